@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/stackgen-cli/devcheck/internal/checker"
+	"github.com/stackgen-cli/devcheck/internal/config"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with .devcheck.yaml configuration files",
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate [path]",
+	Short: "Validate a .devcheck.yaml file",
+	Long: `Validate a .devcheck.yaml file without running a scan.
+
+The path may be a config file or a directory to look for one in (default
+the current directory). Validation rejects unknown fields and wrong types,
+custom_rules patterns that don't compile, unknown severities, malformed
+tool_versions and custom codes that reuse a built-in code. Exits 1 if the
+config is invalid.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runConfigValidate,
+}
+
+func init() {
+	configCmd.AddCommand(configValidateCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("path not found: %s", path)
+	}
+	if info.IsDir() {
+		found := config.Find(path)
+		if found == "" {
+			return fmt.Errorf("no .devcheck.yaml found in %s", path)
+		}
+		path = found
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	problems := config.Validate(data, func(code string) bool {
+		_, ok := checker.LookupCheck(code)
+		return ok
+	})
+	if len(problems) == 0 {
+		color.Green("✓ %s is valid", path)
+		return nil
+	}
+
+	color.Red("✗ %s has %d problem(s):", path, len(problems))
+	for _, p := range problems {
+		fmt.Printf("  %s\n", p.Error())
+	}
+	os.Exit(1)
+	return nil
+}
@@ -3,10 +3,12 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/profiles"
 )
 
 var initConfigCmd = &cobra.Command{
@@ -19,14 +21,21 @@ The configuration file allows you to:
 - Specify minimum tool versions (docker, docker-compose, etc.)
 - List required environment variables
 - Ignore specific finding codes
-- Map build contexts to Dockerfiles`,
+- Map build contexts to Dockerfiles
+
+With --profile, the file is seeded for that profile instead, e.g.
+ci ignores informational codes and strict requires newer tools.`,
 	RunE: runInitConfig,
 }
 
-var initConfigForce bool
+var (
+	initConfigForce   bool
+	initConfigProfile string
+)
 
 func init() {
 	initConfigCmd.Flags().BoolVarP(&initConfigForce, "force", "f", false, "Overwrite existing config file")
+	initConfigCmd.Flags().StringVarP(&initConfigProfile, "profile", "p", "", fmt.Sprintf("Seed the config for a profile (%s)", strings.Join(profiles.List(), ", ")))
 	rootCmd.AddCommand(initConfigCmd)
 }
 
@@ -38,8 +47,17 @@ func runInitConfig(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("%s already exists (use --force to overwrite)", configPath)
 	}
 
+	content := config.ExampleConfig()
+	if initConfigProfile != "" {
+		profile := profiles.Get(initConfigProfile)
+		if profile == nil {
+			return fmt.Errorf("unknown profile: %s (available: %s)", initConfigProfile, strings.Join(profiles.List(), ", "))
+		}
+		content = profile.StarterConfig()
+	}
+
 	// Write example config
-	if err := os.WriteFile(configPath, []byte(config.ExampleConfig()), 0644); err != nil {
+	if err := os.WriteFile(configPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
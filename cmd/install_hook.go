@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/stackgen-cli/devcheck/internal/git"
+)
+
+// Markers delimit the devcheck block so it can share a hook with other tools
+const (
+	hookBeginMarker = "# >>> devcheck >>>"
+	hookEndMarker   = "# <<< devcheck <<<"
+)
+
+var hookBlock = hookBeginMarker + `
+devcheck scan --fail-on warning --changed-only --no-color || exit 1
+` + hookEndMarker + "\n"
+
+var (
+	installHookForce     bool
+	installHookUninstall bool
+)
+
+var installHookCmd = &cobra.Command{
+	Use:   "install-hook [path]",
+	Short: "Install a git pre-commit hook that runs devcheck",
+	Long: `Install a git pre-commit hook that runs:
+
+  devcheck scan --fail-on warning --changed-only
+
+so commits fail when changed files have warnings or blocking issues.
+
+An existing pre-commit hook is left alone unless --force is given, in which
+case the devcheck block is appended to it. --uninstall removes the block
+again, deleting the hook if nothing else is left in it.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runInstallHook,
+}
+
+func init() {
+	installHookCmd.Flags().BoolVar(&installHookForce, "force", false, "Append to an existing pre-commit hook")
+	installHookCmd.Flags().BoolVar(&installHookUninstall, "uninstall", false, "Remove the devcheck pre-commit hook")
+	rootCmd.AddCommand(installHookCmd)
+}
+
+func runInstallHook(cmd *cobra.Command, args []string) error {
+	dir := "."
+	if len(args) > 0 {
+		dir = args[0]
+	}
+
+	hooksDir, err := git.HooksDir(dir)
+	if err != nil {
+		return err
+	}
+	hookPath := filepath.Join(hooksDir, "pre-commit")
+
+	existing, err := os.ReadFile(hookPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	content := string(existing)
+
+	if installHookUninstall {
+		return uninstallHook(hookPath, content)
+	}
+
+	switch {
+	case strings.Contains(content, hookBeginMarker):
+		color.Yellow("devcheck is already installed in %s", hookPath)
+		return nil
+	case len(existing) == 0:
+		content = "#!/bin/sh\n" + hookBlock
+	case !installHookForce:
+		return fmt.Errorf("%s already exists (use --force to append devcheck to it)", hookPath)
+	default:
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "\n" + hookBlock
+	}
+
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(hookPath, []byte(content), 0755); err != nil {
+		return fmt.Errorf("failed to write hook: %w", err)
+	}
+	// WriteFile keeps the mode of an existing file
+	if err := os.Chmod(hookPath, 0755); err != nil {
+		return err
+	}
+
+	color.Green("✅ Installed devcheck pre-commit hook in %s", hookPath)
+	return nil
+}
+
+// uninstallHook removes the devcheck block from a hook, deleting the hook
+// when only the shebang remains
+func uninstallHook(hookPath, content string) error {
+	start := strings.Index(content, hookBeginMarker)
+	end := strings.Index(content, hookEndMarker)
+	if start < 0 || end < start {
+		color.Yellow("No devcheck hook found in %s", hookPath)
+		return nil
+	}
+
+	end += len(hookEndMarker)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	remaining := strings.TrimRight(content[:start], "\n") + "\n" + content[end:]
+
+	if strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(remaining), "#!/bin/sh")) == "" {
+		if err := os.Remove(hookPath); err != nil {
+			return err
+		}
+	} else if err := os.WriteFile(hookPath, []byte(remaining), 0755); err != nil {
+		return err
+	}
+
+	color.Green("✅ Removed devcheck pre-commit hook from %s", hookPath)
+	return nil
+}
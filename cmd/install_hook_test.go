@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// hookRepo creates a git repository and returns it with its pre-commit
+// hook path
+func hookRepo(t *testing.T) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "-C", dir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v: %s", err, out)
+	}
+	return dir, filepath.Join(dir, ".git", "hooks", "pre-commit")
+}
+
+// installHook runs install-hook on dir with the given flags
+func installHook(t *testing.T, dir string, force, uninstall bool) error {
+	t.Helper()
+	installHookForce, installHookUninstall = force, uninstall
+	t.Cleanup(func() { installHookForce, installHookUninstall = false, false })
+	return runInstallHook(installHookCmd, []string{dir})
+}
+
+func readHook(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestInstallHook(t *testing.T) {
+	dir, hookPath := hookRepo(t)
+
+	if err := installHook(t, dir, false, false); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	if got, want := readHook(t, hookPath), "#!/bin/sh\n"+hookBlock; got != want {
+		t.Errorf("expected hook %q, got %q", want, got)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(hookPath); err != nil {
+			t.Error(err)
+		} else if info.Mode().Perm() != 0o755 {
+			t.Errorf("expected mode 0755, got %v", info.Mode().Perm())
+		}
+	}
+
+	// Installing again leaves the hook as it is
+	if err := installHook(t, dir, false, false); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+	if got := readHook(t, hookPath); strings.Count(got, hookBeginMarker) != 1 {
+		t.Errorf("expected one devcheck block after reinstalling, got %q", got)
+	}
+
+	// With only the shebang left, uninstalling deletes the hook
+	if err := installHook(t, dir, false, true); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if _, err := os.Stat(hookPath); !os.IsNotExist(err) {
+		t.Errorf("expected the hook to be deleted, got %v", err)
+	}
+}
+
+func TestInstallHookExisting(t *testing.T) {
+	dir, hookPath := hookRepo(t)
+	foreign := "#!/bin/sh\nnpm run lint"
+	if err := os.MkdirAll(filepath.Dir(hookPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(hookPath, []byte(foreign), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := installHook(t, dir, false, false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("expected an existing hook to be refused without --force, got %v", err)
+	}
+	if got := readHook(t, hookPath); got != foreign {
+		t.Errorf("expected the refused hook to be unchanged, got %q", got)
+	}
+
+	if err := installHook(t, dir, true, false); err != nil {
+		t.Fatalf("install --force: %v", err)
+	}
+	if got, want := readHook(t, hookPath), foreign+"\n\n"+hookBlock; got != want {
+		t.Errorf("expected devcheck appended as %q, got %q", want, got)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(hookPath); err != nil {
+			t.Error(err)
+		} else if info.Mode().Perm() != 0o755 {
+			t.Errorf("expected the appended hook to be made executable, got %v", info.Mode().Perm())
+		}
+	}
+
+	// Uninstalling keeps the other tool's commands
+	if err := installHook(t, dir, false, true); err != nil {
+		t.Fatalf("uninstall: %v", err)
+	}
+	if got, want := readHook(t, hookPath), foreign+"\n"; got != want {
+		t.Errorf("expected %q after uninstalling, got %q", want, got)
+	}
+}
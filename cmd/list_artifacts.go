@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/reporter"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+var (
+	listArtifactsFormat  string
+	listArtifactsCompose string
+	listArtifactsEnv     []string
+)
+
+var listArtifactsCmd = &cobra.Command{
+	Use:   "list-artifacts [path]",
+	Short: "Show the files and languages devcheck detects, without running checks",
+	Long: `Print a tree of the artifacts devcheck detects in a project: compose
+files, env files and examples, manifests, README, Makefile and the other
+files checks read, plus the detected languages and package manager.
+
+No checks run. Use it to debug detection, or --format json to dump the
+raw artifacts.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runListArtifacts,
+}
+
+func init() {
+	listArtifactsCmd.Flags().StringVarP(&listArtifactsFormat, "format", "f", "text", "Output format: text, json")
+	listArtifactsCmd.Flags().StringVar(&listArtifactsCompose, "compose", "", "Specify compose file path")
+	listArtifactsCmd.Flags().StringSliceVar(&listArtifactsEnv, "env", nil, "Specify env file(s)")
+	rootCmd.AddCommand(listArtifactsCmd)
+}
+
+func runListArtifacts(cmd *cobra.Command, args []string) error {
+	path := "."
+	if len(args) > 0 {
+		path = args[0]
+	}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return fmt.Errorf("failed to resolve path: %w", err)
+	}
+	if _, err := os.Stat(absPath); err != nil {
+		return fmt.Errorf("path not found: %s", absPath)
+	}
+
+	var fsys vfs.FS
+	if vfs.IsArchive(absPath) {
+		if fsys, err = vfs.OpenArchive(absPath); err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+	}
+
+	cfg, err := config.Load(absPath)
+	if err != nil {
+		cfg = config.DefaultConfig()
+	}
+
+	artifacts := detector.DetectWithOptions(absPath, detector.Options{
+		ComposeOverride: listArtifactsCompose,
+		EnvOverrides:    listArtifactsEnv,
+		IncludePaths:    cfg.ResolveIncludePaths(absPath),
+		FS:              fsys,
+	})
+
+	switch listArtifactsFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(artifacts)
+	case "text":
+		return reporter.WriteArtifactTree(os.Stdout, absPath, artifacts)
+	default:
+		return fmt.Errorf("unknown format %q (available: text, json)", listArtifactsFormat)
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/stackgen-cli/devcheck/internal/checker"
+	"github.com/stackgen-cli/devcheck/internal/config"
+)
+
+var (
+	listChecksFormat string
+	listChecksConfig string
+)
+
+var listChecksCmd = &cobra.Command{
+	Use:   "list-checks",
+	Short: "List all finding codes devcheck can emit",
+	Long: `List every built-in finding code with its default severity and a short
+description, and whether it needs source scanning or --check-tools.
+
+Codes described in code_metadata or emitted by custom_rules in
+.devcheck.yaml are listed after the built-in ones.
+
+Use these codes in ignore_codes in .devcheck.yaml.`,
+	Args: cobra.NoArgs,
+	RunE: runListChecks,
+}
+
+func init() {
+	listChecksCmd.Flags().StringVarP(&listChecksFormat, "format", "f", "text", "Output format: text, json")
+	listChecksCmd.Flags().StringVar(&listChecksConfig, "config", "", "Config file to read custom codes from (default .devcheck.yaml in the current directory)")
+	rootCmd.AddCommand(listChecksCmd)
+}
+
+func runListChecks(cmd *cobra.Command, args []string) error {
+	var cfg *config.Config
+	var err error
+	if listChecksConfig != "" {
+		cfg, err = config.LoadFromFile(listChecksConfig)
+	} else {
+		cfg, err = config.Load(".")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	checks := append(append([]checker.CheckInfo{}, checker.Registry...), checker.CustomChecks(cfg)...)
+
+	switch listChecksFormat {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(checks)
+	case "text":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "CODE\tSEVERITY\tREQUIRES\tDESCRIPTION")
+		for _, info := range checks {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", info.Code, info.Severity, requirements(info), info.Description)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown format %q (available: text, json)", listChecksFormat)
+	}
+}
+
+// requirements describes what a check needs beyond a default scan
+func requirements(info checker.CheckInfo) string {
+	var reqs []string
+	if info.RequiresSourceScan {
+		reqs = append(reqs, "source scan")
+	}
+	if info.RequiresCheckTools {
+		reqs = append(reqs, "--check-tools")
+	}
+	if info.Custom {
+		reqs = append(reqs, "config")
+	}
+	if len(reqs) == 0 {
+		return "-"
+	}
+	return strings.Join(reqs, ", ")
+}
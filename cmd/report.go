@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/reporter"
+)
+
+var (
+	reportInput   string
+	reportFormat  string
+	reportNoColor bool
+	reportMeta    []string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Render a saved JSON report in another format",
+	Long: `Load a report saved with 'devcheck scan --format json' and render it in
+any scan output format, without scanning again. Use it in CI to scan once
+and produce markdown, checklists or metrics in later stages.
+
+Reports from 'scan --json-findings-only' can't be loaded, since they have
+no summary or artifacts.`,
+	Example: `  devcheck scan --format json > report.json
+  devcheck report --input report.json --format markdown > report.md`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().StringVarP(&reportInput, "input", "i", "", "JSON report to render (- reads stdin)")
+	reportCmd.Flags().StringVarP(&reportFormat, "format", "f", "text", "Output format: "+strings.Join(outputFormats, ", "))
+	reportCmd.Flags().StringVar(&groupBy, "group-by", "severity", "Group text and markdown output by: severity, file, category")
+	reportCmd.Flags().BoolVar(&reportNoColor, "no-color", false, "Disable color output")
+	reportCmd.Flags().StringVar(&reportTitle, "report-title", "", "Replace the report title")
+	reportCmd.Flags().StringArrayVar(&reportMeta, "meta", nil, "Add or replace KEY=VALUE metadata (repeatable)")
+	reportCmd.MarkFlagRequired("input")
+	rootCmd.AddCommand(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	if !slices.Contains(outputFormats, reportFormat) {
+		return fmt.Errorf("unknown format %q (available: %s)", reportFormat, strings.Join(outputFormats, ", "))
+	}
+	if groupBy != "severity" && groupBy != "file" && groupBy != "category" {
+		return fmt.Errorf("unknown --group-by value %q (available: severity, file, category)", groupBy)
+	}
+
+	metadata, err := parseMeta(reportMeta)
+	if err != nil {
+		return err
+	}
+
+	report, err := loadReportFile(reportInput)
+	if err != nil {
+		return err
+	}
+	applyReportInfo(report, reportTitle, metadata)
+	return writeReport(reportFormat, os.Stdout, reportNoColor, report, report)
+}
+
+// loadReportFile loads a JSON report from path, or stdin for "-"
+func loadReportFile(path string) (*models.Report, error) {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	report, err := reporter.LoadReport(in)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return report, nil
+}
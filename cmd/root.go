@@ -3,14 +3,16 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
 var (
-	version   = "1.0.0"
-	colorMode string
+	version     = "1.0.0"
+	colorMode   string
+	scanTimeout time.Duration
 )
 
 var rootCmd = &cobra.Command{
@@ -36,6 +38,7 @@ func Execute() {
 
 func init() {
 	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Color output: auto, always, never")
+	rootCmd.PersistentFlags().DurationVar(&scanTimeout, "timeout", 0, "Stop scanning after this long and report partial results (e.g. 30s; 0 = no limit)")
 
 	rootCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
 		switch colorMode {
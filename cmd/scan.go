@@ -1,9 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/fatih/color"
@@ -11,9 +15,13 @@ import (
 	"github.com/stackgen-cli/devcheck/internal/checker"
 	"github.com/stackgen-cli/devcheck/internal/config"
 	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/fixer"
+	"github.com/stackgen-cli/devcheck/internal/git"
 	"github.com/stackgen-cli/devcheck/internal/models"
 	"github.com/stackgen-cli/devcheck/internal/profiles"
 	"github.com/stackgen-cli/devcheck/internal/reporter"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"github.com/stackgen-cli/devcheck/internal/workspace"
 )
 
 var (
@@ -23,15 +31,58 @@ var (
 	strictMode        bool
 	noColor           bool
 	profileName       string
+	profileFile       string
 	checkToolVersions bool
 	configFile        string
 	generateFixList   string
+	fixScript         string
+	applyFixes        bool
+	assumeYes         bool
+	changedOnly       bool
+	stagedOnly        bool
+	sinceRef          string
+	absolutePaths     bool
+	jsonCompact       bool
+	jsonFindingsOnly  bool
+	envPrefix         string
+	failOn            string
+	exitZero          bool
+	requireProject    bool
+	allowPlugins      bool
+	detailedEnvRefs   bool
+	groupBy           string
+	categories        []string
+	dedupFindings     bool
+	blame             bool
+	services          []string
+	maxFindings       int
+	maxFileSize       int64
+	outputFlags       []string
+	reportTitle       string
+	metaFlags         []string
+	blockingToStderr  bool
+	warningsToStderr  bool
+	parallelPaths     bool
+	pathConcurrency   int
 )
 
+// stdinComposeName is the path findings report for a compose file read
+// from stdin with --compose -
+const stdinComposeName = "<stdin>"
+
+// stdinCompose holds the compose file read from stdin with --compose -
+var stdinCompose []byte
+
 var scanCmd = &cobra.Command{
-	Use:   "scan [path]",
+	Use:   "scan [path...]",
 	Short: "Scan a project for local dev readiness",
 	Long: `Scan a project directory for local development readiness issues.
+The path may also be a .tar, .tar.gz, .tgz or .zip archive, which is read
+in memory without extracting it; archives with a file over 64 MiB, or
+over 512 MiB in total once decompressed, are rejected. With several paths, each is scanned and
+the results are combined into one report, with findings prefixed by their
+path; exit codes consider the combined totals. Use --parallel-workspaces
+to scan several paths at once; the report is the same as a serial scan.
 
 Available profiles:
   default  Standard development checks
@@ -40,6 +91,11 @@ Available profiles:
   minimal  Only blocking issues
   full     Full analysis including source code scanning
 
+  Teams can define their own with --profile-file team.yaml, using the
+  fields name, description, min_severity, include_info,
+  enable_source_scanning, enabled_checks, disabled_checks and
+  severity_overrides.
+
 Configuration:
   Create a .devcheck.yaml file to customize rules, required variables,
   tool versions, and ignored checks. See --init-config for an example.
@@ -47,43 +103,257 @@ Configuration:
 Examples:
   devcheck scan
   devcheck scan /path/to/project
+  devcheck scan ./service-a ./service-b
+  devcheck scan --parallel-workspaces services/*
+  devcheck scan project.tar.gz
   devcheck scan --format json
   devcheck scan --strict
   devcheck scan --profile ci
   devcheck scan --check-tools
-  devcheck scan --fix-list fixes.md`,
-	Args: cobra.MaximumNArgs(1),
+  devcheck scan --fix-list fixes.md
+  devcheck scan --fix --yes
+  devcheck scan --changed-only --since origin/main
+  devcheck scan --staged`,
+	Args: cobra.ArbitraryArgs,
 	Run:  runScan,
 }
 
 func init() {
-	scanCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format: text, json, markdown, checklist")
-	scanCmd.Flags().StringVar(&composeFile, "compose", "", "Specify compose file path")
+	scanCmd.Flags().StringVarP(&formatFlag, "format", "f", "text", "Output format: text, json, markdown, checklist, prometheus, script, quickfix, badge")
+	scanCmd.Flags().StringVar(&composeFile, "compose", "", "Specify compose file path, or - to read it from stdin")
 	scanCmd.Flags().StringSliceVar(&envFiles, "env", nil, "Specify env file(s)")
 	scanCmd.Flags().BoolVar(&strictMode, "strict", false, "Exit 1 if blocking findings exist")
+	scanCmd.Flags().StringVar(&failOn, "fail-on", "", "Exit 1 if findings at or above this severity exist: blocking, warning, info")
+	scanCmd.Flags().BoolVar(&allowPlugins, "allow-plugins", false, "Run the external check programs listed in plugins in .devcheck.yaml")
+	scanCmd.Flags().BoolVar(&requireProject, "require-project", false, "Exit 1 if no compose, env or manifest files are found (DET001)")
+	scanCmd.Flags().BoolVar(&exitZero, "exit-zero", false, "Always exit 0 after a completed scan, overriding --strict and --fail-on")
 	scanCmd.Flags().BoolVar(&noColor, "no-color", false, "Disable color output")
+	scanCmd.Flags().BoolVar(&blockingToStderr, "blocking-to-stderr", false, "Write blocking findings to stderr instead of stdout (with --format text)")
+	scanCmd.Flags().BoolVar(&warningsToStderr, "warnings-to-stderr", false, "Write warnings to stderr instead of stdout (with --format text)")
 	scanCmd.Flags().StringVarP(&profileName, "profile", "p", "default", fmt.Sprintf("Check profile (%s)", strings.Join(profiles.List(), ", ")))
+	scanCmd.Flags().StringVar(&profileFile, "profile-file", "", "Load a custom profile from a YAML file instead of --profile")
 	scanCmd.Flags().BoolVar(&checkToolVersions, "check-tools", false, "Check tool versions (docker, docker-compose, etc.)")
 	scanCmd.Flags().StringVar(&configFile, "config", "", "Custom config file path")
 	scanCmd.Flags().StringVar(&generateFixList, "fix-list", "", "Generate fix checklist to file (markdown)")
+	scanCmd.Flags().StringVar(&fixScript, "fix-script", "", "Write a bash fix script to file (review before running)")
+	scanCmd.Flags().BoolVar(&applyFixes, "fix", false, "Apply safe fixes (never overwrites existing files)")
+	scanCmd.Flags().BoolVarP(&assumeYes, "yes", "y", false, "Apply fixes without prompting (with --fix)")
+	scanCmd.Flags().BoolVar(&changedOnly, "changed-only", false, "Only report findings in files changed according to git")
+	scanCmd.Flags().BoolVar(&stagedOnly, "staged", false, "Only report findings in files staged for commit")
+	scanCmd.Flags().StringVar(&sinceRef, "since", "", "Git ref to diff against for --changed-only (default HEAD; implies --changed-only)")
+	scanCmd.Flags().BoolVar(&absolutePaths, "absolute-paths", false, "Report absolute file paths instead of repo-relative ones")
+	scanCmd.Flags().BoolVar(&jsonCompact, "json-compact", false, "Print JSON output on a single line (with --format json)")
+	scanCmd.Flags().BoolVar(&jsonFindingsOnly, "json-findings-only", false, "Print only the findings array as JSON (with --format json)")
+	scanCmd.Flags().StringVar(&envPrefix, "env-prefix", "", "Only apply required_env_vars and custom_rules to variables with this prefix")
+	scanCmd.Flags().BoolVar(&detailedEnvRefs, "detailed-env-refs", false, "Report each undefined compose variable even when no env file exists")
+
+	scanCmd.Flags().StringSliceVar(&services, "services", nil, "Only check these compose services (comma-separated)")
+	scanCmd.Flags().Int64Var(&maxFileSize, "max-file-size", checker.DefaultMaxFileSize, "Skip source files larger than this many bytes during source scanning (0 = no limit)")
+	scanCmd.Flags().IntVar(&maxFindings, "max-findings", 0, "Show at most N findings, most severe first (0 shows all)")
+	scanCmd.Flags().StringArrayVar(&outputFlags, "output", nil, "Also write the report to a file as FORMAT=PATH, e.g. json=report.json (repeatable)")
+	scanCmd.Flags().StringVar(&groupBy, "group-by", "severity", "Group text and markdown output by: severity, file, category")
+	scanCmd.Flags().BoolVar(&dedupFindings, "dedup-findings", false, "Collapse findings with the same code and title into one with several locations")
+	scanCmd.Flags().StringVar(&reportTitle, "report-title", "", "Title for markdown and JSON reports, e.g. the project name")
+	scanCmd.Flags().StringArrayVar(&metaFlags, "meta", nil, "Add KEY=VALUE metadata to markdown and JSON reports, e.g. commit=$GITHUB_SHA (repeatable)")
+	scanCmd.Flags().BoolVar(&blame, "blame", false, "Annotate findings with the commit, author and date that last changed their line (git blame)")
+	scanCmd.Flags().BoolVar(&parallelPaths, "parallel-workspaces", false, "Scan several paths concurrently, one worker per CPU unless --workspace-concurrency is set")
+	scanCmd.Flags().IntVar(&pathConcurrency, "workspace-concurrency", 1, "Scan at most N paths at once (implies --parallel-workspaces when above 1)")
+	scanCmd.Flags().StringSliceVar(&categories, "category", nil, "Only report findings in these categories (comma-separated): "+categoryList())
 
 	rootCmd.AddCommand(scanCmd)
 }
 
 func runScan(cmd *cobra.Command, args []string) {
-	// Get profile
-	profile := profiles.Get(profileName)
-	if profile == nil {
-		color.Red("Unknown profile: %s (available: %s)", profileName, strings.Join(profiles.List(), ", "))
+	// Get profile, from --profile-file if given
+	var profile *profiles.Profile
+	if profileFile != "" {
+		if cmd.Flags().Changed("profile") {
+			color.Red("--profile and --profile-file cannot be used together")
+			os.Exit(2)
+		}
+		var err error
+		profile, err = profiles.LoadFile(profileFile)
+		if err != nil {
+			color.Red("Error loading profile: %v", err)
+			os.Exit(2)
+		}
+	} else {
+		profile = profiles.Get(profileName)
+		if profile == nil {
+			color.Red("Unknown profile: %s (available: %s)", profileName, strings.Join(profiles.List(), ", "))
+			os.Exit(2)
+		}
+	}
+
+	if stagedOnly && (changedOnly || sinceRef != "") {
+		color.Red("--staged cannot be combined with --changed-only or --since")
+		os.Exit(2)
+	}
+
+	if failOn != "" && models.SeverityLevel(models.Severity(failOn)) == 0 {
+		color.Red("Unknown --fail-on severity: %s (available: blocking, warning, info)", failOn)
+		os.Exit(2)
+	}
+
+	outputs, err := parseOutputs(outputFlags)
+	if err != nil {
+		color.Red("%v", err)
+		os.Exit(2)
+	}
+
+	metadata, err := parseMeta(metaFlags)
+	if err != nil {
+		color.Red("%v", err)
+		os.Exit(2)
+	}
+
+	// --no-color covers status messages too; reporters get it explicitly
+	if noColor {
+		color.NoColor = true
+	}
+
+	if groupBy != "severity" && groupBy != "file" && groupBy != "category" {
+		color.Red("Unknown --group-by value: %s (available: severity, file, category)", groupBy)
+		os.Exit(2)
+	}
+
+	if (blockingToStderr || warningsToStderr) && formatFlag != "text" {
+		color.Red("--blocking-to-stderr and --warnings-to-stderr need --format text")
+		os.Exit(2)
+	}
+
+	if pathConcurrency < 1 {
+		color.Red("--workspace-concurrency must be at least 1")
+		os.Exit(2)
+	}
+
+	for _, category := range categories {
+		if !slices.Contains(models.Categories, models.Category(category)) {
+			color.Red("Unknown --category: %s (available: %s)", category, categoryList())
+			os.Exit(2)
+		}
+	}
+
+	// Read a compose file from stdin once, for every scan path
+	if composeFile == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			color.Red("Error reading compose file from stdin: %v", err)
+			os.Exit(2)
+		}
+		stdinCompose = data
+	}
+
+	// Determine scan paths
+	scanPaths := args
+	if len(scanPaths) == 0 {
+		scanPaths = []string{"."}
+	}
+	if applyFixes && len(scanPaths) > 1 {
+		color.Red("--fix needs a single path")
+		os.Exit(2)
+	}
+
+	// Scan each path, combining several into one report with findings
+	// prefixed by their path. Parallel scans share tool detection and
+	// merge in argument order, so the report matches a serial scan.
+	concurrency := pathConcurrency
+	if parallelPaths && !cmd.Flags().Changed("workspace-concurrency") {
+		concurrency = runtime.NumCPU()
+	}
+	reports := workspace.ScanAll(scanPaths, concurrency, func(scanPath string) *models.Report {
+		return scanProject(scanPath, profile)
+	})
+	prefixes := make([]string, len(scanPaths))
+	for i, scanPath := range scanPaths {
+		prefixes[i] = filepath.Clean(scanPath)
+	}
+	missingProject := projectMissing(reports)
+	report := reports[0]
+	if len(reports) > 1 {
+		report = models.MergeReports(reports, prefixes)
+	}
+	applyReportInfo(report, reportTitle, metadata)
+
+	// Generate fix list if requested
+	if generateFixList != "" {
+		f, err := os.Create(generateFixList)
+		if err != nil {
+			color.Red("Error creating fix list: %v", err)
+			os.Exit(2)
+		}
+		defer f.Close()
+
+		r := reporter.NewChecklistReporter(f)
+		if err := r.Report(report); err != nil {
+			color.Red("Error generating fix list: %v", err)
+			os.Exit(2)
+		}
+		color.Green("Fix checklist written to %s", generateFixList)
+	}
+
+	// Generate fix script if requested
+	if fixScript != "" {
+		if err := os.WriteFile(fixScript, []byte(reporter.GenerateShellScript(report)), 0755); err != nil {
+			color.Red("Error writing fix script: %v", err)
+			os.Exit(2)
+		}
+		// WriteFile keeps the mode of an existing file
+		if err := os.Chmod(fixScript, 0755); err != nil {
+			color.Red("Error writing fix script: %v", err)
+			os.Exit(2)
+		}
+		color.Green("Fix script written to %s", fixScript)
+	}
+
+	// Only displayed output is capped; fixes, metrics, scripts and exit
+	// codes see every finding
+	shown := report.Truncated(maxFindings)
+
+	// Build the report once and fan it out: --format to stdout, then each
+	// --output to its file
+	if err := writeReport(formatFlag, os.Stdout, noColor, report, shown); err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating %s: %v\n", formatFlag, err)
 		os.Exit(2)
 	}
+	for _, out := range outputs {
+		if err := writeReportFile(out, report, shown); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", out.path, err)
+			os.Exit(2)
+		}
+		color.New(color.FgGreen).Fprintf(os.Stderr, "%s report written to %s\n", out.format, out.path)
+	}
 
-	// Determine scan path
-	scanPath := "."
-	if len(args) > 0 {
-		scanPath = args[0]
+	// Apply safe fixes if requested
+	if applyFixes {
+		result := fixer.Apply(report.Path, report.Findings, fixer.Options{
+			AssumeYes: assumeYes,
+			In:        os.Stdin,
+			Out:       os.Stderr,
+		})
+		fmt.Fprintln(os.Stderr)
+		result.WriteSummary(os.Stderr)
 	}
 
+	// Exit code handling; --exit-zero only changes the status, the report
+	// is already written
+	if exitZero {
+		return
+	}
+	if strictMode && report.Summary.BlockingCount > 0 {
+		os.Exit(1)
+	}
+	if failOn != "" && hasSeverityAtLeast(report.Findings, models.Severity(failOn)) {
+		os.Exit(1)
+	}
+	if requireProject && missingProject {
+		os.Exit(1)
+	}
+}
+
+// scanProject scans one path and returns its report, exiting with status 2
+// on invalid input
+func scanProject(scanPath string, profile *profiles.Profile) *models.Report {
 	// Resolve to absolute path
 	absPath, err := filepath.Abs(scanPath)
 	if err != nil {
@@ -97,6 +367,22 @@ func runScan(cmd *cobra.Command, args []string) {
 		os.Exit(2)
 	}
 
+	// Archives are scanned in memory without extracting them
+	var fsys vfs.FS
+	if vfs.IsArchive(absPath) {
+		// Plugins run as programs in the project directory, which an
+		// archive doesn't have on disk
+		if applyFixes || changedOnly || sinceRef != "" || stagedOnly || blame || allowPlugins {
+			color.Red("--fix, --changed-only, --staged, --blame and --allow-plugins need a project directory, not an archive; extract it first")
+			os.Exit(2)
+		}
+		fsys, err = vfs.OpenArchive(absPath)
+		if err != nil {
+			color.Red("Error reading archive: %v", err)
+			os.Exit(2)
+		}
+	}
+
 	// Load config
 	var cfg *config.Config
 	if configFile != "" {
@@ -107,28 +393,115 @@ func runScan(cmd *cobra.Command, args []string) {
 			os.Exit(2)
 		}
 	} else {
-		// Try to load from project directory
-		cfg, err = config.Load(absPath)
+		// Try to load from project directory, or from inside the archive
+		if fsys != nil {
+			cfg, err = config.LoadFS(fsys)
+		} else {
+			cfg, err = config.Load(absPath)
+		}
 		if err != nil {
 			color.Yellow("Warning: could not load config: %v", err)
 			cfg = config.DefaultConfig()
 		}
 	}
 
+	// A custom rule under a built-in code would be reported as that check
+	if codes := cfg.BuiltinCodes(func(code string) bool {
+		_, ok := checker.LookupCheck(code)
+		return ok
+	}); len(codes) > 0 {
+		color.Red("Error loading config: custom_rules or code_metadata reuse built-in code(s) %s; rename them (see devcheck config validate)", strings.Join(codes, ", "))
+		os.Exit(2)
+	}
+
+	if envPrefix != "" {
+		cfg.EnvPrefix = envPrefix
+	}
+
+	// A compose file from stdin is served as <stdin>, with paths in it
+	// resolved against the project
+	composeOverride := composeFile
+	if composeFile == "-" {
+		base := fsys
+		if base == nil {
+			base = vfs.OS(absPath)
+		}
+		fsys = vfs.WithFile(base, stdinComposeName, stdinCompose)
+		composeOverride = stdinComposeName
+	}
+
 	// Detect artifacts
-	artifacts := detector.Detect(absPath, composeFile, envFiles)
+	artifacts := detector.DetectWithOptions(absPath, detector.Options{
+		ComposeOverride: composeOverride,
+		EnvOverrides:    envFiles,
+		IncludePaths:    cfg.ResolveIncludePaths(absPath),
+		FS:              fsys,
+	})
 
 	// Run checks with profile options
 	opts := checker.Options{
 		EnableSourceScanning: profile.EnableSourceScanning,
 		Config:               cfg,
 		CheckToolVersions:    checkToolVersions,
+		DetailedEnvRefs:      detailedEnvRefs,
+		MaxFileSize:          maxFileSize,
+		Services:             services,
+		FS:                   fsys,
+		AllowPlugins:         allowPlugins,
+	}
+	if maxFileSize == 0 {
+		opts.MaxFileSize = -1
 	}
-	findings := checker.CheckWithOptions(absPath, artifacts, opts)
+	ctx := context.Background()
+	if scanTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, scanTimeout)
+		defer cancel()
+	}
+	findings := checker.CheckWithContext(ctx, absPath, artifacts, opts)
 
 	// Filter findings based on profile
 	findings = profile.FilterFindings(findings)
 
+	// Limit to files changed in git if requested
+	if changedOnly || sinceRef != "" {
+		ref := sinceRef
+		if ref == "" {
+			ref = "HEAD"
+		}
+		changed, err := git.ChangedFiles(absPath, ref)
+		if err != nil {
+			color.Red("--changed-only: %v", err)
+			os.Exit(2)
+		}
+		findings = filterByFiles(findings, changed)
+	}
+
+	// Limit to files staged for commit if requested
+	if stagedOnly {
+		staged, err := git.StagedFiles(absPath)
+		if err != nil {
+			color.Red("--staged: %v", err)
+			os.Exit(2)
+		}
+		findings = filterByFiles(findings, staged)
+	}
+
+	// Limit to the requested categories
+	if len(categories) > 0 {
+		findings = filterByCategory(findings, categories)
+	}
+
+	// Collapse repeated findings into one with several locations
+	if dedupFindings {
+		findings = models.DedupFindings(findings)
+	}
+
+	// Annotate findings with the commit that last changed their line
+	if blame {
+		annotateBlame(findings, absPath)
+	}
+
 	// Create report
 	report := &models.Report{
 		Path:      absPath,
@@ -136,56 +509,254 @@ func runScan(cmd *cobra.Command, args []string) {
 		Findings:  findings,
 	}
 
-	// Calculate summary
+	// Calculate summary and readiness score
 	report.CalculateSummary()
+	report.CalculateScoreWithWeights(scoreWeights(cfg))
 
-	// Generate fix list if requested
-	if generateFixList != "" {
-		f, err := os.Create(generateFixList)
-		if err != nil {
-			color.Red("Error creating fix list: %v", err)
-			os.Exit(2)
+	// After CalculateSummary so fingerprints use repo-relative paths
+	if absolutePaths {
+		absolutizePaths(findings, absPath)
+	}
+
+	return report
+}
+
+// scoreWeights returns the readiness score weights, with score_weights
+// from config overriding the defaults
+func scoreWeights(cfg *config.Config) models.ScoreWeights {
+	weights := models.DefaultScoreWeights
+	if cfg.ScoreWeights == nil {
+		return weights
+	}
+	if w := cfg.ScoreWeights.Blocking; w != nil {
+		weights.Blocking = *w
+	}
+	if w := cfg.ScoreWeights.Warning; w != nil {
+		weights.Warning = *w
+	}
+	if w := cfg.ScoreWeights.Info; w != nil {
+		weights.Info = *w
+	}
+	return weights
+}
+
+// hasSeverityAtLeast checks if any finding is at or above severity
+func hasSeverityAtLeast(findings []*models.Finding, severity models.Severity) bool {
+	for _, f := range findings {
+		if models.SeverityLevel(f.Severity) >= models.SeverityLevel(severity) {
+			return true
 		}
-		defer f.Close()
+	}
+	return false
+}
 
-		r := reporter.NewChecklistReporter(f)
-		if err := r.Report(report); err != nil {
-			color.Red("Error generating fix list: %v", err)
-			os.Exit(2)
+// projectMissing reports whether any scanned path had no compose, env or
+// manifest files. It looks at the artifacts rather than for DET001, which
+// a profile, --category or the git filters may have dropped.
+func projectMissing(reports []*models.Report) bool {
+	for _, r := range reports {
+		if !r.Artifacts.HasProject() {
+			return true
 		}
-		color.Green("Fix checklist written to %s", generateFixList)
 	}
+	return false
+}
 
-	// Output based on format
-	switch formatFlag {
-	case "json":
-		r := reporter.NewJSONReporter(os.Stdout, true)
-		if err := r.Report(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating JSON: %v\n", err)
-			os.Exit(2)
+// annotateBlame sets Blame on findings whose primary location has a line,
+// running git blame once per file. Findings outside a git repository, in
+// untracked files or on uncommitted lines are left as they are.
+func annotateBlame(findings []*models.Finding, basePath string) {
+	blamed := make(map[string]map[int]git.LineBlame)
+	for _, f := range findings {
+		if len(f.Files) == 0 || f.Files[0].Line == 0 {
+			continue
 		}
-	case "markdown":
-		r := reporter.NewMarkdownReporter(os.Stdout)
-		if err := r.Report(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating markdown: %v\n", err)
-			os.Exit(2)
+		loc := f.Files[0]
+		lines, ok := blamed[loc.File]
+		if !ok {
+			lines, _ = git.BlameFile(basePath, loc.File)
+			blamed[loc.File] = lines
 		}
-	case "checklist":
-		r := reporter.NewChecklistReporter(os.Stdout)
-		if err := r.Report(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating checklist: %v\n", err)
-			os.Exit(2)
+		if b, ok := lines[loc.Line]; ok {
+			f.Blame = &models.Blame{Commit: b.Commit, Author: b.Author, Date: b.Date}
 		}
+	}
+}
+
+// absolutizePaths rewrites repo-relative finding paths to absolute ones
+func absolutizePaths(findings []*models.Finding, basePath string) {
+	for _, f := range findings {
+		for i := range f.Files {
+			if !filepath.IsAbs(f.Files[i].File) {
+				f.Files[i].File = filepath.Join(basePath, f.Files[i].File)
+			}
+		}
+	}
+}
+
+// filterByFiles keeps findings whose primary file is in files. Findings
+// without a file location (e.g. a missing .env) are always kept.
+func filterByFiles(findings []*models.Finding, files []string) []*models.Finding {
+	set := make(map[string]bool, len(files))
+	for _, f := range files {
+		set[filepath.Clean(f)] = true
+	}
+
+	var filtered []*models.Finding
+	for _, f := range findings {
+		if len(f.Files) == 0 || set[filepath.Clean(f.Files[0].File)] {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// filterByCategory keeps findings in one of categories
+func filterByCategory(findings []*models.Finding, categories []string) []*models.Finding {
+	var filtered []*models.Finding
+	for _, f := range findings {
+		if slices.Contains(categories, string(f.Category)) {
+			filtered = append(filtered, f)
+		}
+	}
+	return filtered
+}
+
+// categoryList returns the finding categories as a comma-separated list
+func categoryList() string {
+	names := make([]string, len(models.Categories))
+	for i, c := range models.Categories {
+		names[i] = string(c)
+	}
+	return strings.Join(names, ", ")
+}
+
+// outputFormats are the formats --format and --output accept
+var outputFormats = []string{"text", "json", "markdown", "checklist", "prometheus", "script", "quickfix", "badge"}
+
+// outputFile is an --output FORMAT=PATH entry
+type outputFile struct {
+	format string
+	path   string
+}
+
+// parseOutputs parses --output FORMAT=PATH values
+func parseOutputs(values []string) ([]outputFile, error) {
+	var result []outputFile
+	for _, value := range values {
+		format, path, ok := strings.Cut(value, "=")
+		if !ok || path == "" {
+			return nil, fmt.Errorf("invalid --output %q (expected FORMAT=PATH, e.g. json=report.json)", value)
+		}
+		if !slices.Contains(outputFormats, format) {
+			return nil, fmt.Errorf("unknown --output format %q (available: %s)", format, strings.Join(outputFormats, ", "))
+		}
+		result = append(result, outputFile{format: format, path: path})
+	}
+	return result, nil
+}
+
+// parseMeta parses --meta KEY=VALUE entries
+func parseMeta(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	meta := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("invalid --meta %q (expected KEY=VALUE)", value)
+		}
+		meta[strings.TrimSpace(key)] = val
+	}
+	return meta, nil
+}
+
+// applyReportInfo sets the report title and adds metadata, keeping any
+// the report already has unless a key is given again
+func applyReportInfo(report *models.Report, title string, metadata map[string]string) {
+	if title != "" {
+		report.Title = title
+	}
+	if len(metadata) > 0 && report.Metadata == nil {
+		report.Metadata = make(map[string]string, len(metadata))
+	}
+	for key, value := range metadata {
+		report.Metadata[key] = value
+	}
+}
+
+// newReporter creates the reporter for a format; unknown formats get text
+func newReporter(format string, w io.Writer, noColor bool) reporter.Reporter {
+	switch format {
+	case "json":
+		r := reporter.NewJSONReporter(w, !jsonCompact)
+		r.FindingsOnly = jsonFindingsOnly
+		return r
+	case "markdown":
+		r := reporter.NewMarkdownReporter(w)
+		r.GroupByFile = groupBy == "file"
+		r.GroupByCategory = groupBy == "category"
+		return r
+	case "checklist":
+		return reporter.NewChecklistReporter(w)
+	case "prometheus":
+		return reporter.NewPrometheusReporter(w)
+	case "script":
+		return reporter.NewScriptReporter(w)
+	case "quickfix":
+		return reporter.NewQuickfixReporter(w)
+	case "badge":
+		return reporter.NewBadgeReporter(w)
 	default:
-		r := reporter.NewTextReporter(os.Stdout, noColor)
-		if err := r.Report(report); err != nil {
-			fmt.Fprintf(os.Stderr, "Error generating text: %v\n", err)
-			os.Exit(2)
+		r := reporter.NewTextReporter(w, noColor)
+		r.GroupByFile = groupBy == "file"
+		r.GroupByCategory = groupBy == "category"
+		// Only stdout output is split; --output files keep every finding
+		if w == os.Stdout {
+			r.SeverityWriters = stderrSeverities()
 		}
+		return r
 	}
+}
 
-	// Exit code handling
-	if strictMode && report.Summary.BlockingCount > 0 {
-		os.Exit(1)
+// stderrSeverities maps the severities routed to stderr by
+// --blocking-to-stderr and --warnings-to-stderr
+func stderrSeverities() map[models.Severity]io.Writer {
+	writers := make(map[models.Severity]io.Writer)
+	if blockingToStderr {
+		writers[models.SeverityBlocking] = os.Stderr
+	}
+	if warningsToStderr {
+		writers[models.SeverityWarning] = os.Stderr
+	}
+	return writers
+}
+
+// writeReport renders report in format to w. Metrics, fix scripts and
+// quickfixes always cover every finding; other formats get the
+// --max-findings view.
+func writeReport(format string, w io.Writer, noColor bool, report, shown *models.Report) error {
+	r := newReporter(format, w, noColor)
+	if format == "prometheus" || format == "script" || format == "quickfix" {
+		return r.Report(report)
+	}
+	return r.Report(shown)
+}
+
+// writeReportFile renders an --output entry to its file, without color
+func writeReportFile(out outputFile, report, shown *models.Report) error {
+	f, err := os.Create(out.path)
+	if err != nil {
+		return err
+	}
+
+	err = writeReport(out.format, f, true, report, shown)
+	if err == nil && out.format == "script" {
+		err = f.Chmod(0755)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
 	}
+	return err
 }
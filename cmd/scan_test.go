@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/checker"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/profiles"
+)
+
+func TestProjectMissingIgnoresFilters(t *testing.T) {
+	empty := t.TempDir()
+	artifacts := detector.Detect(empty, "", nil)
+	findings := checker.Check(empty, artifacts)
+
+	tests := []struct {
+		name   string
+		filter func([]*models.Finding) []*models.Finding
+	}{
+		{"minimal profile", profiles.Get("minimal").FilterFindings},
+		{"env category", func(fs []*models.Finding) []*models.Finding { return filterByCategory(fs, []string{"env"}) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &models.Report{Path: empty, Artifacts: artifacts, Findings: tt.filter(findings)}
+			for _, f := range report.Findings {
+				if f.Code == "DET001" {
+					t.Fatalf("expected the filter to drop DET001")
+				}
+			}
+			if !projectMissing([]*models.Report{report}) {
+				t.Error("expected the project to be missing")
+			}
+		})
+	}
+
+	project := detector.Detect("../internal/checker/testdata/healthcheck-env", "", nil)
+	if projectMissing([]*models.Report{{Artifacts: project}}) {
+		t.Error("expected a compose project not to be missing")
+	}
+	if !projectMissing([]*models.Report{{Artifacts: project}, {Artifacts: artifacts}}) {
+		t.Error("expected one empty path among several to count as missing")
+	}
+}
+
+func TestParseMeta(t *testing.T) {
+	meta, err := parseMeta([]string{"commit=abc123", " branch =main", "note=a=b", "empty="})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]string{"commit": "abc123", "branch": "main", "note": "a=b", "empty": ""}
+	if !reflect.DeepEqual(meta, expected) {
+		t.Errorf("expected %v, got %v", expected, meta)
+	}
+
+	for _, bad := range []string{"commit", "=abc123", "  =x"} {
+		if _, err := parseMeta([]string{"ok=1", bad}); err == nil {
+			t.Errorf("expected an error for --meta %q", bad)
+		}
+	}
+
+	if meta, err := parseMeta(nil); meta != nil || err != nil {
+		t.Errorf("expected no metadata, got %v, %v", meta, err)
+	}
+}
+
+func TestApplyReportInfo(t *testing.T) {
+	report := &models.Report{Title: "Old", Metadata: map[string]string{"commit": "abc123", "branch": "main"}}
+	applyReportInfo(report, "", map[string]string{"branch": "feature"})
+	if report.Title != "Old" {
+		t.Errorf("expected an empty title to keep %q, got %q", "Old", report.Title)
+	}
+	expected := map[string]string{"commit": "abc123", "branch": "feature"}
+	if !reflect.DeepEqual(report.Metadata, expected) {
+		t.Errorf("expected %v, got %v", expected, report.Metadata)
+	}
+
+	report = &models.Report{}
+	applyReportInfo(report, "Nightly", map[string]string{"run": "42"})
+	if report.Title != "Nightly" || report.Metadata["run"] != "42" {
+		t.Errorf("expected title and metadata to be set, got %q and %v", report.Title, report.Metadata)
+	}
+}
+
+// writeProjectTarGz archives files under a single top-level directory
+func writeProjectTarGz(t *testing.T, path string, files map[string]string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: "proj/" + name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanProjectArchiveConfig(t *testing.T) {
+	files := map[string]string{
+		"compose.yaml": "services:\n  app:\n    image: app\n    environment:\n      - TOKEN=${TOKEN}\n",
+	}
+	dir := t.TempDir()
+	plain := filepath.Join(dir, "plain.tar.gz")
+	writeProjectTarGz(t, plain, files)
+
+	files[".devcheck.yaml"] = "ignore_codes:\n  - ENV018\n"
+	configured := filepath.Join(dir, "configured.tar.gz")
+	writeProjectTarGz(t, configured, files)
+
+	profile := profiles.Get("default")
+	if n := countByCode(scanProject(plain, profile).Findings, "ENV018"); n != 1 {
+		t.Fatalf("expected ENV018 without a config, got %d", n)
+	}
+	if n := countByCode(scanProject(configured, profile).Findings, "ENV018"); n != 0 {
+		t.Errorf("expected the archive's ignore_codes to suppress ENV018, got %d", n)
+	}
+}
+
+func countByCode(findings []*models.Finding, code string) int {
+	n := 0
+	for _, f := range findings {
+		if f.Code == code {
+			n++
+		}
+	}
+	return n
+}
+
+func TestFilterByFiles(t *testing.T) {
+	findings := []*models.Finding{
+		models.NewFinding("ENV001", models.SeverityWarning, "in changed file").WithFile(filepath.Join("api", ".env"), 2),
+		models.NewFinding("ENV002", models.SeverityWarning, "in unchanged file").WithFile("compose.yaml", 4),
+		models.NewFinding("TOOL001", models.SeverityBlocking, "without a file"),
+	}
+
+	filtered := filterByFiles(findings, []string{"./api/.env"})
+	var titles []string
+	for _, f := range filtered {
+		titles = append(titles, f.Title)
+	}
+	expected := []string{"in changed file", "without a file"}
+	if !reflect.DeepEqual(titles, expected) {
+		t.Errorf("expected %v, got %v", expected, titles)
+	}
+}
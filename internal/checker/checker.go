@@ -2,15 +2,21 @@ package checker
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/stackgen-cli/devcheck/internal/config"
 	"github.com/stackgen-cli/devcheck/internal/models"
 	"github.com/stackgen-cli/devcheck/internal/tools"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
 	"gopkg.in/yaml.v3"
 )
 
@@ -19,83 +25,355 @@ type Options struct {
 	EnableSourceScanning bool
 	Config               *config.Config
 	CheckToolVersions    bool
+
+	// DetailedEnvRefs keeps one ENV001 per variable even when no env file
+	// exists, instead of summarizing them as a single ENV018
+	DetailedEnvRefs bool
+
+	// MaxFileSize skips source files larger than this many bytes, such as
+	// minified bundles, during source scanning. Zero uses
+	// DefaultMaxFileSize; a negative value disables the limit.
+	MaxFileSize int64
+
+	// Services limits compose checks to these services; empty checks all.
+	// References into other services are still resolved.
+	Services []string
+
+	// FS is the project tree to check; defaults to basePath on disk.
+	// basePath is still used to make reported paths relative.
+	FS vfs.FS
+
+	// AllowPlugins runs the plugins listed in Config. Off by default, since
+	// a project's .devcheck.yaml could otherwise run any program on scan;
+	// configured plugins are then reported as PLUGIN002 instead.
+	AllowPlugins bool
+
+	// OnFinding, if set, is called with each finding as soon as its check
+	// reports it, after ignore_codes filtering but before any profile
+	// filtering. Source scanning reports findings file by file, as it
+	// walks the project. Returning false stops the scan: remaining checks
+	// are skipped and the findings passed so far are returned. Calls are
+	// serialized, but may come from internal goroutines when checks run
+	// concurrently, so the callback should return quickly.
+	OnFinding func(*models.Finding) bool
 }
 
+// DefaultMaxFileSize is the source scanning file size limit when
+// Options.MaxFileSize is zero
+const DefaultMaxFileSize = 1 << 20
+
 // Check runs all checks against the detected artifacts
 func Check(basePath string, artifacts *models.Artifacts) []*models.Finding {
 	return CheckWithOptions(basePath, artifacts, Options{})
 }
 
+// CheckFS runs all checks against a project in an fs.FS, such as an
+// fstest.MapFS. It replaces opts.FS; reported paths are relative to the
+// root of fsys.
+func CheckFS(fsys fs.FS, artifacts *models.Artifacts, opts Options) []*models.Finding {
+	opts.FS = vfs.FromFS(fsys)
+	return CheckWithOptions(".", artifacts, opts)
+}
+
 // CheckWithOptions runs all checks with configurable options
 func CheckWithOptions(basePath string, artifacts *models.Artifacts, opts Options) []*models.Finding {
-	var findings []*models.Finding
+	return CheckWithContext(context.Background(), basePath, artifacts, opts)
+}
+
+// CheckWithContext runs all checks until ctx is done. A cancelled or timed
+// out scan stops walking source files, skips the remaining slow checks and
+// returns the findings collected so far plus a SCAN001 warning. A scan
+// stopped by Options.OnFinding returns without the warning.
+func CheckWithContext(ctx context.Context, basePath string, artifacts *models.Artifacts, opts Options) []*models.Finding {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	c := &collector{basePath: basePath, cfg: opts.Config, onFinding: opts.OnFinding, stop: cancel}
+
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = vfs.OS(basePath)
+	}
+
+	// Parse env files once; most checks only need the defined names
+	definedVars := collectDefinedVars(fsys, artifacts)
+
+	// Parse compose files once for the structural compose checks
+	composeDocs, composeErrors := loadComposeDocs(fsys, artifacts)
+
+	// Honor "# devcheck:" directives on compose services
+	c.suppressions = composeSuppressions(composeDocs, basePath)
+
+	// Limit compose checks to the selected services
+	filter := newServiceFilter(opts.Services)
+	c.add(checkUnknownServices(composeDocs, opts.Services)...)
 
 	// Check env vars in compose files
-	findings = append(findings, checkComposeEnvRefs(basePath, artifacts)...)
+	c.add(checkComposeEnvRefs(fsys, artifacts, composeDocs, filter, definedVars, !opts.DetailedEnvRefs && !artifacts.HasEnv())...)
+
+	// Check env example vs env, and which env file wins
+	precedence := envPrecedence(opts.Config)
+	c.add(checkEnvExample(fsys, artifacts, precedence)...)
+	c.add(checkEnvPrecedence(artifacts, precedence)...)
+
+	// Check that .env keeps the key order of .env.example, if enabled
+	if opts.Config != nil && opts.Config.CheckKeyOrder {
+		c.add(checkEnvKeyOrder(fsys, artifacts, precedence)...)
+	}
+
+	// Check env and compose symlinks that point nowhere
+	c.add(checkBrokenLinks(artifacts)...)
+
+	// Check env file encoding
+	c.add(checkEnvEncoding(fsys, artifacts)...)
+
+	// Check env files without a final newline
+	c.add(checkEnvFinalNewline(fsys, artifacts)...)
+
+	// Check keys that override system variables
+	c.add(checkShadowedSystemVars(fsys, artifacts)...)
+
+	// Check JSON values that need quoting
+	c.add(checkEnvStructuredValues(fsys, artifacts)...)
+
+	// Check values with a quote at only one end
+	c.add(checkEnvQuotes(fsys, artifacts)...)
+
+	// Check keys left without a value
+	c.add(checkEmptyEnvValues(fsys, artifacts, opts.Config)...)
+
+	// Check env files that include each other in a loop
+	c.add(checkEnvIncludeCycles(fsys, artifacts, composeDocs, filter)...)
+
+	// Check env values that reference variables nothing defines
+	c.add(checkEnvSelfReferences(fsys, artifacts)...)
+
+	// Check example env files that contain real-looking secrets
+	c.add(checkExampleSecrets(fsys, artifacts)...)
+
+	// Check that the path looks like a project at all
+	c.add(checkProjectDetected(artifacts)...)
 
-	// Check env example vs env
-	findings = append(findings, checkEnvExample(basePath, artifacts)...)
+	// Check env files with secrets that other users can read
+	c.add(checkEnvFilePermissions(fsys, artifacts)...)
+
+	// Check env values that point at project files
+	c.add(checkEnvFilePaths(fsys, artifacts)...)
+
+	// Check host ports published by more than one service
+	c.add(checkPortConflicts(composeDocs, filter)...)
+
+	// Check PORT env values that no published container port matches
+	c.add(checkPortEnvMismatch(fsys, composeDocs, filter)...)
+
+	// Explain which value wins for variables set in several places
+	c.add(checkEnvSourcePrecedence(fsys, composeDocs, filter)...)
+
+	// Check container names used by more than one service
+	c.add(checkContainerNameCollisions(composeDocs, filter)...)
+
+	// Check undefined variables where compose needs a non-empty value
+	c.add(checkRequiredInterpolations(composeDocs, filter, definedVars)...)
+
+	// Check $$VAR references in shell-form healthcheck commands
+	c.add(checkHealthcheckRefs(fsys, composeDocs, filter)...)
 
 	// Check compose depends_on
-	findings = append(findings, checkComposeDependsOn(basePath, artifacts)...)
+	c.add(checkComposeDependsOn(fsys, artifacts, filter)...)
 
 	// Check build contexts (Dockerfile existence)
-	findings = append(findings, checkBuildContexts(basePath, artifacts)...)
+	c.add(checkBuildContexts(fsys, artifacts, filter)...)
+
+	// Check depends_on targets that never start
+	c.add(checkDependsOnDisabled(fsys, composeDocs, filter)...)
+
+	// Check build args that interpolate undefined variables
+	c.add(checkBuildArgRefs(composeDocs, filter, definedVars)...)
+
+	// Check services that both build and name an image
+	c.add(checkBuildWithImage(composeDocs, filter)...)
+
+	// Check build contexts that would copy .env into images
+	c.add(checkDockerignoreEnv(fsys, composeDocs, filter)...)
+
+	// Check Dockerfiles that build from unpinned base images
+	c.add(checkDockerfileBaseImages(fsys, composeDocs, filter)...)
+
+	// Check aliases to undefined anchors, which make compose files unparseable
+	c.add(checkUnknownAnchors(composeErrors)...)
+
+	// Check extends chains that loop
+	c.add(checkExtendsCycles(composeDocs, filter)...)
+
+	// Check service environment entries that shadow .env
+	c.add(checkEnvironmentOverrides(fsys, composeDocs, filter)...)
+
+	// Check env_file values that differ from .env
+	c.add(checkEnvFileConflicts(fsys, composeDocs, filter)...)
+
+	// Check host ports that need elevated privileges
+	c.add(checkPrivilegedPorts(composeDocs, filter)...)
+
+	// Check resource-hungry services without limits
+	c.add(checkResourceLimits(composeDocs, filter)...)
+
+	// Check depends_on on databases that may not be ready yet
+	c.add(checkDependsOnReadiness(composeDocs, filter)...)
+
+	// Check legacy links and links to unknown services
+	c.add(checkLinks(composeDocs, filter)...)
+
+	// Check images that may need docker login
+	c.add(checkPrivateRegistries(composeDocs, filter)...)
+
+	// Check services nothing uses
+	c.add(checkUnreferencedServices(composeDocs, filter)...)
 
 	// Add info findings
-	findings = append(findings, addLanguageInfo(artifacts)...)
+	c.add(addLanguageInfo(artifacts)...)
 
 	// Add run hints from README
-	findings = append(findings, checkReadmeHints(basePath, artifacts)...)
+	c.add(checkReadmeHints(fsys, artifacts, opts.Config)...)
+
+	// Add run hints from Procfile
+	c.add(checkProcfileHints(fsys, artifacts)...)
+
+	// Add run hints from Justfile and Taskfile
+	c.add(checkTaskRunnerHints(fsys, artifacts)...)
+
+	// Check Makefile recipes for env vars nothing defines
+	c.add(checkMakefileEnvRefs(fsys, artifacts, definedVars)...)
+
+	// Check dev container references
+	c.add(checkDevcontainer(fsys, artifacts, definedVars)...)
 
 	// Source code env scanning (if enabled)
 	if opts.EnableSourceScanning {
-		findings = append(findings, checkSourceCodeEnvRefs(basePath, artifacts)...)
+		maxSize := opts.MaxFileSize
+		if maxSize == 0 {
+			maxSize = DefaultMaxFileSize
+		}
+		var ignorePatterns []string
+		if opts.Config != nil {
+			ignorePatterns = opts.Config.IgnorePatterns
+		}
+		checkSourceCodeEnvRefs(ctx, fsys, definedVars, knownExternalVars(opts.Config), maxSize, ignorePatterns, func(f *models.Finding) {
+			c.add(f)
+		})
 	}
 
 	// Tool version checks (if enabled)
-	if opts.CheckToolVersions && opts.Config != nil && opts.Config.ToolVersions != nil {
-		findings = append(findings, checkToolVersions(opts.Config.ToolVersions)...)
+	if opts.CheckToolVersions && opts.Config != nil && opts.Config.ToolVersions != nil && ctx.Err() == nil {
+		c.add(checkToolVersions(opts.Config.ToolVersions)...)
+	}
+
+	// Tools the detected stack needs, without config (if enabled)
+	if opts.CheckToolVersions && ctx.Err() == nil {
+		c.add(checkStackTools(fsys, artifacts, opts.Config)...)
+	}
+
+	// Java build target vs installed JDK (if enabled)
+	if opts.CheckToolVersions && ctx.Err() == nil {
+		c.add(checkJavaVersion(fsys, artifacts)...)
 	}
 
 	// Custom rules from config
 	if opts.Config != nil {
-		findings = append(findings, checkCustomRules(basePath, artifacts, opts.Config)...)
-		findings = append(findings, checkRequiredEnvVars(basePath, artifacts, opts.Config)...)
+		c.add(checkCustomRules(definedVars, opts.Config)...)
+		c.add(checkRequiredEnvVars(definedVars, opts.Config)...)
+		c.add(checkIgnorePatterns(opts.Config)...)
 	}
 
-	// Filter out ignored codes if config provided
-	if opts.Config != nil {
-		findings = filterIgnoredFindings(findings, opts.Config)
+	// External check plugins from config
+	if opts.Config != nil && len(opts.Config.Plugins) > 0 && ctx.Err() == nil {
+		if opts.AllowPlugins {
+			c.add(runPlugins(ctx, basePath, artifacts, opts.Config)...)
+		} else {
+			c.add(skippedPlugins(opts.Config)...)
+		}
 	}
 
-	return findings
+	if err := ctx.Err(); err != nil && !c.stopped {
+		c.add(models.NewFinding(
+			"SCAN001",
+			models.SeverityWarning,
+			"Scan stopped early; results are partial",
+		).WithDetails(fmt.Sprintf("The scan was interrupted (%v) before every check finished, so some issues may be missing", err)).
+			WithFix("Raise --timeout or narrow the scan, e.g. with a smaller path or profile without source scanning"))
+	}
+
+	return c.findings
 }
 
-// checkComposeEnvRefs checks for ${VAR} references in compose files
-func checkComposeEnvRefs(basePath string, artifacts *models.Artifacts) []*models.Finding {
-	var findings []*models.Finding
+// collector gathers findings from checks, dropping ignored codes and
+// findings suppressed by compose directives, normalizing file paths,
+// filling in categories and streaming the rest to Options.OnFinding
+type collector struct {
+	mu        sync.Mutex
+	findings  []*models.Finding
+	basePath  string
+	cfg       *config.Config
+	onFinding func(*models.Finding) bool
+
+	// stop cancels the scan once onFinding returns false; later findings
+	// are dropped
+	stop    context.CancelFunc
+	stopped bool
+
+	// suppressions are the compose services' "# devcheck:" directives
+	suppressions []suppression
+}
 
-	// Collect defined env vars from all env files
-	definedVars := make(map[string]bool)
-	for _, envFile := range artifacts.EnvFiles {
-		if envFile.Found {
-			vars := parseEnvFile(filepath.Join(basePath, envFile.Path))
-			for k := range vars {
-				definedVars[k] = true
-			}
+// add records findings from a check; safe for concurrent use
+func (c *collector) add(findings ...*models.Finding) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, f := range findings {
+		if c.stopped {
+			return
+		}
+		if c.cfg != nil && c.cfg.ShouldIgnoreCode(f.Code) {
+			continue
+		}
+		for i := range f.Files {
+			f.Files[i].File = vfs.RelPath(c.basePath, f.Files[i].File)
+		}
+		if isSuppressed(f, c.suppressions) {
+			continue
+		}
+		if f.Category == "" {
+			f.Category = models.CategoryForCode(f.Code)
+		}
+		c.findings = append(c.findings, f)
+		if c.onFinding != nil && !c.onFinding(f) {
+			c.stopped = true
+			c.stop()
 		}
 	}
+}
+
+// envRef is an undefined variable reference in a compose file
+type envRef struct {
+	Name string
+	File string
+	Line int
+}
 
-	// Parse compose files for ${VAR} references
-	varRefRegex := regexp.MustCompile(`\$\{([^}:]+)(?::-[^}]*)?\}`)
+// undefinedComposeRefs finds ${VAR} and $VAR references in compose files
+// that no env file defines
+func undefinedComposeRefs(fsys vfs.FS, artifacts *models.Artifacts, definedVars map[string]bool) []envRef {
+	var refs []envRef
+
+	isDefined := func(name string) bool {
+		return definedVars[name] || isStandardVar(name)
+	}
 
 	for _, composeFile := range artifacts.ComposeFiles {
 		if !composeFile.Found {
 			continue
 		}
 
-		content, err := os.ReadFile(filepath.Join(basePath, composeFile.Path))
+		content, err := fsys.ReadFile(composeFile.Path)
 		if err != nil {
 			continue
 		}
@@ -105,38 +383,120 @@ func checkComposeEnvRefs(basePath string, artifacts *models.Artifacts) []*models
 		for scanner.Scan() {
 			lineNum++
 			line := scanner.Text()
-			matches := varRefRegex.FindAllStringSubmatch(line, -1)
-			for _, match := range matches {
-				if len(match) > 1 {
-					varName := match[1]
-					if !definedVars[varName] && !isStandardVar(varName) {
-						finding := models.NewFinding(
-							"ENV001",
-							models.SeverityBlocking,
-							fmt.Sprintf("${%s} referenced but not defined", varName),
-						).WithDetails(fmt.Sprintf("Variable ${%s} is used in %s but is not defined in any .env file", varName, composeFile.Path)).
-							WithFile(composeFile.Path, lineNum).
-							WithFix(fmt.Sprintf("Add %s=<value> to .env file", varName))
-
-						findings = append(findings, finding)
-					}
-				}
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			for _, name := range undefinedRefs(line, isDefined) {
+				refs = append(refs, envRef{Name: name, File: composeFile.Path, Line: lineNum})
+			}
+		}
+	}
+
+	return refs
+}
+
+// checkComposeEnvRefs reports undefined compose variable references. With
+// summarize set they are reported as a single ENV018 instead, for when no
+// env file exists and every reference is undefined for the same reason.
+// References inside services excluded by the filter are skipped; ones
+// outside any service may feed a selected service and are kept. A
+// reference inside a service with its own .env in its build context is
+// defined if that file sets it.
+func checkComposeEnvRefs(fsys vfs.FS, artifacts *models.Artifacts, docs []*composeDoc, filter serviceFilter, definedVars map[string]bool, summarize bool) []*models.Finding {
+	var findings []*models.Finding
+
+	docsByPath := make(map[string]*composeDoc)
+	for _, doc := range docs {
+		docsByPath[doc.Path] = doc
+	}
+	localEnv := serviceLocalEnvFiles(fsys, artifacts)
+
+	var refs []envRef
+	for _, ref := range undefinedComposeRefs(fsys, artifacts, definedVars) {
+		if doc := docsByPath[ref.File]; doc != nil {
+			name := serviceAtLine(doc, ref.Line)
+			if name != "" && !filter.includes(name) {
+				continue
+			}
+			if localEnv[name].Vars[ref.Name] {
+				continue
+			}
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == 0 {
+		return findings
+	}
+
+	if summarize {
+		var names []string
+		seen := make(map[string]bool)
+		for _, ref := range refs {
+			if !seen[ref.Name] {
+				seen[ref.Name] = true
+				names = append(names, ref.Name)
 			}
 		}
+
+		return append(findings, models.NewFinding(
+			"ENV018",
+			models.SeverityWarning,
+			fmt.Sprintf("Compose uses %d variables but no .env file found", len(names)),
+		).WithDetails(fmt.Sprintf("Undefined: %s. Use --detailed-env-refs to list each reference.", strings.Join(names, ", "))).
+			WithFile(refs[0].File, refs[0].Line).
+			WithFix("Create .env (or copy .env.example) and define these variables"))
+	}
+
+	for _, ref := range refs {
+		if suggestion := suggestVarName(ref.Name, definedVars); suggestion != "" {
+			findings = append(findings, models.NewFinding(
+				"ENV019",
+				models.SeverityBlocking,
+				fmt.Sprintf("${%s} not defined; did you mean ${%s}?", ref.Name, suggestion),
+			).WithDetails(fmt.Sprintf("Variable ${%s} is used in %s but only %s is defined", ref.Name, ref.File, suggestion)).
+				WithFile(ref.File, ref.Line).
+				WithFix(fmt.Sprintf("Rename ${%s} to ${%s} in %s", ref.Name, suggestion, ref.File)))
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"ENV001",
+			models.SeverityBlocking,
+			fmt.Sprintf("${%s} referenced but not defined", ref.Name),
+		).WithDetails(fmt.Sprintf("Variable ${%s} is used in %s but is not defined in any .env file", ref.Name, ref.File)).
+			WithFile(ref.File, ref.Line).
+			WithFix(fmt.Sprintf("Add %s=<value> to .env file", ref.Name)).
+			WithFixCommand(appendEnvKey(".env", ref.Name)))
 	}
 
 	return findings
 }
 
-// checkEnvExample compares .env.example with .env
-func checkEnvExample(basePath string, artifacts *models.Artifacts) []*models.Finding {
+// collectDefinedVars returns the names defined across all found
+// project-wide env files
+func collectDefinedVars(fsys vfs.FS, artifacts *models.Artifacts) map[string]bool {
+	definedVars := make(map[string]bool)
+	for _, envFile := range artifacts.EnvFiles {
+		if envFile.Found && len(envFile.Services) == 0 {
+			for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+				definedVars[entry.Key] = true
+			}
+		}
+	}
+	return definedVars
+}
+
+// checkEnvExample compares .env.example with the env file that takes
+// precedence
+func checkEnvExample(fsys vfs.FS, artifacts *models.Artifacts, precedence []string) []*models.Finding {
 	var findings []*models.Finding
 
 	// Check if .env.example exists but .env doesn't
 	hasExample := artifacts.HasEnvExample()
 	hasEnv := artifacts.HasEnv()
 
-	if hasExample && !hasEnv {
+	// A dangling .env symlink is reported by ENV021 instead
+	if hasExample && !hasEnv && !hasBrokenLink(artifacts.EnvFiles) {
 		var examplePath string
 		for _, e := range artifacts.EnvExamples {
 			if e.Found {
@@ -150,39 +510,47 @@ func checkEnvExample(basePath string, artifacts *models.Artifacts) []*models.Fin
 			".env.example exists but .env is missing",
 		).WithDetails(fmt.Sprintf("%s exists but no .env file found", examplePath)).
 			WithFile(examplePath, 0).
-			WithFix("Copy .env.example to .env and fill in values"))
+			WithFix("Copy .env.example to .env and fill in values").
+			WithFixCommand(&models.FixCommand{
+				Action: models.FixCopyFile,
+				Path:   ".env",
+				Source: examplePath,
+			}))
 	}
 
 	// Compare keys in .env.example vs .env
 	if hasExample && hasEnv {
-		var exampleVars, envVars map[string]string
+		var exampleEntries []envEntry
+		var envVars map[string]string
 		var examplePath, envPath string
 
 		for _, e := range artifacts.EnvExamples {
 			if e.Found {
 				examplePath = e.Path
-				exampleVars = parseEnvFile(filepath.Join(basePath, e.Path))
+				exampleEntries = parseEnvEntries(fsys, e.Path)
 				break
 			}
 		}
 
-		for _, e := range artifacts.EnvFiles {
-			if e.Found && (e.Path == ".env" || e.Path == ".env.local") {
-				envPath = e.Path
-				envVars = parseEnvFile(filepath.Join(basePath, e.Path))
-				break
-			}
+		if ranked := rankedEnvFiles(artifacts, precedence); len(ranked) > 0 {
+			envPath = ranked[0].Path
+			envVars = parseEnvFile(fsys, envPath)
 		}
 
-		if exampleVars != nil && envVars != nil {
-			for key := range exampleVars {
-				if _, ok := envVars[key]; !ok {
+		if examplePath != "" && envVars != nil {
+			reported := make(map[string]bool)
+			for _, entry := range exampleEntries {
+				key := entry.Key
+				if _, ok := envVars[key]; !ok && !reported[key] {
+					reported[key] = true
 					findings = append(findings, models.NewFinding(
 						"ENV002",
 						models.SeverityWarning,
 						fmt.Sprintf("%s has %s but %s does not", examplePath, key, envPath),
 					).WithDetails(fmt.Sprintf("Variable %s is defined in %s but missing from %s", key, examplePath, envPath)).
-						WithFix(fmt.Sprintf("Add %s=<value> to %s", key, envPath)))
+						WithFix(fmt.Sprintf("Add %s=<value> to %s", key, envPath)).
+						WithFixCommand(appendEnvKey(envPath, key)).
+						WithSection(entry.Section))
 				}
 			}
 		}
@@ -191,8 +559,335 @@ func checkEnvExample(basePath string, artifacts *models.Artifacts) []*models.Fin
 	return findings
 }
 
+// hasBrokenLink checks if any of the artifacts is a dangling symlink
+func hasBrokenLink(files []models.Artifact) bool {
+	for _, f := range files {
+		if f.LinkTarget != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkBrokenLinks flags env and compose files that are symlinks to
+// missing targets, which otherwise just look like missing files
+func checkBrokenLinks(artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	candidates := append(append(append([]models.Artifact{}, artifacts.EnvFiles...), artifacts.EnvExamples...), artifacts.ComposeFiles...)
+	for _, file := range candidates {
+		if file.LinkTarget == "" {
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"ENV021",
+			models.SeverityWarning,
+			fmt.Sprintf("%s is a broken symlink to %s", file.Path, file.LinkTarget),
+		).WithDetails(fmt.Sprintf("%s exists as a symlink, but its target %s does not, so it is treated as missing", file.Path, file.LinkTarget)).
+			WithFile(file.Path, 0).
+			WithFix(fmt.Sprintf("Create %s (e.g. check out the shared config it points at) or re-point the link", file.LinkTarget)))
+	}
+
+	return findings
+}
+
+// checkEnvEncoding flags env files saved with a UTF-8 byte order mark
+func checkEnvEncoding(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	candidates := append(append([]models.Artifact{}, artifacts.EnvFiles...), artifacts.EnvExamples...)
+	for _, envFile := range candidates {
+		if !envFile.Found {
+			continue
+		}
+
+		if !hasBOM(fsys, envFile.Path) {
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"ENV016",
+			models.SeverityWarning,
+			fmt.Sprintf("%s starts with a UTF-8 byte order mark", envFile.Path),
+		).WithDetails("Many env loaders treat the BOM as part of the first variable name, so that variable never matches its references").
+			WithFile(envFile.Path, 1).
+			WithFix(fmt.Sprintf("Re-save %s as UTF-8 without BOM", envFile.Path)))
+	}
+
+	return findings
+}
+
+// checkEnvFinalNewline flags env files without a final newline
+func checkEnvFinalNewline(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	candidates := append(append([]models.Artifact{}, artifacts.EnvFiles...), artifacts.EnvExamples...)
+	for _, envFile := range candidates {
+		if !envFile.Found {
+			continue
+		}
+
+		lines, ok := missingFinalNewline(fsys, envFile.Path)
+		if !ok {
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"ENV020",
+			models.SeverityInfo,
+			fmt.Sprintf("%s does not end with a newline", envFile.Path),
+		).WithDetails("Some tools drop or mis-parse the last line of a file without a trailing newline, and appending to it joins two variables on one line").
+			WithFile(envFile.Path, lines).
+			WithFix(fmt.Sprintf("Add a newline at the end of %s", envFile.Path)))
+	}
+
+	return findings
+}
+
+// systemVarRisks describes what breaks when an env file sourced into a
+// shell overrides a system variable. isStandardVar names without an entry
+// get a generic description.
+var systemVarRisks = map[string]string{
+	"PATH":              "commands stop resolving in that shell",
+	"HOME":              "tools read and write their config under the wrong directory",
+	"SHELL":             "programs that spawn a shell start the wrong one",
+	"UID":               "bash treats UID as read-only, so sourcing the file fails",
+	"GID":               "scripts that rely on the real group id see the wrong one",
+	"IFS":               "word splitting in every later shell command changes",
+	"LD_PRELOAD":        "every program started afterwards loads that library",
+	"LD_LIBRARY_PATH":   "programs may load the wrong shared libraries",
+	"DYLD_LIBRARY_PATH": "programs may load the wrong shared libraries",
+	"TMPDIR":            "temporary files go to an unexpected directory",
+	"LANG":              "tools switch locale, changing sorting and text encoding",
+	"LC_ALL":            "tools switch locale, changing sorting and text encoding",
+}
+
+// checkShadowedSystemVars flags env file keys that override system
+// variables, which breaks a developer's shell when the file is sourced
+func checkShadowedSystemVars(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, envFile := range artifacts.EnvFiles {
+		if !envFile.Found {
+			continue
+		}
+
+		for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+			risk, ok := systemVarRisks[entry.Key]
+			if !ok && !isStandardVar(entry.Key) {
+				continue
+			}
+			if !ok {
+				risk = "tools that rely on the value the system sets see the wrong one"
+			}
+
+			findings = append(findings, models.NewFinding(
+				"ENV024",
+				models.SeverityWarning,
+				fmt.Sprintf("%s sets system variable %s", envFile.Path, entry.Key),
+			).WithDetails(fmt.Sprintf("Sourcing %s into a shell overrides %s: %s", envFile.Path, entry.Key, risk)).
+				WithFile(envFile.Path, entry.Line).
+				WithFix(fmt.Sprintf("Rename %s to a project-specific name such as APP_%s and update its references", entry.Key, entry.Key)))
+		}
+	}
+
+	return findings
+}
+
+// looksLikeJSON reports whether an env value is a JSON object or array
+func looksLikeJSON(value string) bool {
+	return (strings.HasPrefix(value, "{") && strings.HasSuffix(value, "}")) ||
+		(strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]"))
+}
+
+// checkEmptyEnvValues flags keys in .env files with an empty value, which
+// count as defined but usually mean the value was never filled in. Quoted
+// empty values ("") are treated as deliberate unless the variable is in
+// required_env_vars, where any empty value is blocking.
+func checkEmptyEnvValues(fsys vfs.FS, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
+	var findings []*models.Finding
+
+	required := make(map[string]bool)
+	if cfg != nil {
+		for _, name := range cfg.RequiredEnvVars {
+			if cfg.MatchesEnvPrefix(name) {
+				required[name] = true
+			}
+		}
+	}
+
+	for _, envFile := range artifacts.EnvFiles {
+		if !envFile.Found {
+			continue
+		}
+
+		for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+			if entry.Value != "" || (entry.Quoted && !required[entry.Key]) {
+				continue
+			}
+
+			finding := models.NewFinding(
+				"ENV027",
+				models.SeverityWarning,
+				fmt.Sprintf("%s is empty in %s", entry.Key, envFile.Path),
+			).WithDetails(fmt.Sprintf("%s is defined in %s but has no value, so checks treat it as set while the app sees an empty string", entry.Key, envFile.Path))
+			if required[entry.Key] {
+				finding.Severity = models.SeverityBlocking
+				finding.Details = fmt.Sprintf("%s is configured as required in .devcheck.yaml but is empty in %s", entry.Key, envFile.Path)
+			}
+			findings = append(findings, finding.
+				WithFile(envFile.Path, entry.Line).
+				WithFix(fmt.Sprintf("Set a value for %s in %s, or remove the line if it isn't needed", entry.Key, envFile.Path)).
+				WithSection(entry.Section))
+		}
+	}
+
+	return findings
+}
+
+// checkProjectDetected warns when no compose, env or manifest file was
+// found, which usually means devcheck was pointed at the wrong directory
+// and would otherwise report a clean result
+func checkProjectDetected(artifacts *models.Artifacts) []*models.Finding {
+	if artifacts.HasProject() {
+		return nil
+	}
+
+	return []*models.Finding{models.NewFinding(
+		"DET001",
+		models.SeverityWarning,
+		"No recognizable project artifacts found",
+	).WithDetails("No compose file, env file or language manifest was found, so there was nothing to check").
+		WithFix("Check the scan path, or pass --compose and --env explicitly")}
+}
+
+// checkEnvSelfReferences flags env values that interpolate a variable
+// defined neither in the same file nor in the environment, which loaders
+// silently replace with an empty string. Single-quoted values are not
+// interpolated and are skipped.
+func checkEnvSelfReferences(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, envFile := range artifacts.EnvFiles {
+		if !envFile.Found {
+			continue
+		}
+
+		entries := parseEnvEntries(fsys, envFile.Path)
+		inFile := make(map[string]bool, len(entries))
+		for _, entry := range entries {
+			inFile[entry.Key] = true
+		}
+		defined := func(name string) bool {
+			_, inEnv := os.LookupEnv(name)
+			return inFile[name] || inEnv
+		}
+
+		for _, entry := range entries {
+			if entry.Literal {
+				continue
+			}
+			for _, name := range undefinedRefs(entry.Value, defined) {
+				findings = append(findings, models.NewFinding(
+					"ENV025",
+					models.SeverityWarning,
+					fmt.Sprintf("%s in %s references undefined variable %s", entry.Key, envFile.Path, name),
+				).WithDetails(fmt.Sprintf("%s is not defined in %s or the environment, so %s expands it to an empty string", name, envFile.Path, entry.Key)).
+					WithFile(envFile.Path, entry.Line).
+					WithSection(entry.Section).
+					WithFix(fmt.Sprintf("Define %s in %s, or give it a default: ${%s:-value}", name, envFile.Path, name)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkEnvStructuredValues flags unquoted JSON values, whose braces, colons
+// and embedded quotes many env loaders mis-parse
+func checkEnvStructuredValues(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	candidates := append(append([]models.Artifact{}, artifacts.EnvFiles...), artifacts.EnvExamples...)
+	for _, envFile := range candidates {
+		if !envFile.Found {
+			continue
+		}
+
+		for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+			if entry.Quoted || !looksLikeJSON(entry.Value) {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"ENV022",
+				models.SeverityWarning,
+				fmt.Sprintf("%s has an unquoted JSON value", entry.Key),
+			).WithDetails(fmt.Sprintf("%s in %s starts with %q; loaders such as dotenv and docker compose may strip its quotes or stop at special characters", entry.Key, envFile.Path, entry.Value[:1])).
+				WithFile(envFile.Path, entry.Line).
+				WithFix(fmt.Sprintf("Wrap the value in single quotes: %s='%s'", entry.Key, entry.Value)))
+		}
+	}
+
+	return findings
+}
+
+// filePathExtensions are extensions that mark an env value as a file path
+var filePathExtensions = map[string]bool{
+	".json": true, ".pem": true, ".key": true, ".crt": true, ".cert": true,
+	".p12": true, ".pfx": true, ".jks": true, ".yaml": true, ".yml": true,
+	".toml": true, ".ini": true, ".txt": true, ".db": true, ".sqlite": true,
+}
+
+// looksLikeRelativePath reports whether an env value is plausibly a path
+// relative to the project. URLs, absolute paths (usually container paths)
+// and values with spaces or interpolation are never treated as paths.
+func looksLikeRelativePath(value string) bool {
+	if value == "" || strings.Contains(value, "://") || strings.ContainsAny(value, " \t$,;") {
+		return false
+	}
+	if strings.HasPrefix(value, "/") || strings.HasPrefix(value, "~") || filepath.IsAbs(value) {
+		return false
+	}
+	if strings.HasPrefix(value, "./") || strings.HasPrefix(value, "../") {
+		return true
+	}
+	return filePathExtensions[strings.ToLower(filepath.Ext(value))]
+}
+
+// checkEnvFilePaths flags env values that reference project files that don't exist
+func checkEnvFilePaths(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, envFile := range artifacts.EnvFiles {
+		if !envFile.Found {
+			continue
+		}
+
+		for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+			if !looksLikeRelativePath(entry.Value) {
+				continue
+			}
+			if _, err := fsys.Stat(entry.Value); err == nil {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"ENV017",
+				models.SeverityInfo,
+				fmt.Sprintf("%s points to missing file %s", entry.Key, entry.Value),
+			).WithDetails(fmt.Sprintf("%s in %s looks like a path relative to the project, but %s does not exist", entry.Key, envFile.Path, entry.Value)).
+				WithFile(envFile.Path, entry.Line).
+				WithFix(fmt.Sprintf("Create %s or update %s to the correct path", entry.Value, entry.Key)))
+		}
+	}
+
+	return findings
+}
+
 // checkComposeDependsOn validates depends_on references
-func checkComposeDependsOn(basePath string, artifacts *models.Artifacts) []*models.Finding {
+func checkComposeDependsOn(fsys vfs.FS, artifacts *models.Artifacts, filter serviceFilter) []*models.Finding {
 	var findings []*models.Finding
 
 	for _, composeFile := range artifacts.ComposeFiles {
@@ -200,7 +895,7 @@ func checkComposeDependsOn(basePath string, artifacts *models.Artifacts) []*mode
 			continue
 		}
 
-		content, err := os.ReadFile(filepath.Join(basePath, composeFile.Path))
+		content, err := fsys.ReadFile(composeFile.Path)
 		if err != nil {
 			continue
 		}
@@ -215,14 +910,22 @@ func checkComposeDependsOn(basePath string, artifacts *models.Artifacts) []*mode
 			continue
 		}
 
-		// Collect all service names
+		// Collect all service names, sorted so findings come out in a stable
+		// order
 		serviceNames := make(map[string]bool)
+		var names []string
 		for name := range compose.Services {
 			serviceNames[name] = true
+			names = append(names, name)
 		}
+		sort.Strings(names)
 
 		// Check depends_on references
-		for svcName, svc := range compose.Services {
+		for _, svcName := range names {
+			svc := compose.Services[svcName]
+			if !filter.includes(svcName) {
+				continue
+			}
 			deps := extractDependsOn(&svc.DependsOn)
 			for _, dep := range deps {
 				if !serviceNames[dep] {
@@ -258,24 +961,52 @@ func addLanguageInfo(artifacts *models.Artifacts) []*models.Finding {
 		))
 	}
 
+	if len(artifacts.DetectedLanguages) > 1 {
+		names := make([]string, 0, len(artifacts.DetectedLanguages))
+		for _, lang := range artifacts.DetectedLanguages {
+			names = append(names, lang.DisplayName())
+		}
+
+		findings = append(findings, models.NewFinding(
+			"LANG003",
+			models.SeverityInfo,
+			fmt.Sprintf("Detected %s", strings.Join(names, " + ")),
+		).WithDetails(fmt.Sprintf("Polyglot repository: %d languages have manifests; %s is treated as primary", len(names), artifacts.DetectedLang.DisplayName())))
+	}
+
 	return findings
 }
 
-// checkReadmeHints scans README for run instructions
-func checkReadmeHints(basePath string, artifacts *models.Artifacts) []*models.Finding {
+// checkReadmeHints scans README for run instructions. Every readme_hints
+// entry from config that appears is reported; of the built-in commands only
+// the first match is.
+func checkReadmeHints(fsys vfs.FS, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
 	var findings []*models.Finding
 
 	if artifacts.Readme == nil || !artifacts.Readme.Found {
 		return findings
 	}
 
-	content, err := os.ReadFile(filepath.Join(basePath, artifacts.Readme.Path))
+	content, err := fsys.ReadFile(artifacts.Readme.Path)
 	if err != nil {
 		return findings
 	}
 
 	text := strings.ToLower(string(content))
 
+	// Project-specific commands from config
+	if cfg != nil {
+		for _, hint := range cfg.ReadmeHints {
+			if hint != "" && strings.Contains(text, strings.ToLower(hint)) {
+				findings = append(findings, models.NewFinding(
+					"HINT001",
+					models.SeverityInfo,
+					fmt.Sprintf("Likely entrypoint: %s (from README)", hint),
+				))
+			}
+		}
+	}
+
 	// Look for common run commands
 	patterns := []struct {
 		pattern string
@@ -308,19 +1039,104 @@ func checkReadmeHints(basePath string, artifacts *models.Artifacts) []*models.Fi
 	return findings
 }
 
+// procfileEntryRegex matches "type: command" lines in a Procfile
+var procfileEntryRegex = regexp.MustCompile(`^([a-zA-Z0-9_-]+):\s+(.*\S)`)
+
+// checkProcfileHints reports each process type declared in a Procfile, as
+// run by foreman, honcho or Heroku
+func checkProcfileHints(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	if artifacts.Procfile == nil || !artifacts.Procfile.Found {
+		return findings
+	}
+
+	content, err := fsys.ReadFile(artifacts.Procfile.Path)
+	if err != nil {
+		return findings
+	}
+
+	for i, line := range strings.Split(string(content), "\n") {
+		match := procfileEntryRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		findings = append(findings, models.NewFinding(
+			"HINT004",
+			models.SeverityInfo,
+			fmt.Sprintf("Process %s: %s (from Procfile)", match[1], match[2]),
+		).WithFile(artifacts.Procfile.Path, i+1))
+	}
+
+	return findings
+}
+
+// utf8BOM is the byte order mark some editors prepend to UTF-8 files
+const utf8BOM = "\ufeff"
+
+// envEntry is a single KEY=VALUE line from an env file
+type envEntry struct {
+	Key   string
+	Value string
+	Line  int
+	// Quoted reports whether the raw value was wrapped in quotes
+	Quoted bool
+	// Literal reports whether the value was single-quoted, which env
+	// loaders take as is without expanding ${VAR} references
+	Literal bool
+	// Section is the nearest preceding section header comment, if any
+	Section string
+}
+
+// sectionHeaderRegex matches section comments such as "# === Database ===",
+// "# --- Cache", "## Database" and "# [Database]"
+var sectionHeaderRegex = regexp.MustCompile(`^#+\s*(?:[=*-]{3,}\s*(.*?)\s*[=*-]*|\[\s*(.*?)\s*\])\s*$|^#{2,}\s*([^#=*\s-].*?)\s*#*\s*$`)
+
+// sectionHeader returns the name in a section header comment
+func sectionHeader(line string) (string, bool) {
+	match := sectionHeaderRegex.FindStringSubmatch(line)
+	if match == nil {
+		return "", false
+	}
+	name := firstGroup(match)
+	return name, name != ""
+}
+
 // parseEnvFile reads an env file and returns key-value pairs
-func parseEnvFile(path string) map[string]string {
+func parseEnvFile(fsys vfs.FS, name string) map[string]string {
 	result := make(map[string]string)
+	for _, entry := range parseEnvEntries(fsys, name) {
+		result[entry.Key] = entry.Value
+	}
+	return result
+}
+
+// parseEnvEntries reads an env file and returns its entries in file order
+func parseEnvEntries(fsys vfs.FS, name string) []envEntry {
+	var entries []envEntry
 
-	file, err := os.Open(path)
+	file, err := fsys.Open(name)
 	if err != nil {
-		return result
+		return entries
 	}
 	defer file.Close()
 
 	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	section := ""
 	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
+		lineNum++
+		raw := scanner.Text()
+		if lineNum == 1 {
+			// A leading BOM would otherwise become part of the first key
+			raw = strings.TrimPrefix(raw, utf8BOM)
+		}
+
+		line := strings.TrimSpace(raw)
+		if name, ok := sectionHeader(line); ok {
+			section = name
+			continue
+		}
 		// Skip comments and empty lines
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
@@ -331,13 +1147,38 @@ func parseEnvFile(path string) map[string]string {
 		if len(parts) == 2 {
 			key := strings.TrimSpace(parts[0])
 			value := strings.TrimSpace(parts[1])
+			quoted := strings.HasPrefix(value, `"`) || strings.HasPrefix(value, "'")
+			literal := strings.HasPrefix(value, "'")
 			// Remove quotes
 			value = strings.Trim(value, `"'`)
-			result[key] = value
+			entries = append(entries, envEntry{Key: key, Value: value, Line: lineNum, Quoted: quoted, Literal: literal, Section: section})
 		}
 	}
 
-	return result
+	return entries
+}
+
+// hasBOM checks if a file starts with a UTF-8 byte order mark
+func hasBOM(fsys vfs.FS, name string) bool {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buf := make([]byte, len(utf8BOM))
+	n, _ := file.Read(buf)
+	return string(buf[:n]) == utf8BOM
+}
+
+// missingFinalNewline reports whether a non-empty file lacks a trailing
+// newline, along with its line count for pointing at the last line
+func missingFinalNewline(fsys vfs.FS, name string) (int, bool) {
+	content, err := fsys.ReadFile(name)
+	if err != nil || len(content) == 0 || content[len(content)-1] == '\n' {
+		return 0, false
+	}
+	return strings.Count(string(content), "\n") + 1, true
 }
 
 // extractDependsOn extracts dependency names from depends_on node
@@ -368,6 +1209,15 @@ func extractDependsOn(node *yaml.Node) []string {
 	return deps
 }
 
+// appendEnvKey builds a fix that appends an empty KEY= entry to an env file
+func appendEnvKey(envPath, key string) *models.FixCommand {
+	return &models.FixCommand{
+		Action:  models.FixAppendLine,
+		Path:    envPath,
+		Content: key + "=",
+	}
+}
+
 // isStandardVar checks if a variable is a standard system variable
 func isStandardVar(name string) bool {
 	standard := map[string]bool{
@@ -384,54 +1234,124 @@ func isStandardVar(name string) bool {
 	return standard[name]
 }
 
-// checkSourceCodeEnvRefs scans source code for environment variable usage
-func checkSourceCodeEnvRefs(basePath string, artifacts *models.Artifacts) []*models.Finding {
-	var findings []*models.Finding
+// builtinExternalVars are env vars commonly injected by CI providers and
+// cloud platforms rather than defined in env files
+var builtinExternalVars = []string{
+	// CI providers
+	"CI", "BUILD_NUMBER", "JENKINS_URL",
+	"GITHUB_ACTIONS", "GITHUB_SHA", "GITHUB_REF", "GITHUB_REF_NAME", "GITHUB_RUN_ID", "GITHUB_REPOSITORY", "GITHUB_WORKSPACE",
+	"GITLAB_CI", "CI_COMMIT_SHA", "CI_COMMIT_REF_NAME", "CI_PIPELINE_ID", "CI_PROJECT_DIR",
+	"CIRCLECI", "CIRCLE_SHA1", "CIRCLE_BRANCH", "BUILDKITE", "BUILDKITE_COMMIT",
+	// Cloud and hosting platforms
+	"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_EXECUTION_ENV", "AWS_LAMBDA_FUNCTION_NAME",
+	"GOOGLE_CLOUD_PROJECT", "GOOGLE_APPLICATION_CREDENTIALS", "K_SERVICE",
+	"KUBERNETES_SERVICE_HOST", "VERCEL", "VERCEL_ENV", "NETLIFY", "DYNO", "RENDER",
+}
 
-	// Collect defined env vars
-	definedVars := make(map[string]bool)
-	for _, envFile := range artifacts.EnvFiles {
-		if envFile.Found {
-			vars := parseEnvFile(filepath.Join(basePath, envFile.Path))
-			for k := range vars {
-				definedVars[k] = true
-			}
+// knownExternalVars returns the env vars source scanning treats as
+// defined: the built-in list, unless disabled, plus known_external_vars
+func knownExternalVars(cfg *config.Config) map[string]bool {
+	known := make(map[string]bool)
+	if cfg == nil || !cfg.DisableBuiltinExternalVars {
+		for _, name := range builtinExternalVars {
+			known[name] = true
 		}
 	}
-
-	// Patterns to detect env var usage in source code
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`process\.env\.([A-Za-z_][A-Za-z0-9_]*)`),                         // Node.js
-		regexp.MustCompile(`os\.Getenv\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),              // Go
-		regexp.MustCompile(`os\.environ\s*\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]`),       // Python dict
-		regexp.MustCompile(`os\.getenv\s*\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]`),             // Python getenv
-		regexp.MustCompile(`System\.getenv\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),          // Java
-		regexp.MustCompile(`Environment\.GetEnvironmentVariable\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`), // C#
-		regexp.MustCompile(`env::var\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`),                // Rust
+	if cfg != nil {
+		for _, name := range cfg.KnownExternalVars {
+			known[name] = true
+		}
 	}
+	return known
+}
 
-	// File extensions to scan
-	extensions := map[string]bool{
-		".go":    true,
-		".js":    true,
-		".ts":    true,
-		".jsx":   true,
-		".tsx":   true,
-		".py":    true,
-		".java":  true,
-		".cs":    true,
-		".rs":    true,
-	}
+// sourceEnvPatterns detect env var usage in source code. They are combined
+// into a single alternation so each line is matched once; exactly one
+// capture group is non-empty per match.
+var sourceEnvPatterns = regexp.MustCompile(strings.Join([]string{
+	`process\.env\.([A-Za-z_][A-Za-z0-9_]*)`,                                     // Node.js
+	`process\.env\s*\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]`,                  // Node.js bracket
+	`os\.Getenv\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`,                          // Go
+	`os\.LookupEnv\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`,                       // Go LookupEnv
+	`os\.environ\s*\[\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]\s*\]`,                   // Python dict
+	`os\.environ\.get\s*\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]`,                   // Python environ.get
+	`os\.getenv\s*\(\s*['"]([A-Za-z_][A-Za-z0-9_]*)['"]`,                         // Python getenv
+	`System\.getenv\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`,                      // Java, Kotlin
+	`Environment\.GetEnvironmentVariable\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`, // C#
+	`env::var\s*\(\s*"([A-Za-z_][A-Za-z0-9_]*)"\s*\)`,                            // Rust
+}, "|"))
+
+// sourceExtensions are the file extensions scanned for env var usage
+var sourceExtensions = map[string]bool{
+	".go":   true,
+	".js":   true,
+	".ts":   true,
+	".jsx":  true,
+	".tsx":  true,
+	".py":   true,
+	".java": true,
+	".kt":   true,
+	".cs":   true,
+	".rs":   true,
+}
+
+// mentionsEnv is a cheap prefilter: every source pattern contains "env" or "Env"
+func mentionsEnv(s string) bool {
+	return strings.Contains(s, "env") || strings.Contains(s, "Env")
+}
+
+// checkSourceCodeEnvRefs scans source code and shell scripts for
+// environment variable usage. Variables in external are provided by the
+// platform and never reported. Files over maxSize bytes are skipped and
+// listed in one SRC002 note; a negative maxSize scans every file. Files
+// and directories matching ignorePatterns are not scanned. Findings are
+// passed to emit as each file is scanned.
+func checkSourceCodeEnvRefs(ctx context.Context, fsys vfs.FS, definedVars, external map[string]bool, maxSize int64, ignorePatterns []string, emit func(*models.Finding)) {
+	var skipped []string
 
 	// Track found undefined vars to avoid duplicates
 	foundUndefined := make(map[string]bool)
 
+	isDefined := func(name string) bool {
+		return definedVars[name] || isStandardVar(name) || external[name]
+	}
+
+	report := func(varName, path string, line int) {
+		foundUndefined[varName] = true
+		if suggestion := suggestVarName(varName, definedVars); suggestion != "" {
+			emit(models.NewFinding(
+				"ENV019",
+				models.SeverityBlocking,
+				fmt.Sprintf("Environment variable '%s' not defined; did you mean '%s'?", varName, suggestion),
+			).WithDetails(fmt.Sprintf("Variable %s is accessed in source code but only %s is defined", varName, suggestion)).
+				WithFile(path, line).
+				WithFix(fmt.Sprintf("Rename %s to %s in %s", varName, suggestion, path)))
+			return
+		}
+		emit(models.NewFinding(
+			"SRC001",
+			models.SeverityWarning,
+			fmt.Sprintf("Environment variable '%s' used in source but not defined", varName),
+		).WithDetails(fmt.Sprintf("Variable %s is accessed in source code but not found in any .env file", varName)).
+			WithFile(path, line).
+			WithFix(fmt.Sprintf("Add %s=<value> to .env file", varName)))
+	}
+
 	// Walk source files
-	filepath.Walk(basePath, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+	fsys.WalkDir(".", func(path string, entry fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return fs.SkipAll
+		}
+		if path != "." && entry != nil && matchesIgnorePattern(filepath.ToSlash(path), entry.IsDir(), ignorePatterns) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if err != nil || entry.IsDir() {
 			// Skip common non-source directories
-			if info != nil && info.IsDir() {
-				name := info.Name()
+			if entry != nil && entry.IsDir() {
+				name := entry.Name()
 				if name == "node_modules" || name == "vendor" || name == ".git" || name == "__pycache__" || name == "target" || name == "bin" || name == "obj" {
 					return filepath.SkipDir
 				}
@@ -439,36 +1359,48 @@ func checkSourceCodeEnvRefs(basePath string, artifacts *models.Artifacts) []*mod
 			return nil
 		}
 
+		// Shell scripts are recognized by extension or, without one, by
+		// their shebang
 		ext := filepath.Ext(path)
-		if !extensions[ext] {
+		isShell := shellExtensions[ext] || (ext == "" && hasShellShebang(fsys, path))
+		if !sourceExtensions[ext] && !isShell {
 			return nil
 		}
 
-		content, err := os.ReadFile(path)
+		// Generated files and minified bundles are slow to scan and noisy
+		if info, err := entry.Info(); err == nil && maxSize >= 0 && info.Size() > maxSize {
+			skipped = append(skipped, path)
+			return nil
+		}
+
+		content, err := fsys.ReadFile(path)
 		if err != nil {
 			return nil
 		}
 
-		relPath, _ := filepath.Rel(basePath, path)
-		lines := strings.Split(string(content), "\n")
+		text := string(content)
+		if isShell {
+			for _, ref := range shellEnvRefs(text, isDefined) {
+				if !foundUndefined[ref.Name] {
+					report(ref.Name, path, ref.Line)
+				}
+			}
+			return nil
+		}
+		if !mentionsEnv(text) {
+			return nil
+		}
+
+		lines := strings.Split(text, "\n")
 
 		for lineNum, line := range lines {
-			for _, pattern := range patterns {
-				matches := pattern.FindAllStringSubmatch(line, -1)
-				for _, match := range matches {
-					if len(match) >= 2 {
-						varName := match[1]
-						if !definedVars[varName] && !isStandardVar(varName) && !foundUndefined[varName] {
-							foundUndefined[varName] = true
-							findings = append(findings, models.NewFinding(
-								"SRC001",
-								models.SeverityWarning,
-								fmt.Sprintf("Environment variable '%s' used in source but not defined", varName),
-							).WithDetails(fmt.Sprintf("Variable %s is accessed in source code but not found in any .env file", varName)).
-								WithFile(relPath, lineNum+1).
-								WithFix(fmt.Sprintf("Add %s=<value> to .env file", varName)))
-						}
-					}
+			if !mentionsEnv(line) {
+				continue
+			}
+			for _, match := range sourceEnvPatterns.FindAllStringSubmatch(line, -1) {
+				varName := firstGroup(match)
+				if varName != "" && !isDefined(varName) && !foundUndefined[varName] {
+					report(varName, path, lineNum+1)
 				}
 			}
 		}
@@ -476,11 +1408,29 @@ func checkSourceCodeEnvRefs(basePath string, artifacts *models.Artifacts) []*mod
 		return nil
 	})
 
-	return findings
+	if len(skipped) > 0 {
+		emit(models.NewFinding(
+			"SRC002",
+			models.SeverityInfo,
+			fmt.Sprintf("Skipped %d source file(s) larger than %d bytes", len(skipped), maxSize),
+		).WithDetails(fmt.Sprintf("Not scanned for env var usage: %s", strings.Join(skipped, ", "))).
+			WithFile(skipped[0], 0).
+			WithFix("Raise --max-file-size if these files are hand-written source rather than generated code"))
+	}
+}
+
+// firstGroup returns the first non-empty capture group of a match
+func firstGroup(match []string) string {
+	for _, group := range match[1:] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
 }
 
 // checkBuildContexts validates that Dockerfiles exist in build contexts
-func checkBuildContexts(basePath string, artifacts *models.Artifacts) []*models.Finding {
+func checkBuildContexts(fsys vfs.FS, artifacts *models.Artifacts, filter serviceFilter) []*models.Finding {
 	var findings []*models.Finding
 
 	for _, composeFile := range artifacts.ComposeFiles {
@@ -488,7 +1438,7 @@ func checkBuildContexts(basePath string, artifacts *models.Artifacts) []*models.
 			continue
 		}
 
-		content, err := os.ReadFile(filepath.Join(basePath, composeFile.Path))
+		content, err := fsys.ReadFile(composeFile.Path)
 		if err != nil {
 			continue
 		}
@@ -503,8 +1453,16 @@ func checkBuildContexts(basePath string, artifacts *models.Artifacts) []*models.
 			continue
 		}
 
-		for svcName, svc := range compose.Services {
-			if svc.Build == nil {
+		// Sorted so findings come out in a stable order
+		names := make([]string, 0, len(compose.Services))
+		for name := range compose.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, svcName := range names {
+			svc := compose.Services[svcName]
+			if svc.Build == nil || !filter.includes(svcName) {
 				continue
 			}
 
@@ -528,8 +1486,7 @@ func checkBuildContexts(basePath string, artifacts *models.Artifacts) []*models.
 			}
 
 			// Check if Dockerfile exists in context
-			dockerfilePath := filepath.Join(basePath, context, dockerfile)
-			if _, err := os.Stat(dockerfilePath); os.IsNotExist(err) {
+			if _, err := fsys.Stat(filepath.Join(context, dockerfile)); errors.Is(err, fs.ErrNotExist) {
 				findings = append(findings, models.NewFinding(
 					"BUILD001",
 					models.SeverityBlocking,
@@ -540,15 +1497,18 @@ func checkBuildContexts(basePath string, artifacts *models.Artifacts) []*models.
 			}
 
 			// Check if context directory exists
-			contextPath := filepath.Join(basePath, context)
-			if _, err := os.Stat(contextPath); os.IsNotExist(err) {
+			if _, err := fsys.Stat(context); errors.Is(err, fs.ErrNotExist) {
 				findings = append(findings, models.NewFinding(
 					"BUILD002",
 					models.SeverityBlocking,
 					fmt.Sprintf("Build context directory not found for service %s", svcName),
 				).WithDetails(fmt.Sprintf("Service %s references build context %s which doesn't exist", svcName, context)).
 					WithFile(composeFile.Path, 0).
-					WithFix(fmt.Sprintf("Create directory %s or update build.context", context)))
+					WithFix(fmt.Sprintf("Create directory %s or update build.context", context)).
+					WithFixCommand(&models.FixCommand{
+						Action: models.FixCreateDir,
+						Path:   filepath.Clean(context),
+					}))
 			}
 		}
 	}
@@ -601,24 +1561,13 @@ func checkToolVersions(versions *config.ToolVersions) []*models.Finding {
 }
 
 // checkCustomRules applies custom rules from config
-func checkCustomRules(basePath string, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
+func checkCustomRules(definedVars map[string]bool, cfg *config.Config) []*models.Finding {
 	var findings []*models.Finding
 
 	if len(cfg.CustomRules) == 0 {
 		return findings
 	}
 
-	// Collect all defined vars
-	definedVars := make(map[string]bool)
-	for _, envFile := range artifacts.EnvFiles {
-		if envFile.Found {
-			vars := parseEnvFile(filepath.Join(basePath, envFile.Path))
-			for k := range vars {
-				definedVars[k] = true
-			}
-		}
-	}
-
 	for _, rule := range cfg.CustomRules {
 		if !rule.Required {
 			continue
@@ -632,25 +1581,31 @@ func checkCustomRules(basePath string, artifacts *models.Artifacts, cfg *config.
 		// Check if any matching variable is defined
 		found := false
 		for name := range definedVars {
-			if pattern.MatchString(name) {
+			if cfg.MatchesEnvPrefix(name) && pattern.MatchString(name) {
 				found = true
 				break
 			}
 		}
 
 		if !found {
-			severity := models.SeverityWarning
-			if rule.Severity == "blocking" {
-				severity = models.SeverityBlocking
-			} else if rule.Severity == "info" {
-				severity = models.SeverityInfo
+			// code_metadata fills in what the rule leaves out
+			code := rule.FindingCode()
+			meta := cfg.CodeMetadata[code]
+			severity := rule.Severity
+			if severity == "" {
+				severity = meta.Severity
+			}
+			description := rule.Description
+			if description == "" {
+				description = meta.Description
 			}
 
 			findings = append(findings, models.NewFinding(
-				"CUSTOM-"+rule.ID,
-				severity,
+				code,
+				customSeverity(severity),
 				fmt.Sprintf("Custom rule '%s' not satisfied", rule.ID),
-			).WithDetails(rule.Description).
+			).WithDetails(description).
+				WithCategory(models.CategoryEnv).
 				WithFix(fmt.Sprintf("Define a variable matching pattern: %s", rule.Pattern)))
 		}
 	}
@@ -659,49 +1614,27 @@ func checkCustomRules(basePath string, artifacts *models.Artifacts, cfg *config.
 }
 
 // checkRequiredEnvVars checks that required env vars from config are defined
-func checkRequiredEnvVars(basePath string, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
+func checkRequiredEnvVars(definedVars map[string]bool, cfg *config.Config) []*models.Finding {
 	var findings []*models.Finding
 
 	if len(cfg.RequiredEnvVars) == 0 {
 		return findings
 	}
 
-	// Collect all defined vars
-	definedVars := make(map[string]bool)
-	for _, envFile := range artifacts.EnvFiles {
-		if envFile.Found {
-			vars := parseEnvFile(filepath.Join(basePath, envFile.Path))
-			for k := range vars {
-				definedVars[k] = true
-			}
-		}
-	}
-
 	for _, required := range cfg.RequiredEnvVars {
+		if !cfg.MatchesEnvPrefix(required) {
+			continue
+		}
 		if !definedVars[required] {
 			findings = append(findings, models.NewFinding(
 				"REQ001",
 				models.SeverityBlocking,
 				fmt.Sprintf("Required variable '%s' not defined", required),
 			).WithDetails(fmt.Sprintf("Variable %s is configured as required in .devcheck.yaml but is not defined", required)).
-				WithFix(fmt.Sprintf("Add %s=<value> to .env file", required)))
+				WithFix(fmt.Sprintf("Add %s=<value> to .env file", required)).
+				WithFixCommand(appendEnvKey(".env", required)))
 		}
 	}
 
 	return findings
 }
-
-// filterIgnoredFindings removes findings with codes in the ignore list
-func filterIgnoredFindings(findings []*models.Finding, cfg *config.Config) []*models.Finding {
-	if len(cfg.IgnoreCodes) == 0 {
-		return findings
-	}
-
-	var filtered []*models.Finding
-	for _, f := range findings {
-		if !cfg.ShouldIgnoreCode(f.Code) {
-			filtered = append(filtered, f)
-		}
-	}
-	return filtered
-}
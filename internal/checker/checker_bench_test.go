@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// Baseline on testdata/bench (Intel Xeon, go test -bench . -benchmem):
+//
+//	                            ns/op     B/op  allocs/op
+//	BenchmarkCheckWithOptions  ~1.0ms   314 KB       1870  (per-check env parsing, per-pattern regex loop)
+//	BenchmarkCheckWithOptions  ~0.55ms  263 KB       1438  (env parsed once, combined regex, env prefilter)
+//	BenchmarkSourceScan        ~0.87ms  241 KB       1082  (before)
+//	BenchmarkSourceScan        ~0.35ms  193 KB        681  (after)
+//
+// Re-run after adding checks and update these numbers if they move noticeably.
+func BenchmarkCheckWithOptions(b *testing.B) {
+	basePath, err := filepath.Abs("testdata/bench")
+	if err != nil {
+		b.Fatalf("failed to get absolute path: %v", err)
+	}
+
+	artifacts := detector.Detect(basePath, "", nil)
+	opts := Options{
+		EnableSourceScanning: true,
+		Config:               config.DefaultConfig(),
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		CheckWithOptions(basePath, artifacts, opts)
+	}
+}
+
+func BenchmarkSourceScan(b *testing.B) {
+	basePath, err := filepath.Abs("testdata/bench")
+	if err != nil {
+		b.Fatalf("failed to get absolute path: %v", err)
+	}
+
+	artifacts := detector.Detect(basePath, "", nil)
+	definedVars := collectDefinedVars(vfs.OS(basePath), artifacts)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		checkSourceCodeEnvRefs(context.Background(), vfs.OS(basePath), definedVars, knownExternalVars(nil), DefaultMaxFileSize, nil, func(*models.Finding) {})
+	}
+}
@@ -1,11 +1,19 @@
 package checker
 
 import (
+	"context"
+	"fmt"
+	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/stackgen-cli/devcheck/internal/config"
 	"github.com/stackgen-cli/devcheck/internal/detector"
 	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
 )
 
 func TestCheckBasicProject(t *testing.T) {
@@ -69,9 +77,101 @@ func TestCheckMissingEnvVars(t *testing.T) {
 	}
 }
 
+func TestCheckOnFindingCallback(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/missing-env")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	cfg := config.DefaultConfig()
+	cfg.IgnoreCodes = []string{"LANG001"}
+
+	var streamed []*models.Finding
+	findings := CheckWithOptions(basePath, artifacts, Options{
+		Config: cfg,
+		OnFinding: func(f *models.Finding) bool {
+			streamed = append(streamed, f)
+			return true
+		},
+	})
+
+	if len(streamed) != len(findings) {
+		t.Fatalf("expected %d streamed findings, got %d", len(findings), len(streamed))
+	}
+	for i := range findings {
+		if streamed[i] != findings[i] {
+			t.Errorf("streamed finding %d differs from returned finding", i)
+		}
+		if streamed[i].Code == "LANG001" {
+			t.Error("ignored code LANG001 should not be streamed")
+		}
+	}
+}
+
+func TestCheckOnFindingPerSourceFinding(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env": {Data: []byte("DEFINED=1\n")},
+		"a.go": {Data: []byte("package a\nvar x = os.Getenv(\"FIRST_VAR\")\n")},
+		"b.go": {Data: []byte("package a\nvar y = os.Getenv(\"SECOND_VAR\")\nvar z = os.Getenv(\"THIRD_VAR\")\n")},
+		"c.py": {Data: []byte("import os\nos.environ[\"FOURTH_VAR\"]\n")},
+		"d.go": {Data: []byte("package a\nvar w = os.Getenv(\"DEFINED\")\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	calls := 0
+	var sourceCalls int
+	findings := CheckFS(fsys, artifacts, Options{
+		EnableSourceScanning: true,
+		OnFinding: func(f *models.Finding) bool {
+			calls++
+			if f.Code == "SRC001" {
+				sourceCalls++
+			}
+			return true
+		},
+	})
+
+	if calls != len(findings) {
+		t.Errorf("expected one call per finding (%d), got %d", len(findings), calls)
+	}
+	if sourceCalls != 4 {
+		t.Errorf("expected 4 calls for SRC001 findings, got %d", sourceCalls)
+	}
+}
+
+func TestCheckOnFindingStopsScan(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.go": {Data: []byte("package a\nvar x = os.Getenv(\"FIRST_VAR\")\n")},
+		"b.go": {Data: []byte("package a\nvar y = os.Getenv(\"SECOND_VAR\")\n")},
+		"c.go": {Data: []byte("package a\nvar z = os.Getenv(\"THIRD_VAR\")\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	// Stop at the first source finding
+	calls := 0
+	findings := CheckFS(fsys, artifacts, Options{
+		EnableSourceScanning: true,
+		OnFinding: func(f *models.Finding) bool {
+			calls++
+			return f.Code != "SRC001"
+		},
+	})
+
+	if calls != len(findings) {
+		t.Errorf("expected %d calls, one per returned finding, got %d", len(findings), calls)
+	}
+	if got := countByCode(findings, "SRC001"); got != 1 {
+		t.Errorf("expected the scan to stop after 1 SRC001, got %d", got)
+	}
+	if last := findings[len(findings)-1]; last.Code != "SRC001" {
+		t.Errorf("expected the stopping finding last, got %s", last.Code)
+	}
+	if countByCode(findings, "SCAN001") != 0 {
+		t.Error("a scan stopped by OnFinding should not report SCAN001")
+	}
+}
+
 func TestParseEnvFile(t *testing.T) {
 	basePath, _ := filepath.Abs("testdata/basic")
-	vars := parseEnvFile(filepath.Join(basePath, ".env"))
+	vars := parseEnvFile(vfs.OS(basePath), ".env")
 
 	if vars["DATABASE_HOST"] != "localhost" {
 		t.Errorf("expected DATABASE_HOST=localhost, got %s", vars["DATABASE_HOST"])
@@ -81,6 +181,142 @@ func TestParseEnvFile(t *testing.T) {
 	}
 }
 
+func TestParseEnvFileWithBOM(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/bom")
+	vars := parseEnvFile(vfs.OS(basePath), ".env")
+
+	if _, ok := vars["DATABASE_URL"]; !ok {
+		t.Errorf("expected DATABASE_URL to be parsed without BOM, got keys %v", vars)
+	}
+
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	if got := countByCode(findings, "ENV016"); got != 1 {
+		t.Errorf("expected 1 ENV016 finding, got %d", got)
+	}
+	if got := countByCode(findings, "ENV001"); got != 0 {
+		t.Errorf("expected 0 ENV001 findings, got %d", got)
+	}
+}
+
+func TestCheckBuildWithImage(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/build-image")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP021" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Service api builds image myorg/api without an explicit tag",
+		"Service web defines both build and image registry.local:5000/myorg/web:1.2",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected CMP021 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckEnvFilePaths(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-paths")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	missing := make(map[string]bool)
+	for _, f := range findings {
+		if f.Code == "ENV017" {
+			missing[f.Title] = true
+		}
+	}
+
+	expected := []string{
+		"GOOGLE_APPLICATION_CREDENTIALS points to missing file ./creds.json",
+		"TLS_KEY points to missing file certs/server.key",
+	}
+	if len(missing) != len(expected) {
+		t.Errorf("expected %d ENV017 findings, got %v", len(expected), missing)
+	}
+	for _, title := range expected {
+		if !missing[title] {
+			t.Errorf("expected finding %q", title)
+		}
+	}
+}
+
+func TestCheckDependsOnDisabled(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/disabled-deps")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP022" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Service api depends on debugger, which is disabled",
+		"Service api depends on mailer, which is disabled",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected CMP022 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestProjectJavaVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		content  string
+		expected string
+	}{
+		{"maven release", "pom.xml", "<properties>\n  <maven.compiler.release>17</maven.compiler.release>\n</properties>", "17"},
+		{"maven source", "pom.xml", "<properties>\n  <maven.compiler.source>1.8</maven.compiler.source>\n</properties>", "1.8"},
+		{"gradle string", "build.gradle", "java {\n  sourceCompatibility = '11'\n}", "11"},
+		{"gradle enum", "build.gradle", "sourceCompatibility = JavaVersion.VERSION_17", "17"},
+		{"gradle legacy enum", "build.gradle.kts", "java.sourceCompatibility = JavaVersion.VERSION_1_8", "1.8"},
+		{"gradle toolchain", "build.gradle.kts", "java { toolchain { languageVersion.set(JavaLanguageVersion.of(21)) } }", "21"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir, err := os.MkdirTemp("", "devcheck-test")
+			if err != nil {
+				t.Fatalf("failed to create temp dir: %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if err := os.WriteFile(filepath.Join(tmpDir, tt.file), []byte(tt.content), 0644); err != nil {
+				t.Fatalf("failed to create %s: %v", tt.file, err)
+			}
+
+			artifacts := detector.Detect(tmpDir, "", nil)
+			version, file, _ := projectJavaVersion(vfs.OS(tmpDir), artifacts)
+			if version != tt.expected {
+				t.Errorf("expected version %q, got %q", tt.expected, version)
+			}
+			if file != tt.file {
+				t.Errorf("expected file %s, got %s", tt.file, file)
+			}
+		})
+	}
+}
+
 func TestIsStandardVar(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -125,3 +361,1001 @@ func containsImpl(s, substr string) bool {
 	}
 	return false
 }
+
+func TestCheckProcfileHints(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/procfile")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var hints []*models.Finding
+	for _, f := range findings {
+		if f.Code == "HINT004" {
+			hints = append(hints, f)
+		}
+	}
+
+	if len(hints) != 2 {
+		t.Fatalf("expected 2 HINT004 findings, got %d", len(hints))
+	}
+	if hints[0].Title != "Process web: bundle exec rails server -p $PORT (from Procfile)" {
+		t.Errorf("unexpected title: %s", hints[0].Title)
+	}
+	if hints[1].Title != "Process worker: bundle exec sidekiq (from Procfile)" || hints[1].Files[0].Line != 2 {
+		t.Errorf("unexpected worker hint: %s at line %d", hints[1].Title, hints[1].Files[0].Line)
+	}
+}
+
+func TestCheckReportsRelativePaths(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/missing-env")
+	composePath := filepath.Join(basePath, "compose.yaml")
+
+	artifacts := detector.Detect(basePath, composePath, nil)
+	findings := CheckWithOptions(basePath, artifacts, Options{EnableSourceScanning: true})
+
+	if countByCode(findings, "ENV001") == 0 {
+		t.Fatal("expected ENV001 findings from the absolute compose override")
+	}
+	for _, f := range findings {
+		for _, loc := range f.Files {
+			if filepath.IsAbs(loc.File) {
+				t.Errorf("%s reports absolute path %s", f.Code, loc.File)
+			}
+		}
+	}
+}
+
+func TestCheckEnvironmentOverrides(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-override")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP023" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Service api overrides DB_HOST from .env",
+		"Service worker overrides LOG_LEVEL from .env",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected CMP023 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml": {Data: []byte(`services:
+  api:
+    build: ./api
+    environment:
+      DATABASE_URL: ${DATABASE_URL}
+      API_KEY: ${API_KEY}
+`)},
+		".env":         {Data: []byte("DATABASE_URL=postgres://localhost\n")},
+		".env.example": {Data: []byte("DATABASE_URL=\nAPI_KEY=\n")},
+		"api/main.go":  {Data: []byte("package main\n")},
+	}
+
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	findings := CheckFS(fsys, artifacts, Options{EnableSourceScanning: true})
+
+	if countByCode(findings, "ENV001") != 1 {
+		t.Errorf("expected 1 ENV001 finding for API_KEY, got %d", countByCode(findings, "ENV001"))
+	}
+	if countByCode(findings, "ENV002") != 1 {
+		t.Errorf("expected 1 ENV002 finding for API_KEY, got %d", countByCode(findings, "ENV002"))
+	}
+	// api/ exists but has no Dockerfile
+	if countByCode(findings, "BUILD001") != 1 || countByCode(findings, "BUILD002") != 0 {
+		t.Errorf("expected only BUILD001 for the api context")
+	}
+}
+
+func TestCheckEnvSelfReferences(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-selfref")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV025" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// HOST is defined, REDIS_HOST has a default and TEMPLATE is single-quoted
+	expected := []string{
+		"API_URL in .env references undefined variable DEVCHECK_TEST_UNSET_PORT",
+		"DSN in .env references undefined variable DEVCHECK_TEST_UNSET_USER",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+
+	// Variables from the environment resolve too
+	t.Setenv("DEVCHECK_TEST_UNSET_PORT", "8080")
+	if got := countByCode(Check(basePath, artifacts), "ENV025"); got != 1 {
+		t.Errorf("expected 1 ENV025 finding with the port in the environment, got %d", got)
+	}
+}
+
+func TestCheckEnvIncludeCycles(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-include-cycle")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var cycles []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV028" {
+			cycles = append(cycles, f)
+		}
+	}
+
+	// Both services reach the same loop; it is reported once
+	if len(cycles) != 1 {
+		t.Fatalf("expected 1 ENV028 finding, got %d", len(cycles))
+	}
+	expected := "Circular env file include: config/app.env → config/shared.env → config/app.env"
+	if cycles[0].Title != expected {
+		t.Errorf("expected %q, got %q", expected, cycles[0].Title)
+	}
+	if loc := cycles[0].Files[0]; loc.File != "config/shared.env" || loc.Line != 3 {
+		t.Errorf("expected ENV028 at config/shared.env:3, got %s:%d", loc.File, loc.Line)
+	}
+}
+
+func TestCheckEnvIncludeCyclesSharingAFile(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":         {Data: []byte("source a.env\n")},
+		"a.env":        {Data: []byte("source b.env\nsource c.env\n")},
+		"b.env":        {Data: []byte(". a.env\n")},
+		"c.env":        {Data: []byte(". a.env\n")},
+		"d.env":        {Data: []byte(". b.env\n")},
+		"compose.yaml": {Data: []byte("services:\n  app:\n    image: example/app:1.0\n    env_file: [b.env, d.env]\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	var titles []string
+	for _, f := range CheckFS(fsys, artifacts, Options{}) {
+		if f.Code == "ENV028" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// a.env is in both loops; entering the first one again from b.env is
+	// not a new cycle
+	expected := []string{
+		"Circular env file include: a.env → b.env → a.env",
+		"Circular env file include: a.env → c.env → a.env",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckEmptyEnvValues(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":         {Data: []byte("PORT=3000\nAPI_KEY=\nDATABASE_URL=\nSENTRY_DSN=\"\"\nSECRET_KEY=''\n")},
+		".env.example": {Data: []byte("PORT=\nAPI_KEY=\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	cfg := &config.Config{RequiredEnvVars: []string{"DATABASE_URL", "SECRET_KEY"}}
+
+	var got []string
+	for _, f := range CheckFS(fsys, artifacts, Options{Config: cfg}) {
+		if f.Code == "ENV027" {
+			got = append(got, fmt.Sprintf("%s %s:%d", f.Severity, f.Files[0].File, f.Files[0].Line))
+		}
+	}
+
+	expected := []string{
+		"warning .env:2",
+		"blocking .env:3",
+		"blocking .env:5",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got[i])
+		}
+	}
+}
+
+func TestCheckProjectDetected(t *testing.T) {
+	tests := []struct {
+		name     string
+		fsys     fstest.MapFS
+		expected int
+	}{
+		{"empty", fstest.MapFS{"notes.txt": {Data: []byte("hello\n")}}, 1},
+		{"env example only", fstest.MapFS{".env.example": {Data: []byte("PORT=\n")}}, 0},
+		{"manifest only", fstest.MapFS{"go.mod": {Data: []byte("module example.com/app\n")}}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifacts := detector.DetectFS(tt.fsys, detector.Options{})
+			findings := CheckFS(tt.fsys, artifacts, Options{})
+			if got := countByCode(findings, "DET001"); got != tt.expected {
+				t.Errorf("expected %d DET001 findings, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckEnvKeyOrder(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-order")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	if got := countByCode(Check(basePath, artifacts), "ENV026"); got != 0 {
+		t.Errorf("expected ENV026 to be off by default, got %d findings", got)
+	}
+
+	findings := CheckWithOptions(basePath, artifacts, Options{Config: &config.Config{CheckKeyOrder: true}})
+	var ordered []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV026" {
+			ordered = append(ordered, f)
+		}
+	}
+	if len(ordered) != 1 {
+		t.Fatalf("expected 1 ENV026 finding, got %d", len(ordered))
+	}
+	if loc := ordered[0].Files[0]; loc.File != ".env" || loc.Line != 3 {
+		t.Errorf("expected ENV026 at .env:3, got %s:%d", loc.File, loc.Line)
+	}
+}
+
+func TestCheckSourceMaxFileSize(t *testing.T) {
+	bundle := "var a=1;" + strings.Repeat("x", 4096) + ";fetch(process.env.BUNDLED_URL);\n"
+	fsys := fstest.MapFS{
+		".env":           {Data: []byte("PORT=3000\n")},
+		"src/index.js":   {Data: []byte("const url = process.env.API_URL;\n")},
+		"dist/bundle.js": {Data: []byte(bundle)},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	tests := []struct {
+		name      string
+		maxSize   int64
+		undefined int
+		skipped   int
+	}{
+		{"default limit", 0, 2, 0},
+		{"small limit", 1024, 1, 1},
+		{"no limit", -1, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := CheckFS(fsys, artifacts, Options{EnableSourceScanning: true, MaxFileSize: tt.maxSize})
+			if got := countByCode(findings, "SRC001"); got != tt.undefined {
+				t.Errorf("expected %d SRC001 findings, got %d", tt.undefined, got)
+			}
+			if got := countByCode(findings, "SRC002"); got != tt.skipped {
+				t.Errorf("expected %d SRC002 findings, got %d", tt.skipped, got)
+			}
+		})
+	}
+}
+
+func TestCheckKnownExternalVars(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml": {Data: []byte("services:\n  api:\n    image: api:1.0\n")},
+		".env":         {Data: []byte("DATABASE_URL=postgres://localhost\n")},
+		"main.go": {Data: []byte(`package main
+
+import "os"
+
+var (
+	sha    = os.Getenv("GITHUB_SHA")
+	region = os.Getenv("AWS_REGION")
+	tenant = os.Getenv("PLATFORM_TENANT")
+)
+`)},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	tests := []struct {
+		name     string
+		cfg      *config.Config
+		expected int
+	}{
+		{"built-ins", nil, 1},
+		{"merged", &config.Config{KnownExternalVars: []string{"PLATFORM_TENANT"}}, 0},
+		{"built-ins disabled", &config.Config{KnownExternalVars: []string{"PLATFORM_TENANT"}, DisableBuiltinExternalVars: true}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := CheckFS(fsys, artifacts, Options{Config: tt.cfg, EnableSourceScanning: true})
+			if got := countByCode(findings, "SRC001"); got != tt.expected {
+				t.Errorf("expected %d SRC001 findings, got %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSourceEnvPatterns(t *testing.T) {
+	tests := []struct {
+		line     string
+		expected string
+	}{
+		{`const url = process.env.API_URL`, "API_URL"},
+		{`const url = process.env["API_URL"]`, "API_URL"},
+		{`const url = process.env[ 'API_URL' ]`, "API_URL"},
+		{`url := os.Getenv("API_URL")`, "API_URL"},
+		{`url, ok := os.LookupEnv("API_URL")`, "API_URL"},
+		{`url = os.environ["API_URL"]`, "API_URL"},
+		{`url = os.environ.get("API_URL", "http://localhost")`, "API_URL"},
+		{`url = os.getenv('API_URL')`, "API_URL"},
+		{`val url = System.getenv("API_URL")`, "API_URL"},
+		{`var url = Environment.GetEnvironmentVariable("API_URL");`, "API_URL"},
+		{`let url = env::var("API_URL")?;`, "API_URL"},
+		{`const name = process.env[key]`, ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if match := sourceEnvPatterns.FindStringSubmatch(tt.line); match != nil {
+			got = firstGroup(match)
+		}
+		if got != tt.expected {
+			t.Errorf("%s: expected %q, got %q", tt.line, tt.expected, got)
+		}
+	}
+}
+
+func TestCheckKotlinSource(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":                  {Data: []byte("PORT=8080\n")},
+		"src/main/App.kt":       {Data: []byte("val port = System.getenv(\"PORT\")\nval key = System.getenv(\"SIGNING_KEY\")\n")},
+		"src/main/resources.md": {Data: []byte("System.getenv(\"IGNORED\")\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	findings := CheckFS(fsys, artifacts, Options{EnableSourceScanning: true})
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "SRC001" {
+			titles = append(titles, f.Title)
+		}
+	}
+	if len(titles) != 1 || titles[0] != "Environment variable 'SIGNING_KEY' used in source but not defined" {
+		t.Errorf("expected SRC001 for SIGNING_KEY only, got %v", titles)
+	}
+}
+
+func TestCheckExtendsCycles(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/extends-cycle")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP024" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Circular extends: a → b → a",
+		"Circular extends: d → d",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected CMP024 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestEnvPrefixScopesRequiredVars(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.RequiredEnvVars = []string{"MYAPP_DB", "MYAPP_KEY", "THIRD_PARTY_TOKEN"}
+	cfg.EnvPrefix = "MYAPP_"
+
+	findings := checkRequiredEnvVars(map[string]bool{"MYAPP_DB": true}, cfg)
+	if len(findings) != 1 || findings[0].Title != "Required variable 'MYAPP_KEY' not defined" {
+		t.Errorf("expected only MYAPP_KEY to be reported, got %d findings", len(findings))
+	}
+}
+
+func TestCheckDockerignoreEnv(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/dockerignore")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "SEC004" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// api excludes *.env; web re-includes .env; worker has no .dockerignore
+	expected := []string{
+		"web/.env may be copied into the web image",
+		"worker/.env may be copied into the worker image",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected SEC004 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckEnvFilePermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions don't apply on Windows")
+	}
+
+	fsys := fstest.MapFS{
+		".env":       {Data: []byte("API_TOKEN=abc123\n"), Mode: 0o644},
+		".env.local": {Data: []byte("API_TOKEN=abc123\n"), Mode: 0o600},
+		".env.dev":   {Data: []byte("PORT=3000\n"), Mode: 0o644},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	findings := CheckFS(fsys, artifacts, Options{})
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "SEC006" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// .env.local is private and .env.dev holds no secrets
+	expected := []string{".env is readable by other users (mode 0644)"}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected SEC006 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckExampleSecrets(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/example-secrets")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "SEC007" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Placeholders, the documented AWS example key and plain config are fine
+	expected := []string{
+		"AWS_ACCESS_KEY_ID in .env.example looks like a real secret",
+		"SESSION_SECRET in .env.example looks like a real secret",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected SEC007 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestDockerignoreExcludes(t *testing.T) {
+	tests := []struct {
+		content  string
+		expected bool
+	}{
+		{".env\n", true},
+		{"/.env\n", true},
+		{"**/.env\n", true},
+		{".env*\n", true},
+		{".*\n", true},
+		{"# .env\n", false},
+		{".env\n!.env\n", false},
+		{"node_modules\n", false},
+	}
+
+	for _, tt := range tests {
+		if got := dockerignoreExcludes(tt.content, ".env"); got != tt.expected {
+			t.Errorf("dockerignoreExcludes(%q) = %v, want %v", tt.content, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckNoEnvFileSummary(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/no-env")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	findings := Check(basePath, artifacts)
+	if countByCode(findings, "ENV001") != 0 || countByCode(findings, "ENV018") != 1 {
+		t.Fatalf("expected a single ENV018 and no ENV001, got %d ENV018 and %d ENV001",
+			countByCode(findings, "ENV018"), countByCode(findings, "ENV001"))
+	}
+	for _, f := range findings {
+		if f.Code == "ENV018" && f.Title != "Compose uses 2 variables but no .env file found" {
+			t.Errorf("unexpected title: %s", f.Title)
+		}
+	}
+
+	detailed := CheckWithOptions(basePath, artifacts, Options{DetailedEnvRefs: true})
+	if countByCode(detailed, "ENV001") != 3 || countByCode(detailed, "ENV018") != 0 {
+		t.Errorf("expected 3 ENV001 with DetailedEnvRefs, got %d", countByCode(detailed, "ENV001"))
+	}
+}
+
+func TestCheckDevcontainer(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/devcontainer")
+	artifacts := detector.Detect(basePath, "", nil)
+	if artifacts.Devcontainer == nil {
+		t.Fatal("expected devcontainer.json to be detected")
+	}
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "DEVC001" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		".devcontainer/devcontainer.json references missing file compose.dev.yaml",
+		".devcontainer/devcontainer.json references missing file Dockerfile",
+		"remoteEnv.GITHUB_TOKEN uses undefined ${localEnv:DEVCHECK_TEST_UNSET_TOKEN}",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected DEVC001 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckUnknownAnchors(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/unknown-anchor")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var found *models.Finding
+	for _, f := range findings {
+		if f.Code == "CMP025" {
+			found = f
+		}
+	}
+	if found == nil {
+		t.Fatal("expected a CMP025 finding")
+	}
+	if found.Title != "Alias *commmon references undefined anchor in compose.yaml" || found.Files[0].Line != 6 {
+		t.Errorf("unexpected finding: %s at line %d", found.Title, found.Files[0].Line)
+	}
+}
+
+func TestCheckWithCancelledContext(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/bench")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	findings := CheckWithContext(ctx, basePath, artifacts, Options{EnableSourceScanning: true})
+	if countByCode(findings, "SCAN001") != 1 {
+		t.Errorf("expected a SCAN001 finding, got %d", countByCode(findings, "SCAN001"))
+	}
+	if countByCode(findings, "SRC001") != 0 {
+		t.Error("expected source scanning to stop when the context is done")
+	}
+
+	complete := CheckWithOptions(basePath, artifacts, Options{EnableSourceScanning: true})
+	if countByCode(complete, "SCAN001") != 0 || countByCode(complete, "SRC001") == 0 {
+		t.Error("expected a full scan without SCAN001")
+	}
+}
+
+func TestStackTools(t *testing.T) {
+	fsys := fstest.MapFS{
+		"mvnw": &fstest.MapFile{Data: []byte("#!/bin/sh\n")},
+	}
+
+	tests := []struct {
+		name      string
+		artifacts *models.Artifacts
+		want      []string
+	}{
+		{
+			name: "node with pnpm and compose",
+			artifacts: &models.Artifacts{
+				ComposeFiles:      []models.Artifact{{Found: true}},
+				DetectedLanguages: []models.Language{models.LangNodeJS},
+				PackageManager:    "pnpm",
+			},
+			want: []string{"docker", "node", "pnpm"},
+		},
+		{
+			name: "maven wrapper present",
+			artifacts: &models.Artifacts{
+				DetectedLanguages: []models.Language{models.LangJava},
+				PackageManager:    "maven",
+			},
+			want: []string{"java"},
+		},
+		{
+			name: "go module",
+			artifacts: &models.Artifacts{
+				DetectedLanguages: []models.Language{models.LangGo},
+				PackageManager:    "go mod",
+			},
+			want: []string{"go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, tool := range stackTools(vfs.FromFS(fsys), tt.artifacts) {
+				got = append(got, tool.name)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckSuggestsMistypedVars(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-typo")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV019" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"${databaseUrl} not defined; did you mean ${DATABASE_URL}?",
+		"${REDIS_HOTS} not defined; did you mean ${REDIS_HOST}?",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %d ENV019 findings, got %v", len(expected), titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+
+	// Short names and unrelated names stay plain undefined references
+	if got := countByCode(findings, "ENV001"); got != 2 {
+		t.Errorf("expected 2 ENV001 findings for ${DN} and ${SESSION_SECRET}, got %d", got)
+	}
+}
+
+func TestCheckServicesFilter(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/services")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	codeTitles := func(findings []*models.Finding) []string {
+		var titles []string
+		for _, f := range findings {
+			if f.Code == "ENV001" || f.Code == "CMP001" || f.Code == "SCAN002" {
+				titles = append(titles, f.Code+" "+f.Title)
+			}
+		}
+		return titles
+	}
+
+	all := codeTitles(Check(basePath, artifacts))
+	if len(all) != 5 {
+		t.Fatalf("expected 5 findings without a filter, got %v", all)
+	}
+
+	tests := []struct {
+		services []string
+		expected []string
+	}{
+		// api's depends_on resolves db even though db isn't selected, and
+		// the shared x-common block still counts; web's problems are skipped
+		{[]string{"api", "worker"}, []string{
+			"SCAN002 Service worker selected with --services is not defined",
+			"ENV001 ${SHARED_SECRET} referenced but not defined",
+			"ENV001 ${API_DB_URL} referenced but not defined",
+			"ENV001 ${API_TOKEN} referenced but not defined",
+		}},
+		// The reference inside api's multi-line command belongs to api
+		{[]string{"web"}, []string{
+			"ENV001 ${SHARED_SECRET} referenced but not defined",
+			"ENV001 ${WEB_API_URL} referenced but not defined",
+			"CMP001 Service web depends on unknown service cache",
+		}},
+	}
+	for _, tt := range tests {
+		scoped := codeTitles(CheckWithOptions(basePath, artifacts, Options{Services: tt.services}))
+		if len(scoped) != len(tt.expected) {
+			t.Fatalf("%v: expected %v, got %v", tt.services, tt.expected, scoped)
+		}
+		for i := range tt.expected {
+			if scoped[i] != tt.expected[i] {
+				t.Errorf("%v: expected %q, got %q", tt.services, tt.expected[i], scoped[i])
+			}
+		}
+	}
+}
+
+func TestCheckEnvMissingFinalNewline(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/no-newline")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var flagged []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV020" {
+			flagged = append(flagged, f)
+		}
+	}
+
+	// The empty .env.example has no last line to mis-parse
+	if len(flagged) != 1 {
+		t.Fatalf("expected 1 ENV020 finding, got %d", len(flagged))
+	}
+	if loc := flagged[0].Files[0]; loc.File != ".env" || loc.Line != 2 {
+		t.Errorf("expected .env:2, got %s:%d", loc.File, loc.Line)
+	}
+}
+
+func TestCheckEnvUnquotedJSON(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-json")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV022" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Quoted JSON and plain values are fine
+	expected := []string{
+		"FEATURES has an unquoted JSON value",
+		"ALLOWED_HOSTS has an unquoted JSON value",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected ENV022 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckUnreferencedServices(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/unused-service")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP027" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// worker starts by default, admin publishes a port and metrics is linked
+	expected := []string{
+		"Service debug may be unused",
+		"Service grafana may be unused",
+		"Service legacy-cache may be unused",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckBrokenEnvSymlink(t *testing.T) {
+	basePath := t.TempDir()
+	if err := os.WriteFile(filepath.Join(basePath, ".env.example"), []byte("API_KEY=\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join("..", "secrets", "dev.env"), filepath.Join(basePath, ".env")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	if got := countByCode(findings, "ENV003"); got != 0 {
+		t.Errorf("expected no ENV003 for a dangling .env symlink, got %d", got)
+	}
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV021" {
+			titles = append(titles, f.Title)
+		}
+	}
+	expected := ".env is a broken symlink to " + filepath.Join("..", "secrets", "dev.env")
+	if len(titles) != 1 || titles[0] != expected {
+		t.Errorf("expected [%s], got %v", expected, titles)
+	}
+}
+
+func TestSectionHeader(t *testing.T) {
+	tests := map[string]string{
+		"# === Database ===":  "Database",
+		"#===== API =====":    "API",
+		"# --- Cache":         "Cache",
+		"## Object storage":   "Object storage",
+		"### Mail ###":        "Mail",
+		"# [Redis]":           "Redis",
+		"# ==========":        "",
+		"# Database password": "",
+		"DB_HOST=localhost":   "",
+	}
+
+	for line, expected := range tests {
+		name, ok := sectionHeader(line)
+		if name != expected || ok != (expected != "") {
+			t.Errorf("sectionHeader(%q) = %q, %v; expected %q", line, name, ok, expected)
+		}
+	}
+}
+
+func TestCheckEnvExampleSections(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-sections")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var got []string
+	for _, f := range findings {
+		if f.Code == "ENV002" {
+			got = append(got, f.Section+": "+f.Title)
+		}
+	}
+
+	// Missing keys are reported in .env.example order with their section
+	expected := []string{
+		"Database: .env.example has DB_PASSWORD but .env does not",
+		"Cache: .env.example has REDIS_URL but .env does not",
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got[i])
+		}
+	}
+}
+
+func TestCheckEnvPrecedence(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-precedence")
+	artifacts := detector.Detect(basePath, "", nil)
+
+	tests := []struct {
+		name       string
+		cfg        *config.Config
+		precedence string
+		missing    int
+	}{
+		// .env lacks API_KEY, so it is reported against the winning file only
+		{"default", nil, "2 env files found; .env takes precedence", 1},
+		{"configured", &config.Config{EnvPrecedence: []string{".env.local", ".env"}}, "2 env files found; .env.local takes precedence", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := CheckWithOptions(basePath, artifacts, Options{Config: tt.cfg})
+
+			var titles []string
+			for _, f := range findings {
+				if f.Code == "ENV023" {
+					titles = append(titles, f.Title)
+				}
+			}
+			if len(titles) != 1 || titles[0] != tt.precedence {
+				t.Errorf("expected ENV023 %q, got %v", tt.precedence, titles)
+			}
+			if got := countByCode(findings, "ENV002"); got != tt.missing {
+				t.Errorf("expected %d ENV002 findings, got %d", tt.missing, got)
+			}
+		})
+	}
+}
+
+func TestCheckShadowedSystemVars(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/system-vars")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV024" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Project names that merely contain a system name are fine
+	expected := []string{
+		".env sets system variable PATH",
+		".env sets system variable USER",
+		".env sets system variable LD_PRELOAD",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected ENV024 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckReadmeHintsFromConfig(t *testing.T) {
+	fsys := fstest.MapFS{
+		"README.md": {Data: []byte("# App\n\nRun `Just Dev` to start everything, or `docker compose up` for the stack only.\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	cfg := &config.Config{ReadmeHints: []string{"just dev", "task run"}}
+	findings := CheckFS(fsys, artifacts, Options{Config: cfg})
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "HINT001" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// task run is not in the README
+	expected := []string{
+		"Likely entrypoint: just dev (from README)",
+		"Likely entrypoint: docker compose up (from README)",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected HINT001 findings %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
@@ -0,0 +1,1135 @@
+package checker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// composeDoc is a parsed compose file with node positions kept for reporting
+type composeDoc struct {
+	Path     string
+	Content  []byte
+	Root     *yaml.Node
+	Services []*composeService
+}
+
+// composeService is a single service definition within a compose file
+type composeService struct {
+	Name string
+	Line int
+	Node *yaml.Node
+	Spec serviceSpec
+}
+
+// serviceSpec holds the service fields shared by several checks
+type serviceSpec struct {
+	Image     string    `yaml:"image"`
+	Build     yaml.Node `yaml:"build"`
+	DependsOn yaml.Node `yaml:"depends_on"`
+	Profiles  []string  `yaml:"profiles"`
+	Deploy    struct {
+		Replicas *int `yaml:"replicas"`
+	} `yaml:"deploy"`
+}
+
+// composeParseError records a compose file that failed to parse
+type composeParseError struct {
+	Path    string
+	Content []byte
+	Err     error
+}
+
+// loadComposeDocs parses every found compose file, skipping unreadable ones
+// and returning invalid ones as parse errors. Services are sorted by name
+// so findings are deterministic.
+func loadComposeDocs(fsys vfs.FS, artifacts *models.Artifacts) ([]*composeDoc, []composeParseError) {
+	var docs []*composeDoc
+	var parseErrors []composeParseError
+
+	for _, composeFile := range artifacts.ComposeFiles {
+		if !composeFile.Found {
+			continue
+		}
+
+		content, err := fsys.ReadFile(composeFile.Path)
+		if err != nil {
+			continue
+		}
+
+		doc, err := parseComposeDoc(composeFile.Path, content)
+		if err != nil {
+			parseErrors = append(parseErrors, composeParseError{Path: composeFile.Path, Content: content, Err: err})
+			continue
+		}
+		docs = append(docs, doc)
+	}
+
+	return docs, parseErrors
+}
+
+// parseComposeDoc parses compose content into a composeDoc
+func parseComposeDoc(path string, content []byte) (*composeDoc, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	doc := &composeDoc{Path: path, Content: content, Root: &root}
+	if len(root.Content) == 0 {
+		return doc, nil
+	}
+
+	services := mappingValue(root.Content[0], "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return doc, nil
+	}
+
+	for i := 0; i+1 < len(services.Content); i += 2 {
+		key, value := services.Content[i], resolveAlias(services.Content[i+1])
+		svc := &composeService{Name: key.Value, Line: key.Line, Node: value}
+		// Best effort: a malformed field only leaves its spec value empty
+		_ = value.Decode(&svc.Spec)
+		doc.Services = append(doc.Services, svc)
+	}
+
+	sort.Slice(doc.Services, func(i, j int) bool {
+		return doc.Services[i].Name < doc.Services[j].Name
+	})
+
+	return doc, nil
+}
+
+// Field returns the value node for a key in the service definition
+func (s *composeService) Field(key string) *yaml.Node {
+	return mappingValue(s.Node, key)
+}
+
+// HasField checks if the service sets the given key
+func (s *composeService) HasField(key string) bool {
+	return s.Field(key) != nil
+}
+
+// mappingValue returns the value for key in a mapping node, following
+// aliases and YAML merge keys (<<: *base)
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	node = resolveAlias(node)
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var merges []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		k := node.Content[i]
+		if k.Value == key {
+			return resolveAlias(node.Content[i+1])
+		}
+		if k.Value == "<<" {
+			merges = append(merges, node.Content[i+1])
+		}
+	}
+
+	for _, merge := range merges {
+		merge = resolveAlias(merge)
+		if merge.Kind == yaml.SequenceNode {
+			for _, item := range merge.Content {
+				if v := mappingValue(item, key); v != nil {
+					return v
+				}
+			}
+			continue
+		}
+		if v := mappingValue(merge, key); v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
+
+// resolveAlias follows alias nodes to their anchored value
+func resolveAlias(node *yaml.Node) *yaml.Node {
+	for node != nil && node.Kind == yaml.AliasNode {
+		node = node.Alias
+	}
+	return node
+}
+
+// splitImageTag splits an image reference into repository and tag. Digest
+// references are returned with an empty tag and digest set.
+func splitImageTag(image string) (repo, tag, digest string) {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image, digest = image[:i], image[i+1:]
+	}
+
+	// A colon after the last slash separates the tag; earlier colons
+	// belong to a registry port
+	lastSlash := strings.LastIndex(image, "/")
+	if i := strings.LastIndex(image, ":"); i > lastSlash {
+		return image[:i], image[i+1:], digest
+	}
+	return image, "", digest
+}
+
+// dockerHubHosts are registry hosts that mean Docker Hub
+var dockerHubHosts = map[string]bool{
+	"docker.io":            true,
+	"index.docker.io":      true,
+	"registry-1.docker.io": true,
+}
+
+// imageRegistry returns the registry host of an image reference, or "" for
+// Docker Hub. Like docker, the first path component is only a host if it
+// contains a dot or port, or is localhost.
+func imageRegistry(image string) string {
+	repo, _, _ := splitImageTag(image)
+	host, _, found := strings.Cut(repo, "/")
+	if !found || (!strings.ContainsAny(host, ".:") && host != "localhost") || dockerHubHosts[host] {
+		return ""
+	}
+	return host
+}
+
+// checkPrivateRegistries notes services pulling images from registries
+// other than Docker Hub, which usually need docker login first. Services
+// that build their image don't pull it.
+func checkPrivateRegistries(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if svc.Spec.Image == "" || svc.HasField("build") || strings.Contains(svc.Spec.Image, "$") || !filter.includes(svc.Name) {
+				continue
+			}
+
+			host := imageRegistry(svc.Spec.Image)
+			if host == "" {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"CMP028",
+				models.SeverityInfo,
+				fmt.Sprintf("Service %s pulls from registry %s", svc.Name, host),
+			).WithDetails(fmt.Sprintf("%s is pulled from %s rather than Docker Hub; if that registry is private, docker compose up fails until you log in", svc.Spec.Image, host)).
+				WithFile(doc.Path, svc.Field("image").Line).
+				WithFix(fmt.Sprintf("Run docker login %s if the registry requires authentication", host)))
+		}
+	}
+
+	return findings
+}
+
+// heavyImages are image names (the last repository component) of
+// databases, search engines and brokers that use as much memory as they
+// can get
+var heavyImages = map[string]bool{
+	"postgres": true, "postgis": true, "mysql": true, "mariadb": true,
+	"mongo": true, "elasticsearch": true, "opensearch": true, "kibana": true,
+	"logstash": true, "kafka": true, "cp-kafka": true, "cassandra": true,
+	"clickhouse-server": true, "neo4j": true, "sonarqube": true, "oracle-xe": true,
+	"cockroach": true, "couchbase": true, "jenkins": true, "spark": true,
+}
+
+// isHeavyImage reports whether an image is known to be resource-hungry
+func isHeavyImage(image string) bool {
+	repo, _, _ := splitImageTag(image)
+	name := repo[strings.LastIndex(repo, "/")+1:]
+	return heavyImages[name] || strings.Contains(repo, "mssql")
+}
+
+// hasResourceLimits reports whether a service limits its memory or CPU,
+// via deploy.resources.limits or the legacy mem_limit and cpus keys
+func hasResourceLimits(svc *composeService) bool {
+	if svc.HasField("mem_limit") || svc.HasField("cpus") {
+		return true
+	}
+	limits := mappingValue(mappingValue(svc.Field("deploy"), "resources"), "limits")
+	return mappingValue(limits, "memory") != nil || mappingValue(limits, "cpus") != nil
+}
+
+// checkResourceLimits notes resource-hungry services, such as databases
+// and search engines, that run without memory or CPU limits and can
+// exhaust a laptop during local development
+func checkResourceLimits(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if svc.Spec.Image == "" || !isHeavyImage(svc.Spec.Image) || hasResourceLimits(svc) || !filter.includes(svc.Name) {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"CMP031",
+				models.SeverityInfo,
+				fmt.Sprintf("Service %s runs %s without resource limits", svc.Name, svc.Spec.Image),
+			).WithDetails(fmt.Sprintf("%s tends to use as much memory as it can get; without a limit it can starve the rest of the stack on a laptop", svc.Spec.Image)).
+				WithFile(doc.Path, svc.Field("image").Line).
+				WithFix(fmt.Sprintf("Add deploy.resources.limits to %s, e.g. memory: 1g", svc.Name)))
+		}
+	}
+
+	return findings
+}
+
+// databaseImages are image names (the last repository component) of
+// databases and brokers that accept connections some time after their
+// container starts
+var databaseImages = map[string]bool{
+	"postgres": true, "postgis": true, "mysql": true, "mariadb": true,
+	"mongo": true, "redis": true, "valkey": true, "elasticsearch": true,
+	"opensearch": true, "cassandra": true, "clickhouse-server": true,
+	"cockroach": true, "couchbase": true, "neo4j": true, "rabbitmq": true,
+	"kafka": true, "cp-kafka": true, "oracle-xe": true,
+}
+
+// isDatabaseImage reports whether an image runs a database or broker
+func isDatabaseImage(image string) bool {
+	repo, _, _ := splitImageTag(image)
+	name := repo[strings.LastIndex(repo, "/")+1:]
+	return databaseImages[name] || strings.Contains(repo, "mssql")
+}
+
+// hasHealthcheck reports whether a service defines an enabled healthcheck
+func hasHealthcheck(svc *composeService) bool {
+	node := svc.Field("healthcheck")
+	if node == nil {
+		return false
+	}
+	disable := mappingValue(node, "disable")
+	return disable == nil || disable.Value != "true"
+}
+
+// dependsOnCondition returns the condition a depends_on entry waits for,
+// or "" for the list form, which only waits for the container to start
+func dependsOnCondition(svc *composeService, dep string) string {
+	if condition := mappingValue(mappingValue(svc.Field("depends_on"), dep), "condition"); condition != nil {
+		return condition.Value
+	}
+	return ""
+}
+
+// checkDependsOnReadiness notes services that depend on a database without
+// waiting for it to be ready: no service_healthy condition and no
+// healthcheck on the database. Plain depends_on only waits for the
+// container to start, so the app often crashes connecting too early.
+func checkDependsOnReadiness(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		services := make(map[string]*composeService, len(doc.Services))
+		for _, svc := range doc.Services {
+			services[svc.Name] = svc
+		}
+
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+
+			for _, dep := range extractDependsOn(&svc.Spec.DependsOn) {
+				target, ok := services[dep]
+				if !ok || target.Spec.Image == "" || !isDatabaseImage(target.Spec.Image) {
+					continue
+				}
+				if dependsOnCondition(svc, dep) == "service_healthy" || hasHealthcheck(target) {
+					continue
+				}
+
+				findings = append(findings, models.NewFinding(
+					"CMP032",
+					models.SeverityInfo,
+					fmt.Sprintf("Service %s starts as soon as %s's container starts, not when it is ready", svc.Name, dep),
+				).WithDetails(fmt.Sprintf("%s runs %s, which takes a while to accept connections; %s may crash or retry while it starts up", dep, target.Spec.Image, svc.Name)).
+					WithFile(doc.Path, svc.Field("depends_on").Line).
+					WithFix(fmt.Sprintf("Add a healthcheck to %s and depend on it with condition: service_healthy", dep)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkBuildWithImage notes services that define both build and image.
+// Compose builds and tags the image with that name, which is fine but
+// sometimes unintentional, and an untagged name resolves to latest.
+func checkBuildWithImage(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if svc.Spec.Image == "" || !svc.HasField("build") || !filter.includes(svc.Name) {
+				continue
+			}
+
+			repo, tag, digest := splitImageTag(svc.Spec.Image)
+			finding := models.NewFinding(
+				"CMP021",
+				models.SeverityInfo,
+				fmt.Sprintf("Service %s defines both build and image %s", svc.Name, svc.Spec.Image),
+			).WithFile(doc.Path, svc.Line)
+
+			if digest == "" && (tag == "" || tag == "latest") {
+				finding.Title = fmt.Sprintf("Service %s builds image %s without an explicit tag", svc.Name, repo)
+				finding.WithDetails(fmt.Sprintf("The built image is tagged %s:latest, which is easy to confuse with a pulled image of the same name", repo)).
+					WithFix(fmt.Sprintf("Give the image an explicit tag, e.g. %s:dev", repo))
+			} else {
+				finding.WithDetails(fmt.Sprintf("Compose builds service %s and tags the result as %s; check nothing else expects to pull that image", svc.Name, svc.Spec.Image))
+			}
+
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+// activeComposeProfiles returns the profiles enabled through
+// COMPOSE_PROFILES, read from the environment or the project's .env
+func activeComposeProfiles(fsys vfs.FS) map[string]bool {
+	value := os.Getenv("COMPOSE_PROFILES")
+	if value == "" {
+		value = parseEnvFile(fsys, ".env")["COMPOSE_PROFILES"]
+	}
+
+	active := make(map[string]bool)
+	for _, p := range strings.Split(value, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			active[p] = true
+		}
+	}
+	return active
+}
+
+// disabledReason explains why a service will not start in the active
+// configuration, or returns "" if it will
+func disabledReason(svc *composeService, activeProfiles map[string]bool) string {
+	if svc.Spec.Deploy.Replicas != nil && *svc.Spec.Deploy.Replicas == 0 {
+		return "deploy.replicas is 0"
+	}
+	if len(svc.Spec.Profiles) == 0 || activeProfiles["*"] {
+		return ""
+	}
+	for _, p := range svc.Spec.Profiles {
+		if activeProfiles[p] {
+			return ""
+		}
+	}
+	return fmt.Sprintf("only starts with profile %s, which is not active", strings.Join(svc.Spec.Profiles, " or "))
+}
+
+// checkDependsOnDisabled flags depends_on targets that never start, which
+// makes docker compose up fail or hang waiting for them
+func checkDependsOnDisabled(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	activeProfiles := activeComposeProfiles(fsys)
+
+	for _, doc := range docs {
+		services := make(map[string]*composeService, len(doc.Services))
+		for _, svc := range doc.Services {
+			services[svc.Name] = svc
+		}
+
+		for _, svc := range doc.Services {
+			// A disabled depender never waits on anything
+			if !filter.includes(svc.Name) || disabledReason(svc, activeProfiles) != "" {
+				continue
+			}
+
+			for _, dep := range extractDependsOn(&svc.Spec.DependsOn) {
+				target, ok := services[dep]
+				if !ok {
+					continue // unknown services are reported by CMP001
+				}
+				reason := disabledReason(target, activeProfiles)
+				if reason == "" {
+					continue
+				}
+
+				findings = append(findings, models.NewFinding(
+					"CMP022",
+					models.SeverityWarning,
+					fmt.Sprintf("Service %s depends on %s, which is disabled", svc.Name, dep),
+				).WithDetails(fmt.Sprintf("%s %s, so %s will fail to start or wait forever", dep, reason, svc.Name)).
+					WithFile(doc.Path, svc.Field("depends_on").Line).
+					WithFix(fmt.Sprintf("Enable %s (e.g. COMPOSE_PROFILES) or remove it from %s's depends_on", dep, svc.Name)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// serviceEnvironment returns the entries of a service's environment block,
+// in either map or KEY=VALUE list form. Keys listed without a value pass
+// through from the shell and are returned with an empty value.
+func serviceEnvironment(svc *composeService) []envEntry {
+	var entries []envEntry
+
+	node := svc.Field("environment")
+	if node == nil {
+		return entries
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], resolveAlias(node.Content[i+1])
+			entry := envEntry{Key: key.Value, Line: key.Line}
+			if value.Tag != "!!null" {
+				entry.Value = value.Value
+			}
+			entries = append(entries, entry)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			key, value, _ := strings.Cut(item.Value, "=")
+			entries = append(entries, envEntry{Key: key, Value: value, Line: item.Line})
+		}
+	}
+
+	return entries
+}
+
+// buildArgs returns the entries of a service's build.args, in either map or
+// KEY=VALUE list form. A build given as a plain context path has no args.
+func buildArgs(svc *composeService) []envEntry {
+	var entries []envEntry
+
+	node := mappingValue(svc.Field("build"), "args")
+	if node == nil {
+		return entries
+	}
+
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], resolveAlias(node.Content[i+1])
+			entry := envEntry{Key: key.Value, Line: key.Line}
+			if value.Tag != "!!null" {
+				entry.Value = value.Value
+			}
+			entries = append(entries, entry)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			key, value, _ := strings.Cut(item.Value, "=")
+			entries = append(entries, envEntry{Key: key, Value: value, Line: item.Line})
+		}
+	}
+
+	return entries
+}
+
+// checkBuildArgRefs flags build.args that interpolate variables no env file
+// defines. Compose substitutes an empty string, so the build sees an empty
+// arg rather than failing.
+func checkBuildArgRefs(docs []*composeDoc, filter serviceFilter, definedVars map[string]bool) []*models.Finding {
+	var findings []*models.Finding
+
+	isDefined := func(name string) bool {
+		return definedVars[name] || isStandardVar(name)
+	}
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+			for _, arg := range buildArgs(svc) {
+				for _, name := range undefinedRefs(arg.Value, isDefined) {
+					findings = append(findings, models.NewFinding(
+						"CMP029",
+						models.SeverityWarning,
+						fmt.Sprintf("Build arg %s of service %s uses undefined ${%s}", arg.Key, svc.Name, name),
+					).WithDetails(fmt.Sprintf("Compose substitutes an empty string for ${%s}, so %s builds with an empty %s and the image may not behave as expected", name, svc.Name, arg.Key)).
+						WithFile(doc.Path, arg.Line).
+						WithFix(fmt.Sprintf("Add %s=<value> to .env, or give it a default with ${%s:-value}", name, name)).
+						WithFixCommand(appendEnvKey(".env", name)))
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// requiredValue is a service field that compose can't run with when empty
+type requiredValue struct {
+	Field string
+	Value string
+	Line  int
+}
+
+// requiredValues returns a service's image, ports and volume sources, the
+// positions where an empty interpolation leaves an invalid compose file
+func requiredValues(svc *composeService) []requiredValue {
+	var values []requiredValue
+
+	if node := svc.Field("image"); node != nil && node.Kind == yaml.ScalarNode {
+		values = append(values, requiredValue{Field: "image", Value: node.Value, Line: node.Line})
+	}
+
+	if node := svc.Field("ports"); node != nil && node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			switch item.Kind {
+			case yaml.ScalarNode:
+				values = append(values, requiredValue{Field: "ports", Value: item.Value, Line: item.Line})
+			case yaml.MappingNode:
+				for _, key := range []string{"target", "published"} {
+					if v := mappingValue(item, key); v != nil && v.Kind == yaml.ScalarNode {
+						values = append(values, requiredValue{Field: "ports", Value: v.Value, Line: v.Line})
+					}
+				}
+			}
+		}
+	}
+
+	if node := svc.Field("volumes"); node != nil && node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			switch item.Kind {
+			case yaml.ScalarNode:
+				if source, ok := volumeSource(item.Value); ok {
+					values = append(values, requiredValue{Field: "volumes", Value: source, Line: item.Line})
+				}
+			case yaml.MappingNode:
+				if v := mappingValue(item, "source"); v != nil && v.Kind == yaml.ScalarNode {
+					values = append(values, requiredValue{Field: "volumes", Value: v.Value, Line: v.Line})
+				}
+			}
+		}
+	}
+
+	return values
+}
+
+// volumeSource returns the source of a short syntax volume, the part before
+// the first colon outside ${...}. Anonymous volumes have no source.
+func volumeSource(spec string) (string, bool) {
+	depth := 0
+	for i := 0; i < len(spec); i++ {
+		switch spec[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				return spec[:i], true
+			}
+		}
+	}
+	return "", false
+}
+
+// checkRequiredInterpolations flags undefined variables without a default in
+// image, ports and volume sources. Unlike optional fields, an empty value
+// there leaves compose with an invalid image reference, port or mount.
+func checkRequiredInterpolations(docs []*composeDoc, filter serviceFilter, definedVars map[string]bool) []*models.Finding {
+	var findings []*models.Finding
+
+	isDefined := func(name string) bool {
+		return definedVars[name] || isStandardVar(name)
+	}
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+			for _, value := range requiredValues(svc) {
+				for _, name := range undefinedRefs(value.Value, isDefined) {
+					findings = append(findings, models.NewFinding(
+						"CMP035",
+						models.SeverityWarning,
+						fmt.Sprintf("Service %s %s uses undefined ${%s}", svc.Name, value.Field, name),
+					).WithDetails(fmt.Sprintf("%s has no default, so compose substitutes an empty string and %s of %s becomes invalid (%s)", name, value.Field, svc.Name, value.Value)).
+						WithFile(doc.Path, value.Line).
+						WithFix(fmt.Sprintf("Add %s=<value> to .env, or give it a default with ${%s:-value}", name, name)).
+						WithFixCommand(appendEnvKey(".env", name)))
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// checkEnvironmentOverrides flags service environment entries that set a
+// key from .env to a different value. Compose gives environment precedence,
+// so edits to .env have no effect on that service.
+func checkEnvironmentOverrides(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	envValues := parseEnvFile(fsys, ".env")
+	if len(envValues) == 0 {
+		return findings
+	}
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+			for _, entry := range serviceEnvironment(svc) {
+				if !overridesDotEnv(entry, envValues) {
+					continue
+				}
+				envValue := envValues[entry.Key]
+
+				findings = append(findings, models.NewFinding(
+					"CMP023",
+					models.SeverityInfo,
+					fmt.Sprintf("Service %s overrides %s from .env", svc.Name, entry.Key),
+				).WithDetails(fmt.Sprintf("environment sets %s=%s but .env has %s=%s; the environment value wins for %s, so editing .env has no effect there", entry.Key, entry.Value, entry.Key, envValue, svc.Name)).
+					WithFile(doc.Path, entry.Line).
+					WithFix(fmt.Sprintf("Use %s: ${%s} to take the value from .env, or remove the key from .env", entry.Key, entry.Key)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// overridesDotEnv reports whether a service environment entry sets a key
+// from .env to a different value. Interpolated values usually pull from
+// .env rather than override it, and valueless keys pass through unchanged.
+func overridesDotEnv(entry envEntry, envValues map[string]string) bool {
+	envValue, ok := envValues[entry.Key]
+	return ok && entry.Value != envValue && entry.Value != "" && !strings.Contains(entry.Value, "$")
+}
+
+// envFileRef is an env_file entry of a service
+type envFileRef struct {
+	Path string
+	Line int
+}
+
+// serviceEnvFiles returns the env_file entries of a service, given as a
+// string, a list of paths or a list of {path, required} mappings. Paths
+// are resolved relative to the compose file's directory.
+func serviceEnvFiles(doc *composeDoc, svc *composeService) []envFileRef {
+	var refs []envFileRef
+
+	node := svc.Field("env_file")
+	if node == nil {
+		return refs
+	}
+
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	for _, item := range items {
+		item = resolveAlias(item)
+		if p := mappingValue(item, "path"); p != nil {
+			item = p
+		}
+		if item.Kind != yaml.ScalarNode || item.Value == "" {
+			continue
+		}
+		refs = append(refs, envFileRef{Path: filepath.Join(filepath.Dir(doc.Path), item.Value), Line: item.Line})
+	}
+
+	return refs
+}
+
+// checkEnvFileConflicts flags keys a service's env_file sets to a different
+// value than the project .env. The container gets the env_file value; .env
+// only feeds interpolation in the compose file, so editing it has no effect
+// inside the container.
+func checkEnvFileConflicts(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	envValues := parseEnvFile(fsys, ".env")
+	if len(envValues) == 0 {
+		return findings
+	}
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+
+			for _, envFile := range serviceEnvFiles(doc, svc) {
+				if envFile.Path == ".env" {
+					continue
+				}
+				for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+					if !conflictsWithDotEnv(svc, entry, envValues) {
+						continue
+					}
+					envValue := envValues[entry.Key]
+
+					findings = append(findings, models.NewFinding(
+						"CMP030",
+						models.SeverityInfo,
+						fmt.Sprintf("Service %s gets %s from %s, which differs from .env", svc.Name, entry.Key, envFile.Path),
+					).WithDetails(fmt.Sprintf("%s sets %s=%s but .env has %s=%s; %s sees the env_file value, while .env only feeds ${%s} in the compose file", envFile.Path, entry.Key, entry.Value, entry.Key, envValue, svc.Name, entry.Key)).
+						WithFile(doc.Path, envFile.Line).
+						WithFix(fmt.Sprintf("Keep %s in one file, or make both values match", entry.Key)))
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// conflictsWithDotEnv reports whether an env_file entry of a service,
+// other than .env itself, sets a key to a different value than .env.
+// environment wins over both files, so keys it sets are left to CMP023.
+func conflictsWithDotEnv(svc *composeService, entry envEntry, envValues map[string]string) bool {
+	envValue, ok := envValues[entry.Key]
+	if !ok || envValue == entry.Value {
+		return false
+	}
+	for _, env := range serviceEnvironment(svc) {
+		if env.Key == entry.Key {
+			return false
+		}
+	}
+	return true
+}
+
+// dotEnvConflictKeys returns the keys of a service that CMP023 or CMP030
+// already report as differing from .env
+func dotEnvConflictKeys(fsys vfs.FS, doc *composeDoc, svc *composeService, envValues map[string]string) map[string]bool {
+	keys := make(map[string]bool)
+	for _, entry := range serviceEnvironment(svc) {
+		if overridesDotEnv(entry, envValues) {
+			keys[entry.Key] = true
+		}
+	}
+	for _, envFile := range serviceEnvFiles(doc, svc) {
+		if envFile.Path == ".env" {
+			continue
+		}
+		for _, entry := range parseEnvEntries(fsys, envFile.Path) {
+			if conflictsWithDotEnv(svc, entry, envValues) {
+				keys[entry.Key] = true
+			}
+		}
+	}
+	return keys
+}
+
+// maxExtendsDepth bounds how far an extends chain is followed
+const maxExtendsDepth = 64
+
+// extendsTarget returns the service a service extends within the same
+// file, or "" if it extends nothing or a service in another file
+func extendsTarget(svc *composeService) string {
+	node := svc.Field("extends")
+	if node == nil {
+		return ""
+	}
+	if node.Kind == yaml.ScalarNode {
+		return node.Value
+	}
+	if file := mappingValue(node, "file"); file != nil && file.Value != "" {
+		return ""
+	}
+	if service := mappingValue(node, "service"); service != nil {
+		return service.Value
+	}
+	return ""
+}
+
+// checkExtendsCycles flags services whose extends chain loops back on
+// itself, which compose can never resolve. Each cycle is reported once,
+// at its first service by name.
+func checkExtendsCycles(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		services := make(map[string]*composeService, len(doc.Services))
+		for _, svc := range doc.Services {
+			services[svc.Name] = svc
+		}
+
+		reported := make(map[string]bool)
+		for _, svc := range doc.Services {
+			if reported[svc.Name] || !filter.includes(svc.Name) {
+				continue
+			}
+
+			chain := []string{svc.Name}
+			current := svc
+			for depth := 0; depth < maxExtendsDepth; depth++ {
+				next, ok := services[extendsTarget(current)]
+				if !ok {
+					break // no extends, or an unknown/external service
+				}
+				chain = append(chain, next.Name)
+				if next.Name == svc.Name {
+					for _, name := range chain {
+						reported[name] = true
+					}
+					findings = append(findings, models.NewFinding(
+						"CMP024",
+						models.SeverityBlocking,
+						fmt.Sprintf("Circular extends: %s", strings.Join(chain, " → ")),
+					).WithDetails(fmt.Sprintf("Service %s extends itself through %d service(s), so compose cannot resolve its configuration", svc.Name, len(chain)-1)).
+						WithFile(doc.Path, svc.Field("extends").Line).
+						WithFix("Remove extends from one service in the chain"))
+					break
+				}
+				current = next
+			}
+		}
+	}
+
+	return findings
+}
+
+// unknownAnchorRegex extracts the anchor name from yaml.v3's dangling alias error
+var unknownAnchorRegex = regexp.MustCompile(`unknown anchor '([^']*)' referenced`)
+
+// checkUnknownAnchors turns dangling alias parse errors into findings.
+// Such files are otherwise skipped by every compose check.
+func checkUnknownAnchors(parseErrors []composeParseError) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, pe := range parseErrors {
+		match := unknownAnchorRegex.FindStringSubmatch(pe.Err.Error())
+		if match == nil {
+			continue
+		}
+		anchor := match[1]
+
+		// The error has no position, so point at the first use of the alias
+		line := 0
+		for i, l := range strings.Split(string(pe.Content), "\n") {
+			if strings.Contains(l, "*"+anchor) && !strings.HasPrefix(strings.TrimSpace(l), "#") {
+				line = i + 1
+				break
+			}
+		}
+
+		findings = append(findings, models.NewFinding(
+			"CMP025",
+			models.SeverityBlocking,
+			fmt.Sprintf("Alias *%s references undefined anchor in %s", anchor, pe.Path),
+		).WithDetails(fmt.Sprintf("No &%s anchor is defined before it is used, so %s cannot be parsed and its services are not checked", anchor, pe.Path)).
+			WithFile(pe.Path, line).
+			WithFix(fmt.Sprintf("Define &%s before the alias (anchors must come first) or fix the alias name", anchor)))
+	}
+
+	return findings
+}
+
+// checkLinks notes services that use legacy links, which the default
+// network's service discovery replaces, and flags links to services no
+// compose file defines
+func checkLinks(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	// Override files can link to services from other files
+	defined := make(map[string]bool)
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			defined[svc.Name] = true
+		}
+	}
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			node := svc.Field("links")
+			if node == nil || node.Kind != yaml.SequenceNode || !filter.includes(svc.Name) {
+				continue
+			}
+
+			for _, item := range node.Content {
+				name, alias, _ := strings.Cut(item.Value, ":")
+				if !defined[name] {
+					findings = append(findings, models.NewFinding(
+						"CMP001",
+						models.SeverityBlocking,
+						fmt.Sprintf("Service %s links to unknown service %s", svc.Name, name),
+					).WithDetails(fmt.Sprintf("links references %s which is not defined in any compose file", name)).
+						WithFile(doc.Path, item.Line).
+						WithFix(fmt.Sprintf("Add service %s or remove it from %s's links", name, svc.Name)))
+					continue
+				}
+
+				fix := fmt.Sprintf("Remove the link and reach %s by its service name; add it to depends_on if %s needs it started first", name, svc.Name)
+				if alias != "" {
+					fix = fmt.Sprintf("Remove the link and give %s the network alias %s (networks.<name>.aliases), adding it to depends_on if %s needs it started first", name, alias, svc.Name)
+				}
+				findings = append(findings, models.NewFinding(
+					"CMP033",
+					models.SeverityInfo,
+					fmt.Sprintf("Service %s uses legacy link to %s", svc.Name, name),
+				).WithDetails(fmt.Sprintf("links is deprecated; services on a shared network already reach %s by name", name)).
+					WithFile(doc.Path, item.Line).
+					WithFix(fix))
+			}
+		}
+	}
+
+	return findings
+}
+
+// containerNameRef is where a service sets its container_name
+type containerNameRef struct {
+	Service string
+	Path    string
+	Line    int
+}
+
+// checkContainerNameCollisions flags services that set the same
+// container_name; docker refuses to create the second container. An
+// override file's container_name replaces the base file's for the same
+// service, as in a merged project.
+func checkContainerNameCollisions(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	byService := make(map[string]string)
+	refs := make(map[string]containerNameRef)
+	var order []string
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			node := svc.Field("container_name")
+			if node == nil || node.Kind != yaml.ScalarNode || node.Value == "" || strings.Contains(node.Value, "$") {
+				continue
+			}
+			if _, ok := byService[svc.Name]; !ok {
+				order = append(order, svc.Name)
+			}
+			byService[svc.Name] = node.Value
+			refs[svc.Name] = containerNameRef{Service: svc.Name, Path: doc.Path, Line: node.Line}
+		}
+	}
+
+	byName := make(map[string][]containerNameRef)
+	var names []string
+	for _, service := range order {
+		name := byService[service]
+		if _, ok := byName[name]; !ok {
+			names = append(names, name)
+		}
+		byName[name] = append(byName[name], refs[service])
+	}
+
+	for _, name := range names {
+		users := byName[name]
+		if len(users) < 2 {
+			continue
+		}
+
+		var services []string
+		included := false
+		for _, u := range users {
+			services = append(services, u.Service)
+			included = included || filter.includes(u.Service)
+		}
+		if !included {
+			continue
+		}
+
+		finding := models.NewFinding(
+			"CMP034",
+			models.SeverityBlocking,
+			fmt.Sprintf("Services %s share container_name %s", strings.Join(services, ", "), name),
+		).WithDetails(fmt.Sprintf("container_name must be unique: once %s exists, creating the container for %s fails with a name conflict", services[0], strings.Join(services[1:], ", "))).
+			WithFix("Give each service its own container_name, or remove it and let compose name the containers")
+		for _, u := range users {
+			finding.WithFile(u.Path, u.Line)
+		}
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// serviceReferences returns the services svc needs: depends_on, links,
+// extends, network_mode: service:x and volumes_from entries
+func serviceReferences(svc *composeService) []string {
+	refs := extractDependsOn(&svc.Spec.DependsOn)
+
+	if target := extendsTarget(svc); target != "" {
+		refs = append(refs, target)
+	}
+
+	if mode := svc.Field("network_mode"); mode != nil {
+		if name, ok := strings.CutPrefix(mode.Value, "service:"); ok {
+			refs = append(refs, name)
+		}
+	}
+
+	for _, key := range []string{"links", "volumes_from"} {
+		node := svc.Field(key)
+		if node == nil || node.Kind != yaml.SequenceNode {
+			continue
+		}
+		for _, item := range node.Content {
+			value := strings.TrimPrefix(item.Value, "service:")
+			if strings.HasPrefix(value, "container:") {
+				continue
+			}
+			// links use service:alias, volumes_from use service:ro
+			name, _, _ := strings.Cut(value, ":")
+			refs = append(refs, name)
+		}
+	}
+
+	return refs
+}
+
+// checkUnreferencedServices flags services that nothing references, that
+// publish no ports and that only start under a non-default profile. Such
+// services are often leftovers, but one-off tools and workers look the
+// same, so this stays informational.
+func checkUnreferencedServices(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	// Override files can reference or add ports to services from other
+	// files, so build the picture across every compose file
+	referenced := make(map[string]bool)
+	published := make(map[string]bool)
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			for _, ref := range serviceReferences(svc) {
+				if ref != svc.Name {
+					referenced[ref] = true
+				}
+			}
+			if svc.HasField("ports") {
+				published[svc.Name] = true
+			}
+		}
+	}
+
+	reported := make(map[string]bool)
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if len(svc.Spec.Profiles) == 0 || referenced[svc.Name] || published[svc.Name] || reported[svc.Name] || !filter.includes(svc.Name) {
+				continue
+			}
+			reported[svc.Name] = true
+
+			findings = append(findings, models.NewFinding(
+				"CMP027",
+				models.SeverityInfo,
+				fmt.Sprintf("Service %s may be unused", svc.Name),
+			).WithDetails(fmt.Sprintf("No service depends on %s, it publishes no ports and it only starts with profile %s; if it isn't a one-off tool or worker, it may be dead config", svc.Name, strings.Join(svc.Spec.Profiles, " or "))).
+				WithFile(doc.Path, svc.Line).
+				WithFix(fmt.Sprintf("Remove %s if nothing uses it, or add it to ignore_codes if it is run on purpose", svc.Name)))
+		}
+	}
+
+	return findings
+}
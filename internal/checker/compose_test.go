@@ -0,0 +1,374 @@
+package checker
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestImageRegistry(t *testing.T) {
+	tests := map[string]string{
+		"postgres:16":                        "",
+		"bitnami/kafka:3":                    "",
+		"docker.io/library/redis:7":          "",
+		"registry.company.com/team/app:1.2":  "registry.company.com",
+		"localhost:5000/mirror/nginx":        "localhost:5000",
+		"localhost/app":                      "localhost",
+		"ghcr.io/org/tool@sha256:0123456789": "ghcr.io",
+	}
+
+	for image, expected := range tests {
+		if got := imageRegistry(image); got != expected {
+			t.Errorf("imageRegistry(%q) = %q, expected %q", image, got, expected)
+		}
+	}
+}
+
+func TestCheckPrivateRegistries(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/private-registry")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP028" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// api builds its image, so it is never pulled
+	expected := []string{
+		"Service app pulls from registry registry.company.com",
+		"Service ghcr pulls from registry ghcr.io",
+		"Service mirror pulls from registry localhost:5000",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckBuildArgRefs(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/build-args")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP029" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Defined and defaulted variables are fine, as are args without a value
+	expected := []string{
+		"Build arg GIT_SHA of service api uses undefined ${GIT_SHA}",
+		"Build arg NPM_TOKEN of service worker uses undefined ${NPM_TOKEN}",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckEnvFileConflicts(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-file-conflict")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP030" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Matching values, keys set by environment and .env itself are fine
+	expected := []string{
+		"Service api gets DATABASE_URL from app.env, which differs from .env",
+		"Service worker gets LOG_LEVEL from worker.env, which differs from .env",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckResourceLimits(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/resource-limits")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP031" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Limited services and light images are fine
+	expected := []string{
+		"Service db runs postgres:16 without resource limits",
+		"Service search runs docker.elastic.co/elasticsearch/elasticsearch:8.13.0 without resource limits",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckDependsOnReadiness(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/depends-ready")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP032" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// db has a healthcheck and worker is not a database
+	expected := []string{
+		"Service api starts as soon as cache's container starts, not when it is ready",
+		"Service web starts as soon as queue's container starts, not when it is ready",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckLinks(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/links")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP033" || f.Code == "CMP001" {
+			titles = append(titles, f.Code+" "+f.Title)
+		}
+	}
+
+	expected := []string{
+		"CMP033 Service api uses legacy link to db",
+		"CMP033 Service api uses legacy link to cache",
+		"CMP001 Service api links to unknown service search",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckServiceLocalEnv(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/service-env")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var undefined []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV001" {
+			undefined = append(undefined, f)
+		}
+	}
+
+	// api reads API_TOKEN from services/api/.env, including inside its
+	// multi-line command; web has no such file
+	if len(undefined) != 1 {
+		t.Fatalf("expected 1 ENV001 finding, got %d", len(undefined))
+	}
+	if loc := undefined[0].Files[0]; loc.File != "compose.yaml" || loc.Line != 13 {
+		t.Errorf("expected ENV001 at compose.yaml:13, got %s:%d", loc.File, loc.Line)
+	}
+}
+
+func TestCheckContainerNameCollisions(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/container-names")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP034" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// The override renames cache to db, colliding with db; db repeating
+	// its own name in the override is not a collision
+	expected := []string{
+		"Services api, worker share container_name app",
+		"Services cache, db share container_name db",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckEnvSourcePrecedence(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-sources")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var chains []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV029" {
+			chains = append(chains, f)
+		}
+	}
+
+	// DATABASE_URL is interpolated from .env rather than set twice,
+	// CACHE_TTL has one source, and LOG_LEVEL and PORT differ from .env,
+	// which CMP023 and CMP030 report instead
+	if len(chains) != 2 {
+		t.Fatalf("expected 2 ENV029 findings, got %d", len(chains))
+	}
+
+	expected := []struct {
+		title   string
+		details string
+	}{
+		{"TIMEOUT for service api is set in 2 places", "Precedence, highest first: env_file config/local.env:2 (30) > env_file config/base.env:4 (10). api sees TIMEOUT=30"},
+		{"WORKERS for service api is set in 2 places", "Precedence, highest first: environment compose.yaml:10 (4) > env_file config/base.env:5 (2). api sees WORKERS=4"},
+	}
+	for i, e := range expected {
+		if chains[i].Title != e.title {
+			t.Errorf("expected %q, got %q", e.title, chains[i].Title)
+		}
+		if chains[i].Details != e.details {
+			t.Errorf("expected details %q, got %q", e.details, chains[i].Details)
+		}
+	}
+
+	// Each key is explained by ENV029 or reported by CMP023/CMP030, not both
+	perKey := make(map[string][]string)
+	for _, f := range findings {
+		switch f.Code {
+		case "ENV029", "CMP023", "CMP030":
+			for _, key := range []string{"LOG_LEVEL", "PORT", "TIMEOUT", "WORKERS"} {
+				if strings.Contains(f.Title, key+" ") {
+					perKey[key] = append(perKey[key], f.Code)
+				}
+			}
+		}
+	}
+	for key, code := range map[string]string{"LOG_LEVEL": "CMP023", "PORT": "CMP030", "TIMEOUT": "ENV029", "WORKERS": "ENV029"} {
+		if len(perKey[key]) == 0 {
+			t.Errorf("expected %s to get a %s finding", key, code)
+		}
+		for _, got := range perKey[key] {
+			if got != code {
+				t.Errorf("expected %s to get only %s findings, got %v", key, code, perKey[key])
+				break
+			}
+		}
+	}
+}
+
+func TestCheckRequiredInterpolations(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/required-interp")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP035" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Defaults, defined variables and optional fields like environment are
+	// left to ENV001
+	expected := []string{
+		"Service api image uses undefined ${API_IMAGE}",
+		"Service api ports uses undefined ${API_PORT}",
+		"Service api volumes uses undefined ${CACHE_DIR}",
+		"Service worker volumes uses undefined ${WORKER_SRC}",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckServiceLocalEnvNestedCompose(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/service-env-nested")
+	artifacts := detector.Detect(basePath, filepath.Join(basePath, "deploy", "compose.yaml"), nil)
+
+	// The build context is relative to deploy/, so api's .env is found
+	// under services/ and scoped to api
+	var local []models.Artifact
+	for _, e := range artifacts.EnvFiles {
+		if len(e.Services) > 0 {
+			local = append(local, e)
+		}
+	}
+	if len(local) != 1 || local[0].Path != filepath.Join("services", "api", ".env") || len(local[0].Services) != 1 || local[0].Services[0] != "api" {
+		t.Fatalf("expected services/api/.env scoped to api, got %+v", local)
+	}
+
+	findings := Check(basePath, artifacts)
+
+	var undefined []*models.Finding
+	for _, f := range findings {
+		if f.Code == "ENV001" {
+			undefined = append(undefined, f)
+		}
+	}
+	if len(undefined) != 1 {
+		t.Fatalf("expected 1 ENV001 finding, got %d", len(undefined))
+	}
+	if loc := undefined[0].Files[0]; loc.File != filepath.Join("deploy", "compose.yaml") || loc.Line != 11 {
+		t.Errorf("expected ENV001 at deploy/compose.yaml:11, got %s:%d", loc.File, loc.Line)
+	}
+
+	// Checks on env files cover the service-local one too
+	var newline []string
+	for _, f := range findings {
+		if f.Code == "ENV020" {
+			newline = append(newline, f.Files[0].File)
+		}
+	}
+	if len(newline) != 1 || newline[0] != filepath.Join("services", "api", ".env") {
+		t.Errorf("expected ENV020 for services/api/.env, got %v", newline)
+	}
+}
@@ -0,0 +1,192 @@
+package checker
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// devcontainerConfig holds the devcontainer.json fields devcheck validates
+type devcontainerConfig struct {
+	DockerComposeFile json.RawMessage `json:"dockerComposeFile"`
+	DockerFile        string          `json:"dockerFile"`
+	Build             struct {
+		Dockerfile string `json:"dockerfile"`
+		Context    string `json:"context"`
+	} `json:"build"`
+	RemoteEnv    map[string]string `json:"remoteEnv"`
+	ContainerEnv map[string]string `json:"containerEnv"`
+}
+
+// localEnvRegex matches ${localEnv:VAR} and ${localEnv:VAR:default}
+var localEnvRegex = regexp.MustCompile(`\$\{localEnv:([A-Za-z_][A-Za-z0-9_]*)(:[^}]*)?\}`)
+
+// stripJSONC removes // and /* */ comments and trailing commas, which
+// devcontainer.json allows but encoding/json does not
+func stripJSONC(content []byte) []byte {
+	var out []byte
+	inString := false
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		if inString {
+			out = append(out, ch)
+			if ch == '\\' && i+1 < len(content) {
+				i++
+				out = append(out, content[i])
+			} else if ch == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch {
+		case ch == '"':
+			inString = true
+			out = append(out, ch)
+		case ch == '/' && i+1 < len(content) && content[i+1] == '/':
+			for i < len(content) && content[i] != '\n' {
+				i++
+			}
+			if i < len(content) {
+				out = append(out, '\n')
+			}
+		case ch == '/' && i+1 < len(content) && content[i+1] == '*':
+			i += 2
+			for i+1 < len(content) && !(content[i] == '*' && content[i+1] == '/') {
+				if content[i] == '\n' {
+					out = append(out, '\n') // keep line numbers stable
+				}
+				i++
+			}
+			i++
+		case ch == '}' || ch == ']':
+			// Drop a trailing comma before the closing bracket
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, ch)
+		default:
+			out = append(out, ch)
+		}
+	}
+	return out
+}
+
+// composeFileList decodes dockerComposeFile, a string or array of strings
+func composeFileList(raw json.RawMessage) []string {
+	if len(raw) == 0 {
+		return nil
+	}
+	var single string
+	if err := json.Unmarshal(raw, &single); err == nil {
+		return []string{single}
+	}
+	var list []string
+	_ = json.Unmarshal(raw, &list)
+	return list
+}
+
+// lineOf returns the 1-based line of the first occurrence of needle, or 0
+func lineOf(content []byte, needle string) int {
+	i := strings.Index(string(content), needle)
+	if i < 0 {
+		return 0
+	}
+	return strings.Count(string(content[:i]), "\n") + 1
+}
+
+// sortedKeys returns the keys of m in order, for deterministic findings
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// checkDevcontainer validates file and variable references in
+// devcontainer.json. Paths in it are relative to the file itself.
+func checkDevcontainer(fsys vfs.FS, artifacts *models.Artifacts, definedVars map[string]bool) []*models.Finding {
+	var findings []*models.Finding
+
+	if artifacts.Devcontainer == nil || !artifacts.Devcontainer.Found {
+		return findings
+	}
+	path := artifacts.Devcontainer.Path
+
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return findings
+	}
+
+	var cfg devcontainerConfig
+	if err := json.Unmarshal(stripJSONC(content), &cfg); err != nil {
+		return append(findings, models.NewFinding(
+			"DEVC001",
+			models.SeverityWarning,
+			fmt.Sprintf("%s could not be parsed", path),
+		).WithDetails(err.Error()).
+			WithFile(path, 0))
+	}
+
+	dir := filepath.Dir(path)
+	missingFile := func(field, ref string) {
+		target := filepath.Join(dir, ref)
+		if _, err := fsys.Stat(target); err == nil {
+			return
+		}
+		findings = append(findings, models.NewFinding(
+			"DEVC001",
+			models.SeverityWarning,
+			fmt.Sprintf("%s references missing file %s", path, ref),
+		).WithDetails(fmt.Sprintf("%s points to %s, which does not exist, so the dev container will fail to build", field, target)).
+			WithFile(path, lineOf(content, `"`+ref+`"`)).
+			WithFix(fmt.Sprintf("Create %s or fix %s", target, field)))
+	}
+
+	for _, ref := range composeFileList(cfg.DockerComposeFile) {
+		missingFile("dockerComposeFile", ref)
+	}
+	if cfg.Build.Dockerfile != "" {
+		// build.dockerfile is relative to devcontainer.json, not the context
+		missingFile("build.dockerfile", cfg.Build.Dockerfile)
+	}
+	if cfg.DockerFile != "" {
+		missingFile("dockerFile", cfg.DockerFile)
+	}
+
+	for _, env := range []struct {
+		field  string
+		values map[string]string
+	}{{"remoteEnv", cfg.RemoteEnv}, {"containerEnv", cfg.ContainerEnv}} {
+		for _, key := range sortedKeys(env.values) {
+			for _, match := range localEnvRegex.FindAllStringSubmatch(env.values[key], -1) {
+				name := match[1]
+				if match[2] != "" || definedVars[name] || os.Getenv(name) != "" || isStandardVar(name) {
+					continue
+				}
+				findings = append(findings, models.NewFinding(
+					"DEVC001",
+					models.SeverityWarning,
+					fmt.Sprintf("%s.%s uses undefined ${localEnv:%s}", env.field, key, name),
+				).WithDetails(fmt.Sprintf("%s is not set in your environment or any .env file, so %s will be empty in the dev container", name, key)).
+					WithFile(path, lineOf(content, `"`+key+`"`)).
+					WithFix(fmt.Sprintf("Export %s, or use ${localEnv:%s:default}", name, name)))
+			}
+		}
+	}
+
+	return findings
+}
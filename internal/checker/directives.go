@@ -0,0 +1,145 @@
+package checker
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// directiveRegex matches a "# devcheck: ..." comment, on its own line or
+// after a value
+var directiveRegex = regexp.MustCompile(`(?:^|\s)#\s*devcheck:\s*(.*)$`)
+
+// directiveCodeRegex matches a finding code named in a directive
+var directiveCodeRegex = regexp.MustCompile(`^[A-Z]+[0-9]+$`)
+
+// allowKeywords map "allow-<keyword>" directives to the codes they suppress
+var allowKeywords = map[string][]string{
+	"latest":           {"CMP021"},
+	"port-conflict":    {"CMP002"},
+	"privileged-port":  {"CMP026"},
+	"unused":           {"CMP027"},
+	"private-registry": {"CMP028"},
+	"no-limits":        {"CMP031"},
+	"no-healthcheck":   {"CMP032"},
+	"links":            {"CMP033"},
+	"container-name":   {"CMP034"},
+	"port-mismatch":    {"CMP036"},
+	"undefined-env":    {"ENV001", "CMP029", "CMP035", "CMP037"},
+	"env-override":     {"CMP023", "CMP030"},
+}
+
+// suppression is a compose service's line range and the codes its
+// directives suppress there
+type suppression struct {
+	File  string
+	Start int
+	End   int
+	Codes map[string]bool
+}
+
+// directiveCodes parses the body of a directive, such as
+// "allow-latest, ignore CMP026", into codes. Unknown keywords are skipped.
+func directiveCodes(body string) []string {
+	var codes []string
+	for _, token := range strings.Fields(strings.ReplaceAll(body, ",", " ")) {
+		if keyword, ok := strings.CutPrefix(token, "allow-"); ok {
+			codes = append(codes, allowKeywords[keyword]...)
+		} else if directiveCodeRegex.MatchString(token) {
+			codes = append(codes, token)
+		}
+	}
+	return codes
+}
+
+// indentation returns the number of leading spaces and tabs in line
+func indentation(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+// serviceLineRange returns the first and last lines of a service's block:
+// comment lines directly above its key, through every line indented deeper
+// than the key
+func serviceLineRange(lines []string, keyLine int) (int, int) {
+	keyIndent := indentation(lines[keyLine-1])
+
+	start := keyLine
+	for start > 1 {
+		above := lines[start-2]
+		trimmed := strings.TrimSpace(above)
+		if !strings.HasPrefix(trimmed, "#") || indentation(above) > keyIndent {
+			break
+		}
+		start--
+	}
+
+	end := keyLine
+	for i := keyLine; i < len(lines); i++ {
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" {
+			continue
+		}
+		if indentation(lines[i]) <= keyIndent {
+			if strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			break
+		}
+		end = i + 1
+	}
+
+	return start, end
+}
+
+// composeSuppressions collects the "# devcheck:" directives in each
+// service's block. A directive anywhere in the block, or in the comments
+// directly above the service, applies to the whole service.
+func composeSuppressions(docs []*composeDoc, basePath string) []suppression {
+	var suppressions []suppression
+
+	for _, doc := range docs {
+		if !strings.Contains(string(doc.Content), "devcheck:") {
+			continue
+		}
+		lines := strings.Split(string(doc.Content), "\n")
+
+		for _, svc := range doc.Services {
+			if svc.Line < 1 || svc.Line > len(lines) {
+				continue
+			}
+			start, end := serviceLineRange(lines, svc.Line)
+
+			codes := make(map[string]bool)
+			for _, line := range lines[start-1 : end] {
+				if match := directiveRegex.FindStringSubmatch(line); match != nil {
+					for _, code := range directiveCodes(match[1]) {
+						codes[code] = true
+					}
+				}
+			}
+			if len(codes) > 0 {
+				suppressions = append(suppressions, suppression{File: vfs.RelPath(basePath, doc.Path), Start: start, End: end, Codes: codes})
+			}
+		}
+	}
+
+	return suppressions
+}
+
+// isSuppressed reports whether a directive covers one of the finding's
+// locations. File paths must already be normalized with vfs.RelPath.
+func isSuppressed(f *models.Finding, suppressions []suppression) bool {
+	for _, s := range suppressions {
+		if !s.Codes[f.Code] {
+			continue
+		}
+		for _, loc := range f.Files {
+			if loc.File == s.File && loc.Line >= s.Start && loc.Line <= s.End {
+				return true
+			}
+		}
+	}
+	return false
+}
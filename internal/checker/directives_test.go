@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestDirectiveCodes(t *testing.T) {
+	tests := []struct {
+		body     string
+		expected []string
+	}{
+		{"allow-latest", []string{"CMP021"}},
+		{"allow-no-limits, allow-privileged-port", []string{"CMP031", "CMP026"}},
+		{"ignore CMP026 ENV001", []string{"CMP026", "ENV001"}},
+		{"allow-undefined-env", []string{"ENV001", "CMP029", "CMP035", "CMP037"}},
+		{"allow-everything please", nil},
+	}
+
+	for _, tt := range tests {
+		got := directiveCodes(tt.body)
+		if len(got) != len(tt.expected) {
+			t.Errorf("directiveCodes(%q) = %v, want %v", tt.body, got, tt.expected)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.expected[i] {
+				t.Errorf("directiveCodes(%q) = %v, want %v", tt.body, got, tt.expected)
+				break
+			}
+		}
+	}
+}
+
+func TestComposeDirectives(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/compose-directives")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP026" || f.Code == "CMP031" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// web's directive above the service key and db's on its image line
+	// apply to their own service only; proxy ignores a code it doesn't have
+	expected := []string{
+		"Service proxy publishes privileged host port 443",
+		"Service search runs elasticsearch:8.13.0 without resource limits",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// dockerfileImage is a base image from a FROM instruction
+type dockerfileImage struct {
+	Image string
+	Line  int
+}
+
+// dockerfilePaths returns the Dockerfiles to inspect: the one at the
+// project root and the ones compose services build from
+func dockerfilePaths(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []string {
+	var paths []string
+	seen := make(map[string]bool)
+
+	addPath := func(p string) {
+		p = filepath.Clean(p)
+		if seen[p] || !fileExists(fsys, p) {
+			return
+		}
+		seen[p] = true
+		paths = append(paths, p)
+	}
+
+	addPath("Dockerfile")
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			context := buildContext(doc, svc)
+			if context == "" || !filter.includes(svc.Name) {
+				continue
+			}
+			dockerfile := "Dockerfile"
+			if df := mappingValue(svc.Field("build"), "dockerfile"); df != nil && df.Value != "" {
+				dockerfile = df.Value
+			}
+			addPath(filepath.Join(context, dockerfile))
+		}
+	}
+
+	return paths
+}
+
+// dockerfileBaseImages returns the external images a Dockerfile builds
+// from. ARG defaults are substituted; images that still depend on build
+// args, references to earlier stages and scratch are left out.
+func dockerfileBaseImages(content string) []dockerfileImage {
+	var images []dockerfileImage
+
+	args := make(map[string]string)
+	stages := make(map[string]bool)
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch strings.ToUpper(fields[0]) {
+		case "ARG":
+			name, value, _ := strings.Cut(fields[1], "=")
+			args[name] = strings.Trim(value, `"'`)
+		case "FROM":
+			rest := fields[1:]
+			for len(rest) > 0 && strings.HasPrefix(rest[0], "--") {
+				rest = rest[1:]
+			}
+			if len(rest) == 0 {
+				continue
+			}
+
+			image := expandArgs(rest[0], args)
+			if image != "" && !strings.Contains(image, "$") && image != "scratch" && !stages[strings.ToLower(image)] {
+				images = append(images, dockerfileImage{Image: image, Line: lineNum})
+			}
+			if len(rest) >= 3 && strings.EqualFold(rest[1], "AS") {
+				stages[strings.ToLower(rest[2])] = true
+			}
+		}
+	}
+
+	return images
+}
+
+// expandArgs substitutes $NAME and ${NAME} with ARG defaults, leaving
+// args without a default in place
+func expandArgs(s string, args map[string]string) string {
+	return os.Expand(s, func(name string) string {
+		if value, ok := args[name]; ok && value != "" {
+			return value
+		}
+		return "${" + name + "}"
+	})
+}
+
+// checkDockerfileBaseImages flags FROM lines that pull latest or an untagged
+// image, which changes whenever upstream publishes and makes local builds
+// unreproducible. Digest-pinned images are fine.
+func checkDockerfileBaseImages(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, path := range dockerfilePaths(fsys, docs, filter) {
+		content, err := fsys.ReadFile(path)
+		if err != nil {
+			continue
+		}
+
+		for _, from := range dockerfileBaseImages(string(content)) {
+			repo, tag, digest := splitImageTag(from.Image)
+			if digest != "" || (tag != "" && tag != "latest") {
+				continue
+			}
+
+			reason := fmt.Sprintf("%s has no tag, so docker pulls latest", from.Image)
+			if tag == "latest" {
+				reason = fmt.Sprintf("%s is pinned to latest", from.Image)
+			}
+			findings = append(findings, models.NewFinding(
+				"DKR003",
+				models.SeverityInfo,
+				fmt.Sprintf("%s builds from unpinned image %s", path, from.Image),
+			).WithDetails(fmt.Sprintf("%s; the base image changes whenever upstream publishes, so builds on different machines can differ", reason)).
+				WithFile(path, from.Line).
+				WithFix(fmt.Sprintf("Pin a version, e.g. FROM %s:<version>", repo)))
+		}
+	}
+
+	return findings
+}
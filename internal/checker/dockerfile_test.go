@@ -0,0 +1,59 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestDockerfileBaseImages(t *testing.T) {
+	content := `ARG NODE_VERSION=20
+ARG BASE
+FROM node:${NODE_VERSION} AS build
+FROM ${BASE}
+from build AS test
+FROM --platform=$BUILDPLATFORM nginx
+`
+
+	images := dockerfileBaseImages(content)
+	expected := []dockerfileImage{
+		{Image: "node:20", Line: 3},
+		{Image: "nginx", Line: 6},
+	}
+	if len(images) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, images)
+	}
+	for i := range expected {
+		if images[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], images[i])
+		}
+	}
+}
+
+func TestCheckDockerfileBaseImages(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/dockerfile-tags")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "DKR003" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Tagged, digest-pinned, arg-only, stage and scratch images are fine
+	expected := []string{
+		"Dockerfile builds from unpinned image alpine",
+		"api/Dockerfile builds from unpinned image node:latest",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package checker
+
+import (
+	"bufio"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// maxEnvIncludeDepth bounds how far env file includes are followed
+const maxEnvIncludeDepth = 32
+
+// envIncludeRegex matches a shell-style include in an env file meant to be
+// sourced, such as "source .env.shared" or ". ../common.env"
+var envIncludeRegex = regexp.MustCompile(`^(?:source|\.)\s+["']?([^"'\s]+)["']?\s*$`)
+
+// envInclude is an env file pulled in by another one
+type envInclude struct {
+	Path string
+	Line int
+}
+
+// envIncludes returns the files an env file sources, resolved relative to
+// the env file's directory
+func envIncludes(fsys vfs.FS, name string) []envInclude {
+	var includes []envInclude
+
+	file, err := fsys.Open(name)
+	if err != nil {
+		return includes
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		match := envIncludeRegex.FindStringSubmatch(strings.TrimSpace(scanner.Text()))
+		if match == nil {
+			continue
+		}
+		includes = append(includes, envInclude{Path: filepath.Join(filepath.Dir(name), match[1]), Line: lineNum})
+	}
+
+	return includes
+}
+
+// checkEnvIncludeCycles follows includes from the project env files and
+// every service's env_file entries and flags env files that end up
+// including themselves, which loops forever when sourced. Each cycle is
+// reported once, however many roots reach it.
+func checkEnvIncludeCycles(fsys vfs.FS, artifacts *models.Artifacts, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	var roots []string
+	for _, e := range artifacts.EnvFiles {
+		if e.Found {
+			roots = append(roots, filepath.Clean(e.Path))
+		}
+	}
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+			for _, ref := range serviceEnvFiles(doc, svc) {
+				roots = append(roots, filepath.Clean(ref.Path))
+			}
+		}
+	}
+
+	reported := make(map[string]bool)
+	visited := make(map[string]bool)
+	var stack []string
+	var follow func(path string)
+	follow = func(path string) {
+		if visited[path] || len(stack) >= maxEnvIncludeDepth {
+			return
+		}
+		stack = append(stack, path)
+		defer func() { stack = stack[:len(stack)-1] }()
+
+		for _, inc := range envIncludes(fsys, path) {
+			start := -1
+			for i, p := range stack {
+				if p == inc.Path {
+					start = i
+					break
+				}
+			}
+			if start < 0 {
+				follow(inc.Path)
+				continue
+			}
+
+			cycle := append(append([]string{}, stack[start:]...), inc.Path)
+			key := cycleKey(stack[start:])
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+			findings = append(findings, models.NewFinding(
+				"ENV028",
+				models.SeverityBlocking,
+				fmt.Sprintf("Circular env file include: %s", strings.Join(cycle, " → ")),
+			).WithDetails(fmt.Sprintf("%s includes %s, which leads back to it, so sourcing either file never finishes", path, inc.Path)).
+				WithFile(path, inc.Line).
+				WithFix(fmt.Sprintf("Remove the include of %s from %s", inc.Path, path)))
+		}
+		visited[path] = true
+	}
+
+	for _, root := range roots {
+		follow(root)
+	}
+
+	return findings
+}
+
+// cycleKey identifies an include cycle whichever file it was entered from,
+// by rotating it to start at its smallest path. Cycles that share files
+// but not every include get different keys.
+func cycleKey(cycle []string) string {
+	start := 0
+	for i, p := range cycle {
+		if p < cycle[start] {
+			start = i
+		}
+	}
+	rotated := append(append([]string{}, cycle[start:]...), cycle[:start]...)
+	return strings.Join(rotated, "\x00")
+}
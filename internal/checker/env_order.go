@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// sharedKeyOrder returns the keys of entries that are also in shared, in
+// file order, keeping the first occurrence of each
+func sharedKeyOrder(entries []envEntry, shared map[string]bool) []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if shared[entry.Key] && !seen[entry.Key] {
+			seen[entry.Key] = true
+			keys = append(keys, entry.Key)
+		}
+	}
+	return keys
+}
+
+// checkEnvKeyOrder reports when the keys .env and .env.example share appear
+// in a different order, which makes the two files harder to diff. Only run
+// when check_key_order is enabled.
+func checkEnvKeyOrder(fsys vfs.FS, artifacts *models.Artifacts, precedence []string) []*models.Finding {
+	var examplePath string
+	for _, e := range artifacts.EnvExamples {
+		if e.Found {
+			examplePath = e.Path
+			break
+		}
+	}
+	ranked := rankedEnvFiles(artifacts, precedence)
+	if examplePath == "" || len(ranked) == 0 {
+		return nil
+	}
+	envPath := ranked[0].Path
+
+	exampleEntries := parseEnvEntries(fsys, examplePath)
+	envEntries := parseEnvEntries(fsys, envPath)
+
+	inExample := make(map[string]bool)
+	for _, entry := range exampleEntries {
+		inExample[entry.Key] = true
+	}
+	shared := make(map[string]bool)
+	lines := make(map[string]int)
+	for _, entry := range envEntries {
+		if inExample[entry.Key] && !shared[entry.Key] {
+			shared[entry.Key] = true
+			lines[entry.Key] = entry.Line
+		}
+	}
+
+	want := sharedKeyOrder(exampleEntries, shared)
+	got := sharedKeyOrder(envEntries, shared)
+	for i := range want {
+		if want[i] == got[i] {
+			continue
+		}
+		return []*models.Finding{models.NewFinding(
+			"ENV026",
+			models.SeverityInfo,
+			fmt.Sprintf("%s orders keys differently from %s", envPath, examplePath),
+		).WithDetails(fmt.Sprintf("%s is the first shared key out of place: %s expects %s at that position. Shared key order in %s: %s", got[i], examplePath, want[i], examplePath, strings.Join(want, ", "))).
+			WithFile(envPath, lines[got[i]]).
+			WithFix(fmt.Sprintf("Reorder %s to follow %s so the two files diff cleanly", envPath, examplePath))}
+	}
+	return nil
+}
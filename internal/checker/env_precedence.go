@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// defaultEnvPrecedence is the order devcheck prefers env files in when
+// env_precedence is not configured; the first file found wins
+var defaultEnvPrecedence = []string{".env", ".env.local", ".env.development", ".env.dev"}
+
+// envPrecedence returns the configured env file order, or the default
+func envPrecedence(cfg *config.Config) []string {
+	if cfg != nil && len(cfg.EnvPrecedence) > 0 {
+		return cfg.EnvPrecedence
+	}
+	return defaultEnvPrecedence
+}
+
+// precedenceRank returns the position of an env file in precedence,
+// matching either its full path or its file name. Unlisted files rank last.
+func precedenceRank(path string, precedence []string) int {
+	for i, name := range precedence {
+		if name == path || name == filepath.Base(path) {
+			return i
+		}
+	}
+	return len(precedence)
+}
+
+// rankedEnvFiles returns the found project-wide env files, highest
+// precedence first. Files with the same rank keep their detection order.
+func rankedEnvFiles(artifacts *models.Artifacts, precedence []string) []models.Artifact {
+	var found []models.Artifact
+	for _, e := range artifacts.EnvFiles {
+		if e.Found && len(e.Services) == 0 {
+			found = append(found, e)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return precedenceRank(found[i].Path, precedence) < precedenceRank(found[j].Path, precedence)
+	})
+	return found
+}
+
+// checkEnvPrecedence reports which env file wins when several exist
+func checkEnvPrecedence(artifacts *models.Artifacts, precedence []string) []*models.Finding {
+	ranked := rankedEnvFiles(artifacts, precedence)
+	if len(ranked) < 2 {
+		return nil
+	}
+
+	paths := make([]string, len(ranked))
+	for i, e := range ranked {
+		paths[i] = e.Path
+	}
+	winner := paths[0]
+
+	return []*models.Finding{models.NewFinding(
+		"ENV023",
+		models.SeverityInfo,
+		fmt.Sprintf("%d env files found; %s takes precedence", len(paths), winner),
+	).WithDetails(fmt.Sprintf("Precedence, highest first: %s. devcheck compares %s against .env.example; variables are considered defined if any of these files sets them", strings.Join(paths, " > "), winner)).
+		WithFile(winner, 0).
+		WithFix("Set env_precedence in .devcheck.yaml if your tooling loads these files in a different order")}
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// envAssignmentRegex matches a line starting a new KEY=value entry, which
+// ends the search for a multiline value's closing quote
+var envAssignmentRegex = regexp.MustCompile(`^\s*(?:export\s+)?[A-Za-z_][A-Za-z0-9_.]*\s*=`)
+
+// unbalancedQuote describes a value whose quotes don't pair up
+type unbalancedQuote struct {
+	Key   string
+	Value string
+	Line  int
+	// Opening is true when the value opens a quote it never closes, and
+	// false when it ends with a quote it never opened
+	Opening bool
+}
+
+// unbalancedQuotes returns the values in env file content with a quote
+// missing at one end, such as KEY="value or KEY=value". An opening quote
+// closed on a later line, before the next entry, is a multiline value and
+// is left alone.
+func unbalancedQuotes(content string) []unbalancedQuote {
+	var found []unbalancedQuote
+
+	lines := strings.Split(strings.TrimPrefix(content, utf8BOM), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if q := value[0]; q == '"' || q == '\'' {
+			if strings.IndexByte(value[1:], q) >= 0 {
+				continue
+			}
+			closed := false
+			for j := i + 1; j < len(lines) && !closed && !envAssignmentRegex.MatchString(lines[j]); j++ {
+				closed = strings.IndexByte(lines[j], q) >= 0
+			}
+			if !closed {
+				found = append(found, unbalancedQuote{Key: key, Value: value, Line: i + 1, Opening: true})
+			}
+			continue
+		}
+
+		if q := value[len(value)-1]; (q == '"' || q == '\'') && strings.Count(value, string(q))%2 == 1 {
+			found = append(found, unbalancedQuote{Key: key, Value: value, Line: i + 1})
+		}
+	}
+
+	return found
+}
+
+// checkEnvQuotes flags env values with a quote at only one end. Loaders
+// disagree on such values: some keep the stray quote as part of the value,
+// others fail or read on into the following lines.
+func checkEnvQuotes(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	candidates := append(append([]models.Artifact{}, artifacts.EnvFiles...), artifacts.EnvExamples...)
+	for _, envFile := range candidates {
+		if !envFile.Found {
+			continue
+		}
+		content, err := fsys.ReadFile(envFile.Path)
+		if err != nil {
+			continue
+		}
+
+		for _, q := range unbalancedQuotes(string(content)) {
+			problem := "ends with a quote it never opens"
+			if q.Opening {
+				problem = "opens a quote it never closes"
+			}
+			fixed := strings.Trim(q.Value, `"'`)
+
+			findings = append(findings, models.NewFinding(
+				"ENV030",
+				models.SeverityInfo,
+				fmt.Sprintf("%s has mismatched quotes", q.Key),
+			).WithDetails(fmt.Sprintf("The value of %s in %s (%s) %s; some loaders keep the stray quote in the value, others fail to parse the file", q.Key, envFile.Path, q.Value, problem)).
+				WithFile(envFile.Path, q.Line).
+				WithFix(fmt.Sprintf("Quote both ends or neither: %s=\"%s\"", q.Key, fixed)))
+		}
+	}
+
+	return findings
+}
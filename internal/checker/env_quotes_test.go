@@ -0,0 +1,40 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestCheckEnvQuotes(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/env-quotes")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var lines []int
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "ENV030" {
+			titles = append(titles, f.Title)
+			lines = append(lines, f.Files[0].Line)
+		}
+	}
+
+	// Matched quotes, quotes inside a value, apostrophes and the multiline
+	// PRIVATE_KEY are fine
+	expected := []string{
+		"OPEN_DOUBLE has mismatched quotes",
+		"CLOSE_DOUBLE has mismatched quotes",
+		"OPEN_SINGLE has mismatched quotes",
+	}
+	expectedLines := []int{7, 8, 9}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] || lines[i] != expectedLines[i] {
+			t.Errorf("expected %q at line %d, got %q at line %d", expected[i], expectedLines[i], titles[i], lines[i])
+		}
+	}
+}
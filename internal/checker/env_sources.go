@@ -0,0 +1,122 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// envSource is one place a service's variable is set. Label is empty for
+// the project .env, which only feeds interpolation.
+type envSource struct {
+	Label string
+	File  string
+	Line  int
+	Value string
+}
+
+// describe formats the source for a precedence chain
+func (s envSource) describe() string {
+	if s.Label == "" {
+		return fmt.Sprintf("%s:%d (%s, interpolation only)", s.File, s.Line, s.Value)
+	}
+	return fmt.Sprintf("%s %s:%d (%s)", s.Label, s.File, s.Line, s.Value)
+}
+
+// serviceEnvSources returns where each of a service's variables is set,
+// highest precedence first: environment, then env_file entries with later
+// files winning, then the project .env, which only feeds ${VAR}
+// interpolation. environment entries that interpolate or pass a value
+// through read from another source rather than setting one, and are left
+// out.
+func serviceEnvSources(fsys vfs.FS, doc *composeDoc, svc *composeService, dotEnv []envEntry) map[string][]envSource {
+	sources := make(map[string][]envSource)
+
+	for _, entry := range serviceEnvironment(svc) {
+		if entry.Value == "" || strings.Contains(entry.Value, "$") {
+			continue
+		}
+		sources[entry.Key] = append(sources[entry.Key], envSource{Label: "environment", File: doc.Path, Line: entry.Line, Value: entry.Value})
+	}
+
+	envFiles := serviceEnvFiles(doc, svc)
+	loadsDotEnv := false
+	for i := len(envFiles) - 1; i >= 0; i-- {
+		path := filepath.Clean(envFiles[i].Path)
+		loadsDotEnv = loadsDotEnv || path == ".env"
+
+		// Within one file the last definition wins
+		fileSources := make(map[string]envSource)
+		for _, entry := range parseEnvEntries(fsys, path) {
+			fileSources[entry.Key] = envSource{Label: "env_file", File: path, Line: entry.Line, Value: entry.Value}
+		}
+		for key, src := range fileSources {
+			sources[key] = append(sources[key], src)
+		}
+	}
+
+	if !loadsDotEnv {
+		last := make(map[string]envSource)
+		for _, entry := range dotEnv {
+			last[entry.Key] = envSource{File: ".env", Line: entry.Line, Value: entry.Value}
+		}
+		for key, src := range last {
+			sources[key] = append(sources[key], src)
+		}
+	}
+
+	return sources
+}
+
+// checkEnvSourcePrecedence explains, per service and key, which value wins
+// when a variable is set in more than one of environment, env_file and
+// .env, listing every source in precedence order. Keys already reported
+// by CMP023 or CMP030 for differing from .env are skipped.
+func checkEnvSourcePrecedence(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	dotEnv := parseEnvEntries(fsys, ".env")
+	envValues := parseEnvFile(fsys, ".env")
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+
+			sources := serviceEnvSources(fsys, doc, svc, dotEnv)
+			reported := dotEnvConflictKeys(fsys, doc, svc, envValues)
+			keys := make([]string, 0, len(sources))
+			for key, chain := range sources {
+				if len(chain) > 1 && !reported[key] {
+					keys = append(keys, key)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				chain := sources[key]
+
+				described := make([]string, len(chain))
+				for i, src := range chain {
+					described[i] = src.describe()
+				}
+				winner := chain[0]
+
+				findings = append(findings, models.NewFinding(
+					"ENV029",
+					models.SeverityInfo,
+					fmt.Sprintf("%s for service %s is set in %d places", key, svc.Name, len(chain)),
+				).WithDetails(fmt.Sprintf("Precedence, highest first: %s. %s sees %s=%s", strings.Join(described, " > "), svc.Name, key, winner.Value)).
+					WithFile(winner.File, winner.Line).
+					WithFix(fmt.Sprintf("Set %s in one place so its effective value is obvious", key)))
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,164 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// healthcheckArg is one argument of a healthcheck.test command, or the
+// whole command in string form
+type healthcheckArg struct {
+	Value string
+	Line  int
+}
+
+// healthcheckTest returns the arguments of a service's healthcheck.test and
+// whether the container's shell runs them: the string form and
+// ["CMD-SHELL", ...] go through a shell, ["CMD", ...] runs directly.
+// Disabled healthchecks and ["NONE"] have no command.
+func healthcheckTest(svc *composeService) ([]healthcheckArg, bool) {
+	if !hasHealthcheck(svc) {
+		return nil, false
+	}
+	node := resolveAlias(mappingValue(svc.Field("healthcheck"), "test"))
+	if node == nil {
+		return nil, false
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []healthcheckArg{{Value: node.Value, Line: node.Line}}, true
+	case yaml.SequenceNode:
+		var args []healthcheckArg
+		for _, item := range node.Content {
+			item = resolveAlias(item)
+			if item.Kind == yaml.ScalarNode {
+				args = append(args, healthcheckArg{Value: item.Value, Line: item.Line})
+			}
+		}
+		if len(args) == 0 {
+			return nil, false
+		}
+		switch args[0].Value {
+		case "NONE":
+			return nil, false
+		case "CMD-SHELL":
+			return args[1:], true
+		case "CMD":
+			return args[1:], false
+		}
+		return args, false
+	}
+
+	return nil, false
+}
+
+// escapedRefs returns the variables in s written as $$VAR or $${VAR}, which
+// compose passes through as $VAR for a shell to expand. Braced references
+// with a default or alternate, such as $${VAR:-x}, are left out.
+func escapedRefs(s string) []string {
+	var names []string
+
+	for i := 0; i+2 < len(s); i++ {
+		if s[i] != '$' {
+			continue
+		}
+		if s[i+1] != '$' {
+			// A single $ is compose interpolation; skip past a brace so
+			// a nested $$ isn't misread
+			if s[i+1] == '{' {
+				if end := matchingBrace(s, i+1); end > 0 {
+					i = end
+				}
+			}
+			continue
+		}
+
+		start := i + 2
+		braced := s[start] == '{'
+		if braced {
+			start++
+		}
+		end := start
+		for end < len(s) && isNameChar(s[end]) {
+			end++
+		}
+		if end > start && isNameStart(s[start]) {
+			rest := s[end:]
+			if !braced || strings.HasPrefix(rest, "}") || strings.HasPrefix(rest, "?") || strings.HasPrefix(rest, ":?") {
+				names = append(names, s[start:end])
+			}
+		}
+		i = end - 1
+	}
+
+	return names
+}
+
+// containerEnvVars returns the variables a service's container is given by
+// its environment block and env_file entries
+func containerEnvVars(fsys vfs.FS, doc *composeDoc, svc *composeService) map[string]bool {
+	vars := make(map[string]bool)
+	for _, entry := range serviceEnvironment(svc) {
+		vars[entry.Key] = true
+	}
+	for _, ref := range serviceEnvFiles(doc, svc) {
+		for _, entry := range parseEnvEntries(fsys, filepath.Clean(ref.Path)) {
+			vars[entry.Key] = true
+		}
+	}
+	return vars
+}
+
+// checkHealthcheckRefs flags $$VAR references in shell-form healthcheck
+// tests that the service's environment and env_file don't set. Compose
+// passes $$VAR through for the container's shell to expand, so an unset
+// variable leaves the check running with an empty argument, and the
+// service may never report healthy. ${VAR} and $VAR are substituted by
+// compose like the rest of the file and are left to ENV001.
+func checkHealthcheckRefs(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+			args, shell := healthcheckTest(svc)
+			if len(args) == 0 || !shell {
+				continue
+			}
+
+			var command []string
+			for _, arg := range args {
+				command = append(command, strings.ReplaceAll(arg.Value, "$$", "$"))
+			}
+			local := shellLocalVars(strings.Join(command, " "))
+			containerVars := containerEnvVars(fsys, doc, svc)
+
+			seen := make(map[string]bool)
+			for _, arg := range args {
+				for _, name := range escapedRefs(stripShellLiterals(arg.Value)) {
+					if seen[name] || containerVars[name] || local[name] || shellBuiltinVars[name] || isStandardVar(name) {
+						continue
+					}
+					seen[name] = true
+					findings = append(findings, models.NewFinding(
+						"CMP037",
+						models.SeverityWarning,
+						fmt.Sprintf("Service %s healthcheck uses $$%s, which its container doesn't set", svc.Name, name),
+					).WithDetails(fmt.Sprintf("$$%s is expanded by the shell inside the %s container, but neither its environment nor its env_file sets %s, so the healthcheck runs with an empty value unless the image defines it", name, svc.Name, name)).
+						WithFile(doc.Path, arg.Line).
+						WithFix(fmt.Sprintf("Add %s to the environment of %s, or write ${%s} to have compose substitute it from .env", name, svc.Name, name)))
+				}
+			}
+		}
+	}
+
+	return findings
+}
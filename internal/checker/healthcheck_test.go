@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestEscapedRefs(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"pg_isready -U $$POSTGRES_USER", []string{"POSTGRES_USER"}},
+		{"curl localhost:$${PORT}/health", []string{"PORT"}},
+		{"$${TIMEOUT:-5} $${REQUIRED:?}", []string{"REQUIRED"}},
+		{"${COMPOSE_VAR} $COMPOSE_BARE", nil},
+		{"${OUTER:-$$INNER} $$AFTER", []string{"AFTER"}},
+		{"price: 5$$", nil},
+	}
+
+	for _, tt := range tests {
+		if got := escapedRefs(tt.input); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("escapedRefs(%q) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckHealthcheckRefs(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/healthcheck-env")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP037" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// api reads API_PORT from its env_file and defaults HEALTH_TOKEN, worker
+	// sets QUEUE and q itself, and legacy's healthcheck is disabled
+	expected := []string{
+		"Service cache healthcheck uses $$REDIS_PASSWORD, which its container doesn't set",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+
+	// Each undefined reference gets exactly one finding: compose
+	// interpolation in a healthcheck is ENV001, as anywhere else
+	for name, code := range map[string]string{"DB_HOST": "ENV001", "WORKER_MODE": "ENV001", "REDIS_PASSWORD": "CMP037"} {
+		var codes []string
+		for _, f := range findings {
+			if strings.Contains(f.Title, name) {
+				codes = append(codes, f.Code)
+			}
+		}
+		if len(codes) != 1 || codes[0] != code {
+			t.Errorf("expected one %s finding for %s, got %v", code, name, codes)
+		}
+	}
+}
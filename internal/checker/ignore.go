@@ -0,0 +1,112 @@
+package checker
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// broadPatternProbes are typical source paths; an ignore pattern matching
+// all of them would leave source scanning nothing to scan
+var broadPatternProbes = []string{"main.go", "index.js", "src/app.ts", "app/main.py", "scripts/setup.sh"}
+
+// normalizeIgnorePattern trims a pattern and a leading "./", and reports
+// whether it only matches directories (a trailing "/")
+func normalizeIgnorePattern(pattern string) (string, bool) {
+	pattern = strings.TrimPrefix(strings.TrimSpace(pattern), "./")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimRight(pattern, "/")
+	return pattern, dirOnly
+}
+
+// matchSegments matches path segments against pattern segments, where a
+// "**" segment matches any number of directories, including none
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
+
+// matchesIgnorePattern reports whether a slash-separated path relative to
+// the project root matches one of patterns. Patterns without a slash, such
+// as "*.backup" or "deprecated/", match any path component; patterns with
+// one, such as "web/dist", match from the root, with "**" spanning any
+// number of directories as in "**/dist/**". A trailing slash matches
+// directories only, and "." or "/" match everything.
+func matchesIgnorePattern(name string, isDir bool, patterns []string) bool {
+	for _, raw := range patterns {
+		if strings.TrimSpace(raw) == "" {
+			continue
+		}
+		pattern, dirOnly := normalizeIgnorePattern(raw)
+		if pattern == "" || pattern == "." {
+			return true
+		}
+		if dirOnly && !isDir {
+			continue
+		}
+
+		if strings.Contains(pattern, "/") {
+			if matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/")) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, path.Base(name)); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isBroadIgnorePattern reports whether a pattern matches every typical
+// source file
+func isBroadIgnorePattern(pattern string) bool {
+	if strings.TrimSpace(pattern) == "" {
+		return false
+	}
+	for _, probe := range broadPatternProbes {
+		if !matchesIgnorePattern(probe, false, []string{pattern}) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkIgnorePatterns flags ignore_patterns entries so broad that source
+// scanning would skip every file
+func checkIgnorePatterns(cfg *config.Config) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, pattern := range cfg.IgnorePatterns {
+		if !isBroadIgnorePattern(pattern) {
+			continue
+		}
+		findings = append(findings, models.NewFinding(
+			"CFG001",
+			models.SeverityInfo,
+			fmt.Sprintf("ignore_patterns entry %q matches every file", pattern),
+		).WithDetails("Source scanning skips files matching ignore_patterns, so this pattern turns it off entirely and undefined variables in code go unreported").
+			WithFix(fmt.Sprintf("Narrow %q in .devcheck.yaml to the files or directories to skip, e.g. \"*.min.js\" or \"vendor/\"", pattern)))
+	}
+
+	return findings
+}
@@ -0,0 +1,76 @@
+package checker
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestMatchesIgnorePattern(t *testing.T) {
+	tests := []struct {
+		path     string
+		isDir    bool
+		patterns []string
+		expected bool
+	}{
+		{"old/config.js.backup", false, []string{"*.backup"}, true},
+		{"deprecated", true, []string{"deprecated/"}, true},
+		{"deprecated", false, []string{"deprecated/"}, false},
+		{"web/dist", true, []string{"./web/dist"}, true},
+		{"api/web/dist", true, []string{"web/dist"}, false},
+		{"src/app.min.js", false, []string{"**/*.min.js"}, true},
+		{"web/static/js/vendor/app.min.js", false, []string{"**/*.min.js"}, true},
+		{"app.min.js", false, []string{"**/*.min.js"}, true},
+		{"web/static/app.js", false, []string{"**/*.min.js"}, false},
+		{"dist", true, []string{"**/dist/**"}, true},
+		{"packages/web/dist", true, []string{"**/dist/**"}, true},
+		{"packages/web/dist/assets/main.js", false, []string{"**/dist/**"}, true},
+		{"packages/web/distribution/main.js", false, []string{"**/dist/**"}, false},
+		{"web/src/lib/util.js", false, []string{"web/**/util.js"}, true},
+		{"api/src/lib/util.js", false, []string{"web/**/util.js"}, false},
+		{"src/app.js", false, []string{"*.min.js", ""}, false},
+		{"src/app.js", false, []string{"."}, true},
+	}
+
+	for _, tt := range tests {
+		if got := matchesIgnorePattern(tt.path, tt.isDir, tt.patterns); got != tt.expected {
+			t.Errorf("matchesIgnorePattern(%q, %v, %q) = %v, want %v", tt.path, tt.isDir, tt.patterns, got, tt.expected)
+		}
+	}
+}
+
+func TestCheckIgnorePatterns(t *testing.T) {
+	fsys := fstest.MapFS{
+		".env":                   {Data: []byte("PORT=3000\n")},
+		"src/index.js":           {Data: []byte("const url = process.env.API_URL;\n")},
+		"src/legacy/old.js":      {Data: []byte("const key = process.env.LEGACY_KEY;\n")},
+		"scripts/seed.js.backup": {Data: []byte("process.env.SEED_TOKEN;\n")},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+
+	tests := []struct {
+		name      string
+		patterns  []string
+		undefined int
+		broad     int
+	}{
+		{"no patterns", nil, 2, 0},
+		{"skips a directory", []string{"legacy/", "*.backup"}, 1, 0},
+		{"broad patterns", []string{"*", "**", ".", "/", "*.*"}, 0, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.DefaultConfig()
+			cfg.IgnorePatterns = tt.patterns
+			findings := CheckFS(fsys, artifacts, Options{EnableSourceScanning: true, Config: cfg})
+			if got := countByCode(findings, "SRC001"); got != tt.undefined {
+				t.Errorf("expected %d SRC001 findings, got %d", tt.undefined, got)
+			}
+			if got := countByCode(findings, "CFG001"); got != tt.broad {
+				t.Errorf("expected %d CFG001 findings, got %d", tt.broad, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package checker
+
+import "strings"
+
+// interpolation is a single variable reference in compose syntax
+type interpolation struct {
+	Name string
+	// Operator is one of "", ":-", "-", ":?", "?", ":+", "+"
+	Operator string
+	// Arg is the default, error message or alternate value after the operator
+	Arg string
+}
+
+// interpolationOperators are checked longest first so ":-" wins over "-"
+var interpolationOperators = []string{":-", ":?", ":+", "-", "?", "+"}
+
+// parseInterpolations extracts variable references from s following the
+// compose rules: $$ is a literal dollar, ${VAR}, ${VAR<op>arg} with nested
+// braces in arg, and bare $VAR
+func parseInterpolations(s string) []interpolation {
+	var refs []interpolation
+
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '$':
+			i++ // escaped dollar
+		case next == '{':
+			end := matchingBrace(s, i+1)
+			if end < 0 {
+				return refs
+			}
+			if ref, ok := parseBraced(s[i+2 : end]); ok {
+				refs = append(refs, ref)
+			}
+			i = end
+		case isNameStart(next):
+			j := i + 1
+			for j < len(s) && isNameChar(s[j]) {
+				j++
+			}
+			refs = append(refs, interpolation{Name: s[i+1 : j]})
+			i = j - 1
+		}
+	}
+
+	return refs
+}
+
+// parseBraced parses the inside of ${...}
+func parseBraced(body string) (interpolation, bool) {
+	n := 0
+	for n < len(body) && isNameChar(body[n]) {
+		n++
+	}
+	if n == 0 || !isNameStart(body[0]) {
+		return interpolation{}, false
+	}
+
+	ref := interpolation{Name: body[:n]}
+	rest := body[n:]
+	if rest == "" {
+		return ref, true
+	}
+
+	for _, op := range interpolationOperators {
+		if strings.HasPrefix(rest, op) {
+			ref.Operator = op
+			ref.Arg = rest[len(op):]
+			return ref, true
+		}
+	}
+	return interpolation{}, false
+}
+
+// matchingBrace returns the index of the } closing the { at open, or -1
+func matchingBrace(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// undefinedRefs returns the variables in s that must be defined but aren't.
+// Defaults (:- and -) satisfy an undefined variable, though references in
+// the default itself are then required; :? and ? always require the
+// variable; alternates (:+ and +) only matter when the variable is set.
+func undefinedRefs(s string, defined func(string) bool) []string {
+	var missing []string
+
+	for _, ref := range parseInterpolations(s) {
+		isDefined := defined(ref.Name)
+		switch ref.Operator {
+		case ":-", "-":
+			if !isDefined {
+				missing = append(missing, undefinedRefs(ref.Arg, defined)...)
+			}
+		case ":+", "+":
+			if isDefined {
+				missing = append(missing, undefinedRefs(ref.Arg, defined)...)
+			}
+		default:
+			if !isDefined {
+				missing = append(missing, ref.Name)
+			}
+		}
+	}
+
+	return missing
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isNameChar(c byte) bool {
+	return isNameStart(c) || (c >= '0' && c <= '9')
+}
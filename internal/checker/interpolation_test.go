@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUndefinedRefs(t *testing.T) {
+	defined := map[string]bool{"SET": true}
+	isDefined := func(name string) bool { return defined[name] }
+
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"braced", "${VAR}", []string{"VAR"}},
+		{"braced defined", "${SET}", nil},
+		{"bare", "$VAR/path", []string{"VAR"}},
+		{"escaped dollar", "$$VAR and $${VAR}", nil},
+		{"colon default", "${VAR:-fallback}", nil},
+		{"default", "${VAR-fallback}", nil},
+		{"colon required", "${VAR:?VAR must be set}", []string{"VAR"}},
+		{"required", "${VAR?}", []string{"VAR"}},
+		{"required defined", "${SET:?missing}", nil},
+		{"colon alternate", "${VAR:+alt}", nil},
+		{"alternate", "${VAR+alt}", nil},
+		{"alternate uses ref when set", "${SET:+${OTHER}}", []string{"OTHER"}},
+		{"nested default", "${VAR:-${FALLBACK}}", []string{"FALLBACK"}},
+		{"nested default satisfied", "${VAR:-${SET}}", nil},
+		{"nested default with default", "${A:-${B:-x}}", nil},
+		{"multiple", "${A}:${SET}:$B", []string{"A", "B"}},
+		{"lowercase bare", "$lower", []string{"lower"}},
+		{"not a reference", "price: 5$", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := undefinedRefs(tt.input, isDefined)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("undefinedRefs(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseInterpolationOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		operator string
+		arg      string
+	}{
+		{"${VAR}", "", ""},
+		{"${VAR:-a}", ":-", "a"},
+		{"${VAR-a}", "-", "a"},
+		{"${VAR:?err msg}", ":?", "err msg"},
+		{"${VAR?}", "?", ""},
+		{"${VAR:+b}", ":+", "b"},
+		{"${VAR+b}", "+", "b"},
+		{"${VAR:-${X:-y}}", ":-", "${X:-y}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			refs := parseInterpolations(tt.input)
+			if len(refs) != 1 {
+				t.Fatalf("expected 1 reference, got %d", len(refs))
+			}
+			if refs[0].Name != "VAR" || refs[0].Operator != tt.operator || refs[0].Arg != tt.arg {
+				t.Errorf("got %+v, want operator %q arg %q", refs[0], tt.operator, tt.arg)
+			}
+		})
+	}
+}
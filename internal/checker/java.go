@@ -0,0 +1,99 @@
+package checker
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/tools"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// javaVersionPatterns find the target Java version in build files, in order
+// of preference. The first capture group is the version.
+var javaVersionPatterns = map[string][]*regexp.Regexp{
+	"pom.xml": {
+		regexp.MustCompile(`<maven\.compiler\.release>\s*([0-9._]+)\s*<`),
+		regexp.MustCompile(`<release>\s*([0-9._]+)\s*</release>`),
+		regexp.MustCompile(`<java\.version>\s*([0-9._]+)\s*<`),
+		regexp.MustCompile(`<maven\.compiler\.source>\s*([0-9._]+)\s*<`),
+		regexp.MustCompile(`<maven\.compiler\.target>\s*([0-9._]+)\s*<`),
+	},
+	"build.gradle": {
+		regexp.MustCompile(`JavaLanguageVersion\.of\(\s*(\d+)\s*\)`),
+		regexp.MustCompile(`sourceCompatibility\s*=\s*(?:JavaVersion\.VERSION_)?['"]?([0-9._]+)['"]?`),
+		regexp.MustCompile(`targetCompatibility\s*=\s*(?:JavaVersion\.VERSION_)?['"]?([0-9._]+)['"]?`),
+	},
+}
+
+func init() {
+	javaVersionPatterns["build.gradle.kts"] = javaVersionPatterns["build.gradle"]
+}
+
+// projectJavaVersion reads the Java version a project targets from its
+// Maven or Gradle build file, returning the version, file and line
+func projectJavaVersion(fsys vfs.FS, artifacts *models.Artifacts) (string, string, int) {
+	for _, m := range artifacts.Manifests {
+		patterns, ok := javaVersionPatterns[filepath.Base(m.Path)]
+		if !ok {
+			continue
+		}
+
+		content, err := fsys.ReadFile(m.Path)
+		if err != nil {
+			continue
+		}
+
+		lines := strings.Split(string(content), "\n")
+		for _, pattern := range patterns {
+			for i, line := range lines {
+				if match := pattern.FindStringSubmatch(line); match != nil {
+					// Gradle's JavaVersion.VERSION_1_8 uses underscores
+					return strings.ReplaceAll(match[1], "_", "."), m.Path, i + 1
+				}
+			}
+		}
+	}
+
+	return "", "", 0
+}
+
+// checkJavaVersion compares the project's Java version with the installed JDK
+func checkJavaVersion(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	// Java may be one language of several, not the primary one
+	if !slices.Contains(artifacts.DetectedLanguages, models.LangJava) {
+		return findings
+	}
+
+	required, file, line := projectJavaVersion(fsys, artifacts)
+	if required == "" {
+		return findings
+	}
+
+	// Shares the per-process detection cache with the TOOL checks
+	java := tools.DetectTools()["java"]
+	if !java.Available || java.Version == "" {
+		return findings
+	}
+
+	requiredMajor := tools.JavaMajorVersion(required)
+	installedMajor := tools.JavaMajorVersion(java.Version)
+	if installedMajor >= requiredMajor {
+		return findings
+	}
+
+	findings = append(findings, models.NewFinding(
+		"TOOL008",
+		models.SeverityWarning,
+		fmt.Sprintf("Java %d required by %s but Java %d is installed", requiredMajor, file, installedMajor),
+	).WithDetails(fmt.Sprintf("%s targets Java %s, but java -version reports %s; the build will fail to compile for that release", file, required, java.Version)).
+		WithFile(file, line).
+		WithFix(fmt.Sprintf("Install JDK %d or newer, or point JAVA_HOME at one", requiredMajor)))
+
+	return findings
+}
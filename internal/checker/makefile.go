@@ -0,0 +1,192 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// makeAssignmentRegex matches make variable assignments, including export
+// and override prefixes, target-specific variables and define blocks
+var makeAssignmentRegex = regexp.MustCompile(`^(?:[^\s:#=]+\s*:\s*)?(?:(?:export|override|private)\s+)*(?:define\s+([A-Za-z_][\w.-]*)|([A-Za-z_][\w.-]*)\s*(?:::?=|:::=|\?=|\+=|!=|=))`)
+
+// makeExportRegex matches "export NAME..." lines without an assignment
+var makeExportRegex = regexp.MustCompile(`^export(\s+[A-Za-z_][\w.-]*)*\s*$`)
+
+// makeConditionalRegex matches ifdef/ifndef guards, whose variable the
+// Makefile expects may be unset
+var makeConditionalRegex = regexp.MustCompile(`^(?:ifdef|ifndef)\s+([A-Za-z_]\w*)`)
+
+// makeTargetRegex matches a rule header, capturing the first target
+var makeTargetRegex = regexp.MustCompile(`^([^\s:#=][^\s:=]*)[^:=]*::?(?:[^=]|$)`)
+
+// makeRefRegex matches make variable references such as $(VAR), ${VAR} and
+// substitution references like $(SRCS:.c=.o). Function calls such as
+// $(shell ...) and automatic variables such as $@ and $(@D) don't match.
+var makeRefRegex = regexp.MustCompile(`\$[({]([A-Za-z_]\w*)(?::[^)}]*)?[)}]`)
+
+// makeBuiltinVars are variables make defines itself
+var makeBuiltinVars = map[string]bool{
+	"MAKE": true, "MAKEFLAGS": true, "MAKECMDGOALS": true, "MAKEFILE_LIST": true,
+	"MAKELEVEL": true, "MAKEFILES": true, "MFLAGS": true, "CURDIR": true,
+	"SHELL": true, ".SHELLFLAGS": true, "VPATH": true, "SUFFIXES": true,
+	"CC": true, "CXX": true, "CPP": true, "AR": true, "AS": true, "LD": true,
+	"RM": true, "CFLAGS": true, "CXXFLAGS": true, "CPPFLAGS": true,
+	"LDFLAGS": true, "LDLIBS": true, "ARFLAGS": true,
+}
+
+// makeEnvRef is a variable a Makefile recipe uses without defining it
+type makeEnvRef struct {
+	Name   string
+	Target string
+	Line   int
+	// Shell is true for $$VAR references, expanded by the recipe's shell
+	// rather than by make
+	Shell bool
+}
+
+// makeVars returns the variables a Makefile assigns, those it exports to
+// recipes, and whether a bare "export" exports all of them
+func makeVars(lines []string) (assigned, exported map[string]bool, exportAll bool) {
+	assigned = make(map[string]bool)
+	exported = make(map[string]bool)
+
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		line = strings.TrimSpace(line)
+
+		if match := makeConditionalRegex.FindStringSubmatch(line); match != nil {
+			assigned[match[1]] = true
+			continue
+		}
+		if makeExportRegex.MatchString(line) {
+			names := strings.Fields(line)[1:]
+			exportAll = exportAll || len(names) == 0
+			for _, name := range names {
+				exported[name] = true
+			}
+			continue
+		}
+		if match := makeAssignmentRegex.FindStringSubmatch(line); match != nil {
+			name := firstGroup(match)
+			assigned[name] = true
+			if strings.Contains(line, "export ") {
+				exported[name] = true
+			}
+		}
+	}
+
+	return assigned, exported, exportAll
+}
+
+// makefileEnvRefs returns the first recipe reference to each variable that
+// is neither a make variable, an automatic or built-in one, nor defined.
+// $(VAR) and ${VAR} are make references, which make fills from the
+// environment when the Makefile doesn't set them; $$VAR is passed to the
+// recipe's shell, which sees only exported make variables.
+func makefileEnvRefs(content string, defined func(string) bool) []makeEnvRef {
+	var refs []makeEnvRef
+
+	lines := strings.Split(content, "\n")
+	assigned, exported, exportAll := makeVars(lines)
+
+	isMakeDefined := func(name string) bool {
+		return assigned[name] || makeBuiltinVars[name] || defined(name)
+	}
+
+	// Shell variables recipes set themselves, such as loop variables
+	var recipes strings.Builder
+	for _, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			recipes.WriteString(strings.ReplaceAll(line, "$$", "$") + "\n")
+		}
+	}
+	shellLocal := shellLocalVars(recipes.String())
+	isShellDefined := func(name string) bool {
+		return shellLocal[name] || shellBuiltinVars[name] || exported[name] ||
+			(exportAll && assigned[name]) || defined(name)
+	}
+
+	seen := make(map[string]bool)
+	add := func(ref makeEnvRef) {
+		if !seen[ref.Name] {
+			seen[ref.Name] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	target := ""
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "\t") {
+			if match := makeTargetRegex.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+				target = match[1]
+			}
+			continue
+		}
+
+		recipe := strings.TrimSpace(line)
+		if target == "" || strings.HasPrefix(recipe, "#") {
+			continue
+		}
+
+		// $$ is a literal dollar to make, so split it off before matching
+		// make references
+		parts := strings.Split(recipe, "$$")
+		for _, part := range parts {
+			for _, match := range makeRefRegex.FindAllStringSubmatch(part, -1) {
+				if !isMakeDefined(match[1]) {
+					add(makeEnvRef{Name: match[1], Target: target, Line: i + 1})
+				}
+			}
+		}
+
+		shell := makeRefRegex.ReplaceAllString(strings.Join(parts, "\x00"), "")
+		shell = strings.ReplaceAll(strings.ReplaceAll(shell, "$", ""), "\x00", "$")
+		for _, name := range undefinedRefs(stripShellLiterals(shell), isShellDefined) {
+			add(makeEnvRef{Name: name, Target: target, Line: i + 1, Shell: true})
+		}
+	}
+
+	return refs
+}
+
+// checkMakefileEnvRefs flags Makefile recipes that use env vars nothing
+// defines, which expand to empty strings and let setup steps run with
+// missing values
+func checkMakefileEnvRefs(fsys vfs.FS, artifacts *models.Artifacts, definedVars map[string]bool) []*models.Finding {
+	var findings []*models.Finding
+
+	if artifacts.Makefile == nil || !artifacts.Makefile.Found {
+		return findings
+	}
+	path := artifacts.Makefile.Path
+	content, err := fsys.ReadFile(path)
+	if err != nil {
+		return findings
+	}
+
+	isDefined := func(name string) bool {
+		return definedVars[name] || isStandardVar(name)
+	}
+
+	for _, ref := range makefileEnvRefs(string(content), isDefined) {
+		usage := fmt.Sprintf("$(%s)", ref.Name)
+		if ref.Shell {
+			usage = fmt.Sprintf("$$%s", ref.Name)
+		}
+		findings = append(findings, models.NewFinding(
+			"MAKE001",
+			models.SeverityInfo,
+			fmt.Sprintf("Makefile target %s uses undefined %s", ref.Target, usage),
+		).WithDetails(fmt.Sprintf("%s isn't set in the Makefile or any env file, so %s expands to an empty string unless it's exported in the shell running make", ref.Name, usage)).
+			WithFile(path, ref.Line).
+			WithFix(fmt.Sprintf("Add %s=<value> to .env, or set a default in the Makefile with %s ?= <value>", ref.Name, ref.Name)))
+	}
+
+	return findings
+}
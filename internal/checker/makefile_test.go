@@ -0,0 +1,37 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestCheckMakefileEnvRefs(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/makefile")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "MAKE001" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// Make variables, ifdef guards, .env keys, automatic variables, loop
+	// variables, exported variables and single-quoted text are all defined
+	// or literal; each variable is reported once
+	expected := []string{
+		"Makefile target setup uses undefined $(API_TOKEN)",
+		"Makefile target deploy uses undefined $$KUBE_CONTEXT",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
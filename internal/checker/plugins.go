@@ -0,0 +1,189 @@
+package checker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// PluginProtocolVersion is the version of the JSON contract with external
+// check plugins, sent in every pluginInput
+const PluginProtocolVersion = 1
+
+// DefaultPluginTimeout bounds a plugin run when plugin_timeout isn't set
+const DefaultPluginTimeout = 30 * time.Second
+
+// maxPluginStderr caps how much of a failing plugin's stderr is reported
+const maxPluginStderr = 500
+
+// maxPluginOutput caps how much a plugin may print on stdout; a var so
+// tests can lower it
+var maxPluginOutput = 8 << 20
+
+// cappedBuffer keeps the first limit bytes written to it and discards the
+// rest, so a runaway plugin can't exhaust memory. The buffer isn't
+// embedded, so io.Copy can't bypass Write through its ReadFrom.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.buf.Len(); len(p) > room {
+		b.truncated = true
+		b.buf.Write(p[:max(room, 0)])
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) Bytes() []byte  { return b.buf.Bytes() }
+func (b *cappedBuffer) String() string { return b.buf.String() }
+
+// pluginInput is written to a plugin's stdin. Root is the absolute project
+// path; file paths in Artifacts are relative to it.
+type pluginInput struct {
+	ProtocolVersion int               `json:"protocol_version"`
+	Root            string            `json:"root"`
+	Artifacts       *models.Artifacts `json:"artifacts"`
+}
+
+// pluginOutput is read from a plugin's stdout
+type pluginOutput struct {
+	Findings []*models.Finding `json:"findings"`
+}
+
+// runPlugins runs each configured plugin in the project directory and
+// merges the findings they return. A plugin that can't start, fails, times out or
+// prints invalid output is reported as a PLUGIN001 warning and the scan
+// continues.
+func runPlugins(ctx context.Context, basePath string, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
+	var findings []*models.Finding
+
+	timeout := cfg.PluginTimeout
+	if timeout <= 0 {
+		timeout = DefaultPluginTimeout
+	}
+	root, err := filepath.Abs(basePath)
+	if err != nil {
+		root = basePath
+	}
+	input, err := json.Marshal(pluginInput{ProtocolVersion: PluginProtocolVersion, Root: root, Artifacts: artifacts})
+	if err != nil {
+		return findings
+	}
+
+	for i, path := range cfg.ResolvePlugins(basePath) {
+		if ctx.Err() != nil {
+			break
+		}
+		plugin := cfg.Plugins[i]
+		found, err := runPlugin(ctx, root, path, input, timeout)
+		if err != nil {
+			findings = append(findings, models.NewFinding(
+				"PLUGIN001",
+				models.SeverityWarning,
+				fmt.Sprintf("Plugin %s failed", plugin),
+			).WithDetails(fmt.Sprintf("%v. Its checks were skipped; the rest of the scan is unaffected", err)).
+				WithFix(fmt.Sprintf("Run %s by hand with the scan's artifacts on stdin, or remove it from plugins in .devcheck.yaml", plugin)))
+			continue
+		}
+		findings = append(findings, found...)
+	}
+
+	return findings
+}
+
+// skippedPlugins reports the configured plugins that weren't run because
+// scan wasn't given --allow-plugins
+func skippedPlugins(cfg *config.Config) []*models.Finding {
+	return []*models.Finding{models.NewFinding(
+		"PLUGIN002",
+		models.SeverityInfo,
+		fmt.Sprintf("%d plugin(s) in config were not run", len(cfg.Plugins)),
+	).WithDetails(fmt.Sprintf("Plugins run programs named by the project's config (%s), so devcheck only runs them when asked to", strings.Join(cfg.Plugins, ", "))).
+		WithFix("Review the plugins, then scan with --allow-plugins")}
+}
+
+// runPlugin runs one plugin with input on stdin and returns its validated
+// findings
+func runPlugin(ctx context.Context, dir, plugin string, input []byte, timeout time.Duration) ([]*models.Finding, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	stdout := &cappedBuffer{limit: maxPluginOutput}
+	stderr := &cappedBuffer{limit: maxPluginStderr}
+	cmd := exec.CommandContext(ctx, plugin)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	// Children of a killed plugin can hold its output open; don't wait on them
+	cmd.WaitDelay = time.Second
+
+	if err := cmd.Run(); err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s", timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			if stderr.truncated {
+				msg += "…"
+			}
+			return nil, fmt.Errorf("%v: %s", err, msg)
+		}
+		return nil, err
+	}
+	if stdout.truncated {
+		return nil, fmt.Errorf("printed more than %d bytes", maxPluginOutput)
+	}
+
+	var output pluginOutput
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		return nil, fmt.Errorf("invalid output, expected {\"findings\": [...]}: %v", err)
+	}
+	if output.Findings == nil {
+		return nil, errors.New("invalid output: missing findings")
+	}
+	for i, f := range output.Findings {
+		if err := validatePluginFinding(f); err != nil {
+			return nil, fmt.Errorf("invalid finding %d: %w", i+1, err)
+		}
+	}
+
+	return output.Findings, nil
+}
+
+// validatePluginFinding checks the fields every finding needs and that
+// its code isn't a built-in one
+func validatePluginFinding(f *models.Finding) error {
+	switch {
+	case f == nil:
+		return errors.New("null finding")
+	case f.Code == "":
+		return errors.New("missing code")
+	case f.Title == "":
+		return errors.New("missing title")
+	case models.SeverityLevel(f.Severity) == 0:
+		return fmt.Errorf("severity %q is not blocking, warning or info", f.Severity)
+	}
+	// Like custom rules, a plugin can't report under a built-in code
+	if _, ok := LookupCheck(f.Code); ok {
+		return fmt.Errorf("code %s is a built-in check", f.Code)
+	}
+	for _, loc := range f.Files {
+		if loc.File == "" {
+			return errors.New("file location without a file")
+		}
+	}
+	return nil
+}
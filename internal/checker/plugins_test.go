@@ -0,0 +1,153 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// writePlugin writes an executable shell script plugin to dir
+func writePlugin(t *testing.T, dir, name, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in this test are shell scripts")
+	}
+
+	basePath, _ := filepath.Abs("testdata/basic")
+	artifacts := detector.Detect(basePath, "", nil)
+	dir := t.TempDir()
+
+	cfg := config.DefaultConfig()
+	cfg.PluginTimeout = 2 * time.Second
+	cfg.Plugins = []string{
+		// Echoes a finding only when it was given the artifacts
+		writePlugin(t, dir, "good", `grep -q '"protocol_version":1' || exit 3
+echo '{"findings": [{"code": "LIC001", "severity": "warning", "title": "GPL dependency", "files": [{"file": "package.json", "line": 3}]}]}'
+`),
+		writePlugin(t, dir, "crash", "echo 'boom' >&2\nexit 1\n"),
+		writePlugin(t, dir, "bad-severity", `echo '{"findings": [{"code": "X001", "severity": "fatal", "title": "x"}]}'`+"\n"),
+		writePlugin(t, dir, "not-json", "echo 'all good'\n"),
+		writePlugin(t, dir, "builtin-code", `echo '{"findings": [{"code": "ENV001", "severity": "info", "title": "x"}]}'`+"\n"),
+		filepath.Join(dir, "missing"),
+	}
+
+	findings := CheckWithOptions(basePath, artifacts, Options{Config: cfg, AllowPlugins: true})
+
+	var lic *models.Finding
+	var failures []string
+	for _, f := range findings {
+		switch f.Code {
+		case "LIC001":
+			lic = f
+		case "PLUGIN001":
+			failures = append(failures, filepath.Base(strings.TrimSuffix(strings.TrimPrefix(f.Title, "Plugin "), " failed"))+": "+f.Details)
+		}
+	}
+
+	if lic == nil {
+		t.Fatal("expected LIC001 from the good plugin")
+	}
+	if lic.Category != models.CategoryCustom || len(lic.Files) != 1 || lic.Files[0].File != "package.json" {
+		t.Errorf("unexpected plugin finding: %+v", lic)
+	}
+
+	expected := []string{"crash: exit status 1: boom", "bad-severity: invalid finding 1", "not-json: invalid output", "builtin-code: invalid finding 1: code ENV001 is a built-in check", "missing: "}
+	if len(failures) != len(expected) {
+		t.Fatalf("expected %d PLUGIN001 findings, got %v", len(expected), failures)
+	}
+	for i := range expected {
+		if !strings.HasPrefix(failures[i], expected[i]) {
+			t.Errorf("expected failure starting %q, got %q", expected[i], failures[i])
+		}
+	}
+}
+
+func TestRunPluginsTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in this test are shell scripts")
+	}
+
+	plugin := writePlugin(t, t.TempDir(), "slow", "sleep 5\n")
+	cfg := config.DefaultConfig()
+	cfg.Plugins = []string{plugin}
+	cfg.PluginTimeout = 100 * time.Millisecond
+
+	start := time.Now()
+	findings := CheckFS(fstest.MapFS{}, models.NewArtifacts(), Options{Config: cfg, AllowPlugins: true})
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("plugin was not stopped at its timeout, scan took %s", elapsed)
+	}
+	if countByCode(findings, "PLUGIN001") != 1 {
+		t.Fatalf("expected one PLUGIN001, got %d", countByCode(findings, "PLUGIN001"))
+	}
+}
+
+func TestPluginsNeedOptIn(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in this test are shell scripts")
+	}
+
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	cfg := config.DefaultConfig()
+	cfg.Plugins = []string{writePlugin(t, dir, "touch", "touch '"+marker+"'\necho '{\"findings\": []}'\n")}
+
+	findings := CheckFS(fstest.MapFS{}, models.NewArtifacts(), Options{Config: cfg})
+	if _, err := os.Stat(marker); err == nil {
+		t.Fatal("expected the plugin not to run without AllowPlugins")
+	}
+	if countByCode(findings, "PLUGIN002") != 1 {
+		t.Errorf("expected one PLUGIN002, got %d", countByCode(findings, "PLUGIN002"))
+	}
+
+	findings = CheckFS(fstest.MapFS{}, models.NewArtifacts(), Options{Config: cfg, AllowPlugins: true})
+	if _, err := os.Stat(marker); err != nil {
+		t.Error("expected the plugin to run with AllowPlugins")
+	}
+	if n := countByCode(findings, "PLUGIN001") + countByCode(findings, "PLUGIN002"); n != 0 {
+		t.Errorf("expected no plugin findings, got %d", n)
+	}
+}
+
+func TestRunPluginsOutputCap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins in this test are shell scripts")
+	}
+
+	saved := maxPluginOutput
+	maxPluginOutput = 64
+	t.Cleanup(func() { maxPluginOutput = saved })
+
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Plugins = []string{
+		writePlugin(t, dir, "small", `echo '{"findings": []}'`+"\n"),
+		writePlugin(t, dir, "chatty", `printf '{"findings": [], "padding": "%0100d"}\n' 0`+"\n"),
+	}
+
+	var failures []string
+	for _, f := range CheckFS(fstest.MapFS{}, models.NewArtifacts(), Options{Config: cfg, AllowPlugins: true}) {
+		if f.Code == "PLUGIN001" {
+			failures = append(failures, f.Title+": "+f.Details)
+		}
+	}
+	if len(failures) != 1 || !strings.Contains(failures[0], "chatty failed: printed more than 64 bytes") {
+		t.Errorf("expected only the chatty plugin to fail on its output size, got %v", failures)
+	}
+}
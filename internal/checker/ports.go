@@ -0,0 +1,417 @@
+package checker
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// maxPrivilegedPort is the highest port that needs elevated privileges to bind
+const maxPrivilegedPort = 1023
+
+// portMapping is one published port from a service's ports list
+type portMapping struct {
+	HostIP string
+	// HostPort and HostPortEnd are 0 when compose picks the host port
+	HostPort    int
+	HostPortEnd int
+	Target      string
+	Protocol    string
+	Line        int
+}
+
+// servicePorts parses a service's ports in short ("8080:80") or long
+// (target/published) syntax. Interpolated entries can't be resolved
+// statically and are skipped.
+func servicePorts(svc *composeService) []portMapping {
+	var ports []portMapping
+
+	node := svc.Field("ports")
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return ports
+	}
+
+	for _, item := range node.Content {
+		item = resolveAlias(item)
+		switch item.Kind {
+		case yaml.ScalarNode:
+			if p, ok := parsePortSpec(item.Value); ok {
+				p.Line = item.Line
+				ports = append(ports, p)
+			}
+		case yaml.MappingNode:
+			var long struct {
+				Target    string `yaml:"target"`
+				Published string `yaml:"published"`
+				HostIP    string `yaml:"host_ip"`
+				Protocol  string `yaml:"protocol"`
+			}
+			if err := item.Decode(&long); err != nil || strings.Contains(long.Published, "$") {
+				continue
+			}
+			p := portMapping{HostIP: long.HostIP, Target: long.Target, Protocol: long.Protocol, Line: item.Line}
+			if long.Published != "" {
+				start, end, ok := parsePortRange(long.Published)
+				if !ok {
+					continue
+				}
+				p.HostPort, p.HostPortEnd = start, end
+			}
+			ports = append(ports, p)
+		}
+	}
+
+	return ports
+}
+
+// parsePortSpec parses short syntax: [[HOST_IP:]HOST_PORT:]CONTAINER_PORT[/PROTOCOL],
+// where ports may be ranges and an IPv6 host IP is bracketed
+func parsePortSpec(spec string) (portMapping, bool) {
+	var p portMapping
+	if spec == "" || strings.Contains(spec, "$") {
+		return p, false
+	}
+
+	if i := strings.LastIndex(spec, "/"); i >= 0 {
+		p.Protocol = spec[i+1:]
+		spec = spec[:i]
+	}
+
+	if strings.HasPrefix(spec, "[") {
+		end := strings.Index(spec, "]:")
+		if end < 0 {
+			return p, false
+		}
+		p.HostIP = spec[1:end]
+		spec = spec[end+2:]
+	}
+
+	parts := strings.Split(spec, ":")
+	switch len(parts) {
+	case 1:
+		p.Target = parts[0]
+	case 2:
+		p.Target = parts[1]
+		if parts[0] != "" {
+			start, end, ok := parsePortRange(parts[0])
+			if !ok {
+				return p, false
+			}
+			p.HostPort, p.HostPortEnd = start, end
+		}
+	case 3:
+		if p.HostIP != "" || net.ParseIP(parts[0]) == nil {
+			return p, false
+		}
+		p.HostIP = parts[0]
+		p.Target = parts[2]
+		if parts[1] != "" {
+			start, end, ok := parsePortRange(parts[1])
+			if !ok {
+				return p, false
+			}
+			p.HostPort, p.HostPortEnd = start, end
+		}
+	default:
+		return p, false
+	}
+
+	if _, _, ok := parsePortRange(p.Target); !ok {
+		return p, false
+	}
+	return p, true
+}
+
+// parsePortRange parses "80" or "8000-8010"
+func parsePortRange(s string) (int, int, bool) {
+	startStr, endStr, isRange := strings.Cut(s, "-")
+	start, err := strconv.Atoi(startStr)
+	if err != nil || start < 0 || start > 65535 {
+		return 0, 0, false
+	}
+	if !isRange {
+		return start, start, true
+	}
+	end, err := strconv.Atoi(endStr)
+	if err != nil || end < start || end > 65535 {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// checkPrivilegedPorts flags services publishing host ports below 1024,
+// which need root (or extra capabilities) to bind on most systems
+func checkPrivilegedPorts(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+
+			for _, p := range servicePorts(svc) {
+				if p.HostPort == 0 || p.HostPort > maxPrivilegedPort {
+					continue
+				}
+
+				findings = append(findings, models.NewFinding(
+					"CMP026",
+					models.SeverityInfo,
+					fmt.Sprintf("Service %s publishes privileged host port %d", svc.Name, p.HostPort),
+				).WithDetails(fmt.Sprintf("Binding host ports below %d needs elevated privileges and often fails for rootless Docker or Podman in local development", maxPrivilegedPort+1)).
+					WithFile(doc.Path, p.Line).
+					WithFix(fmt.Sprintf("Publish a higher host port for local development, e.g. \"%d:%s\"", p.HostPort+8000, p.Target)))
+			}
+		}
+	}
+
+	return findings
+}
+
+// publishedPort is a host port mapping along with where it was declared
+type publishedPort struct {
+	Service string
+	Path    string
+	portMapping
+}
+
+// overlaps reports whether two mappings claim the same host port: the
+// same protocol, overlapping host port ranges and host IPs that can clash
+func (p publishedPort) overlaps(o publishedPort) (int, bool) {
+	if portProtocol(p.Protocol) != portProtocol(o.Protocol) || !hostIPsClash(p.HostIP, o.HostIP) {
+		return 0, false
+	}
+	start := max(p.HostPort, o.HostPort)
+	if start > min(p.HostPortEnd, o.HostPortEnd) {
+		return 0, false
+	}
+	return start, true
+}
+
+// portProtocol returns the protocol of a mapping, tcp by default
+func portProtocol(protocol string) string {
+	if protocol == "" {
+		return "tcp"
+	}
+	return strings.ToLower(protocol)
+}
+
+// hostIPsClash reports whether bindings on two host IPs conflict; an empty
+// or wildcard address binds every interface and clashes with any IP
+func hostIPsClash(a, b string) bool {
+	wildcard := func(ip string) bool { return ip == "" || ip == "0.0.0.0" || ip == "::" }
+	return a == b || wildcard(a) || wildcard(b)
+}
+
+// mergedPublishedPorts returns the host ports each service publishes once
+// every compose file is merged. Compose concatenates ports across files, so
+// a mapping repeated by an override is kept once, from the first file.
+func mergedPublishedPorts(docs []*composeDoc) []publishedPort {
+	var ports []publishedPort
+	seen := make(map[string]bool)
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			for _, p := range servicePorts(svc) {
+				if p.HostPort == 0 {
+					continue
+				}
+				key := fmt.Sprintf("%s\x00%s\x00%d-%d/%s", svc.Name, p.HostIP, p.HostPort, p.HostPortEnd, portProtocol(p.Protocol))
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				ports = append(ports, publishedPort{Service: svc.Name, Path: doc.Path, portMapping: p})
+			}
+		}
+	}
+
+	return ports
+}
+
+// checkPortConflicts flags two services publishing the same host port in
+// the merged compose project, including when the mappings come from a base
+// file and its override, since only one of them can bind the port
+func checkPortConflicts(docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	ports := mergedPublishedPorts(docs)
+	reported := make(map[string]bool)
+	for i, a := range ports {
+		for _, b := range ports[i+1:] {
+			if a.Service == b.Service || (!filter.includes(a.Service) && !filter.includes(b.Service)) {
+				continue
+			}
+			port, ok := a.overlaps(b)
+			if !ok {
+				continue
+			}
+			key := a.Service + "\x00" + b.Service
+			if reported[key] {
+				continue
+			}
+			reported[key] = true
+
+			findings = append(findings, models.NewFinding(
+				"CMP002",
+				models.SeverityBlocking,
+				fmt.Sprintf("Services %s and %s both publish host port %d", a.Service, b.Service, port),
+			).WithDetails(fmt.Sprintf("%s publishes it in %s:%d and %s in %s:%d; after the compose files are merged, whichever starts second fails to bind the port", a.Service, a.Path, a.Line, b.Service, b.Path, b.Line)).
+				WithFile(a.Path, a.Line).
+				WithFile(b.Path, b.Line).
+				WithFix(fmt.Sprintf("Publish a different host port for %s, or remove one of the mappings", b.Service)))
+		}
+	}
+
+	return findings
+}
+
+// portEnvKeys are the variables apps commonly read their listen port from,
+// most likely first
+var portEnvKeys = []string{"PORT", "HTTP_PORT", "APP_PORT", "SERVER_PORT"}
+
+// portEnv is the listen port a service's container gets from its env
+type portEnv struct {
+	Key   string
+	Value int
+	File  string
+	Line  int
+}
+
+// resolveSimpleRef resolves a value that is a single ${VAR}, $VAR or
+// ${VAR:-default} reference against vars. Values without a reference are
+// returned as they are; anything more complex isn't resolved.
+func resolveSimpleRef(value string, vars map[string]string) (string, bool) {
+	if !strings.Contains(value, "$") {
+		return value, true
+	}
+
+	refs := parseInterpolations(value)
+	if len(refs) != 1 {
+		return "", false
+	}
+	ref := refs[0]
+	if value != "$"+ref.Name && value != "${"+ref.Name+ref.Operator+ref.Arg+"}" {
+		return "", false
+	}
+
+	v, ok := vars[ref.Name]
+	switch ref.Operator {
+	case "":
+		return v, ok
+	case ":-":
+		if v == "" {
+			return ref.Arg, !strings.Contains(ref.Arg, "$")
+		}
+		return v, true
+	case "-":
+		if !ok {
+			return ref.Arg, !strings.Contains(ref.Arg, "$")
+		}
+		return v, true
+	}
+	return "", false
+}
+
+// servicePortEnv returns the first PORT-like variable the service's
+// container sees, from environment (interpolated from .env) or else its
+// env_file entries, later files winning
+func servicePortEnv(fsys vfs.FS, doc *composeDoc, svc *composeService, dotEnv map[string]string) (portEnv, bool) {
+	environment := make(map[string]envEntry)
+	for _, entry := range serviceEnvironment(svc) {
+		environment[entry.Key] = entry
+	}
+
+	envFiles := serviceEnvFiles(doc, svc)
+	fileEntries := make(map[string]envEntry)
+	fileOf := make(map[string]string)
+	for _, ref := range envFiles {
+		path := filepath.Clean(ref.Path)
+		for _, entry := range parseEnvEntries(fsys, path) {
+			fileEntries[entry.Key] = entry
+			fileOf[entry.Key] = path
+		}
+	}
+
+	for _, key := range portEnvKeys {
+		var value, file string
+		var line int
+		if entry, ok := environment[key]; ok {
+			resolved, ok := resolveSimpleRef(entry.Value, dotEnv)
+			if !ok {
+				return portEnv{}, false
+			}
+			value, file, line = resolved, doc.Path, entry.Line
+		} else if entry, ok := fileEntries[key]; ok {
+			value, file, line = entry.Value, fileOf[key], entry.Line
+		} else {
+			continue
+		}
+
+		port, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || port <= 0 || port > 65535 {
+			return portEnv{}, false
+		}
+		return portEnv{Key: key, Value: port, File: file, Line: line}, true
+	}
+
+	return portEnv{}, false
+}
+
+// checkPortEnvMismatch flags services whose PORT-like env value matches
+// none of the container ports they publish, so requests to the published
+// port never reach the app. It's a heuristic: the app may ignore PORT.
+func checkPortEnvMismatch(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	dotEnv := parseEnvFile(fsys, ".env")
+
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !filter.includes(svc.Name) {
+				continue
+			}
+
+			ports := servicePorts(svc)
+			if len(ports) == 0 {
+				continue
+			}
+			env, ok := servicePortEnv(fsys, doc, svc, dotEnv)
+			if !ok {
+				continue
+			}
+
+			matched := false
+			var targets []string
+			for _, p := range ports {
+				start, end, ok := parsePortRange(p.Target)
+				if !ok {
+					continue
+				}
+				matched = matched || (env.Value >= start && env.Value <= end)
+				targets = append(targets, p.Target)
+			}
+			if matched || len(targets) == 0 {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"CMP036",
+				models.SeverityInfo,
+				fmt.Sprintf("Service %s has %s=%d but publishes container port %s", svc.Name, env.Key, env.Value, strings.Join(targets, ", ")),
+			).WithDetails(fmt.Sprintf("If %s listens on %s, traffic to the published port reaches nothing inside the container", svc.Name, env.Key)).
+				WithFile(env.File, env.Line).
+				WithFile(doc.Path, ports[0].Line).
+				WithFix(fmt.Sprintf("Publish container port %d (e.g. \"%d:%d\"), or set %s to the container port", env.Value, env.Value, env.Value, env.Key)))
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,133 @@
+package checker
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestParsePortSpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		hostIP   string
+		hostPort int
+		hostEnd  int
+		target   string
+		protocol string
+		ok       bool
+	}{
+		{spec: "3000", target: "3000", ok: true},
+		{spec: "8080:80", hostPort: 8080, hostEnd: 8080, target: "80", ok: true},
+		{spec: "127.0.0.1:443:443/tcp", hostIP: "127.0.0.1", hostPort: 443, hostEnd: 443, target: "443", protocol: "tcp", ok: true},
+		{spec: "[::1]:53:53/udp", hostIP: "::1", hostPort: 53, hostEnd: 53, target: "53", protocol: "udp", ok: true},
+		{spec: "9000-9002:9000-9002", hostPort: 9000, hostEnd: 9002, target: "9000-9002", ok: true},
+		{spec: "127.0.0.1::80", hostIP: "127.0.0.1", target: "80", ok: true},
+		{spec: "${PORT}:80"},
+		{spec: "web:80"},
+		{spec: "99999:80"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			p, ok := parsePortSpec(tt.spec)
+			if ok != tt.ok {
+				t.Fatalf("expected ok=%v, got %v", tt.ok, ok)
+			}
+			if !ok {
+				return
+			}
+			if p.HostIP != tt.hostIP || p.HostPort != tt.hostPort || p.HostPortEnd != tt.hostEnd || p.Target != tt.target || p.Protocol != tt.protocol {
+				t.Errorf("unexpected mapping: %+v", p)
+			}
+		})
+	}
+}
+
+func TestCheckPortConflicts(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/port-conflicts")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var conflicts []*models.Finding
+	for _, f := range findings {
+		if f.Code == "CMP002" {
+			conflicts = append(conflicts, f)
+		}
+	}
+
+	expected := []string{
+		"Services api and web both publish host port 8080",
+		"Services db and pgadmin both publish host port 5432",
+	}
+	if len(conflicts) != len(expected) {
+		t.Fatalf("expected %d CMP002 findings, got %d", len(expected), len(conflicts))
+	}
+	for i := range expected {
+		if conflicts[i].Title != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], conflicts[i].Title)
+		}
+	}
+
+	// The web mapping comes from the override file
+	files := conflicts[0].Files
+	if len(files) != 2 || files[0].File != "docker-compose.yml" || files[1].File != "docker-compose.override.yml" {
+		t.Errorf("expected locations in both compose files, got %v", files)
+	}
+}
+
+func TestCheckPrivilegedPorts(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/privileged-ports")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP026" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Service dns publishes privileged host port 53",
+		"Service proxy publishes privileged host port 80",
+		"Service proxy publishes privileged host port 443",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
+
+func TestCheckPortEnvMismatch(t *testing.T) {
+	basePath, _ := filepath.Abs("testdata/port-env")
+	artifacts := detector.Detect(basePath, "", nil)
+	findings := Check(basePath, artifacts)
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "CMP036" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	// api resolves its default to the published port, worker's PORT falls
+	// in the published range and db sets no PORT
+	expected := []string{
+		"Service admin has HTTP_PORT=9000 but publishes container port 80",
+		"Service web has PORT=8080 but publishes container port 3000",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
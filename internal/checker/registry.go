@@ -0,0 +1,139 @@
+package checker
+
+import (
+	"sort"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// CheckInfo describes a finding code devcheck can emit
+type CheckInfo struct {
+	Code               string          `json:"code"`
+	Severity           models.Severity `json:"severity"`
+	Description        string          `json:"description"`
+	RequiresSourceScan bool            `json:"requires_source_scanning"`
+	RequiresCheckTools bool            `json:"requires_check_tools"`
+	Custom             bool            `json:"custom,omitempty"`
+}
+
+// Registry lists every built-in check. Add an entry here whenever a check
+// starts emitting a new code; registry_test.go keeps the two in sync.
+var Registry = []CheckInfo{
+	{Code: "ENV001", Severity: models.SeverityBlocking, Description: "Variable referenced in compose but not defined in any env file"},
+	{Code: "ENV002", Severity: models.SeverityWarning, Description: "Variable in .env.example missing from .env"},
+	{Code: "ENV003", Severity: models.SeverityWarning, Description: ".env missing when .env.example exists"},
+	{Code: "ENV016", Severity: models.SeverityWarning, Description: "Env file starts with a UTF-8 byte order mark"},
+	{Code: "ENV017", Severity: models.SeverityInfo, Description: "Env value points to a project file that doesn't exist"},
+	{Code: "ENV018", Severity: models.SeverityWarning, Description: "Compose uses variables but no env file exists (replaces per-variable ENV001)"},
+	{Code: "ENV019", Severity: models.SeverityBlocking, Description: "Undefined variable closely matches a defined one (likely a casing or spelling mistake)"},
+	{Code: "ENV020", Severity: models.SeverityInfo, Description: "Env file does not end with a newline"},
+	{Code: "ENV021", Severity: models.SeverityWarning, Description: "Env or compose file is a symlink to a missing target"},
+	{Code: "ENV022", Severity: models.SeverityWarning, Description: "Env value is unquoted JSON that loaders may mis-parse"},
+	{Code: "ENV023", Severity: models.SeverityInfo, Description: "Several env files found; shows which one takes precedence"},
+	{Code: "ENV024", Severity: models.SeverityWarning, Description: "Env file overrides a system variable such as PATH or HOME"},
+	{Code: "ENV025", Severity: models.SeverityWarning, Description: "Env value references a variable not defined in the file or environment"},
+	{Code: "ENV026", Severity: models.SeverityInfo, Description: "Keys shared by .env and .env.example are in a different order (check_key_order)"},
+	{Code: "ENV027", Severity: models.SeverityWarning, Description: "Key in .env has an empty value (blocking for required_env_vars)"},
+	{Code: "ENV028", Severity: models.SeverityBlocking, Description: "Env files include each other in a loop via source or ."},
+	{Code: "ENV029", Severity: models.SeverityInfo, Description: "Service variable set in several of environment, env_file and .env; shows which value wins (differences from .env are CMP023/CMP030)"},
+	{Code: "ENV030", Severity: models.SeverityInfo, Description: "Env value opens or closes a quote without its pair"},
+	{Code: "CMP001", Severity: models.SeverityBlocking, Description: "depends_on or links references unknown service"},
+	{Code: "CMP002", Severity: models.SeverityBlocking, Description: "Two services publish the same host port in the merged compose files"},
+	{Code: "CMP021", Severity: models.SeverityInfo, Description: "Service defines both build and image"},
+	{Code: "CMP022", Severity: models.SeverityWarning, Description: "depends_on target is disabled by profiles or deploy.replicas: 0"},
+	{Code: "CMP023", Severity: models.SeverityInfo, Description: "Service environment overrides a different value from .env"},
+	{Code: "CMP024", Severity: models.SeverityBlocking, Description: "Circular extends chain between services"},
+	{Code: "BUILD001", Severity: models.SeverityBlocking, Description: "Dockerfile not found in build context"},
+	{Code: "BUILD002", Severity: models.SeverityBlocking, Description: "Build context directory not found"},
+	{Code: "CMP025", Severity: models.SeverityBlocking, Description: "Alias references an undefined YAML anchor"},
+	{Code: "CMP026", Severity: models.SeverityInfo, Description: "Service publishes a privileged host port (below 1024)"},
+	{Code: "CMP027", Severity: models.SeverityInfo, Description: "Profile-only service with no dependents or ports may be unused"},
+	{Code: "CMP028", Severity: models.SeverityInfo, Description: "Service pulls its image from a registry other than Docker Hub (may need docker login)"},
+	{Code: "CMP029", Severity: models.SeverityWarning, Description: "build.args interpolates a variable no env file defines"},
+	{Code: "CMP030", Severity: models.SeverityInfo, Description: "Service env_file sets a different value than .env"},
+	{Code: "CMP031", Severity: models.SeverityInfo, Description: "Database or search service runs without memory or CPU limits"},
+	{Code: "CMP032", Severity: models.SeverityInfo, Description: "depends_on a database that has no healthcheck or service_healthy condition"},
+	{Code: "CMP033", Severity: models.SeverityInfo, Description: "Service uses legacy links instead of network service discovery"},
+	{Code: "CMP034", Severity: models.SeverityBlocking, Description: "Several services set the same container_name"},
+	{Code: "CMP035", Severity: models.SeverityWarning, Description: "image, ports or a volume source interpolates an undefined variable without a default"},
+	{Code: "CMP036", Severity: models.SeverityInfo, Description: "Service's PORT env value matches none of its published container ports"},
+	{Code: "CMP037", Severity: models.SeverityWarning, Description: "Shell-form healthcheck.test uses $$VAR that the container's environment doesn't set"},
+	{Code: "SEC004", Severity: models.SeverityWarning, Description: "Build context contains .env not excluded by .dockerignore"},
+	{Code: "SEC006", Severity: models.SeverityInfo, Description: "Env file with secrets is readable by group or other users"},
+	{Code: "SEC007", Severity: models.SeverityWarning, Description: "Value in .env.example looks like a real secret"},
+	{Code: "DEVC001", Severity: models.SeverityWarning, Description: "devcontainer.json references a missing file or undefined variable"},
+	{Code: "DKR003", Severity: models.SeverityInfo, Description: "Dockerfile builds from a latest or untagged base image"},
+	{Code: "LANG001", Severity: models.SeverityInfo, Description: "Primary language and package manager detected"},
+	{Code: "LANG003", Severity: models.SeverityInfo, Description: "Multiple languages detected (polyglot repository)"},
+	{Code: "HINT001", Severity: models.SeverityInfo, Description: "Likely run command found in README"},
+	{Code: "HINT004", Severity: models.SeverityInfo, Description: "Process type declared in Procfile"},
+	{Code: "HINT005", Severity: models.SeverityInfo, Description: "Recipes declared in a Justfile or Taskfile"},
+	{Code: "MAKE001", Severity: models.SeverityInfo, Description: "Makefile recipe uses a variable nothing defines"},
+	{Code: "SRC001", Severity: models.SeverityWarning, Description: "Env var used in source code but not defined", RequiresSourceScan: true},
+	{Code: "SRC002", Severity: models.SeverityInfo, Description: "Source files over --max-file-size were not scanned", RequiresSourceScan: true},
+	{Code: "TOOL001", Severity: models.SeverityBlocking, Description: "Tool from tool_versions not installed", RequiresCheckTools: true},
+	{Code: "TOOL002", Severity: models.SeverityWarning, Description: "Installed tool older than tool_versions minimum", RequiresCheckTools: true},
+	{Code: "TOOL008", Severity: models.SeverityWarning, Description: "Installed JDK older than the Maven/Gradle Java target", RequiresCheckTools: true},
+	{Code: "TOOL009", Severity: models.SeverityWarning, Description: "Tool needed by the detected stack is not installed", RequiresCheckTools: true},
+	{Code: "DET001", Severity: models.SeverityWarning, Description: "No compose, env or manifest files found; the path may be wrong"},
+	{Code: "SCAN001", Severity: models.SeverityWarning, Description: "Scan timed out or was cancelled; results are partial"},
+	{Code: "SCAN002", Severity: models.SeverityWarning, Description: "Service selected with --services is not defined"},
+	{Code: "PLUGIN001", Severity: models.SeverityWarning, Description: "External check plugin failed, timed out or returned invalid output"},
+	{Code: "PLUGIN002", Severity: models.SeverityInfo, Description: "Config lists plugins but the scan was not run with --allow-plugins"},
+	{Code: "CFG001", Severity: models.SeverityInfo, Description: "ignore_patterns entry matches every file, turning source scanning off"},
+	{Code: "REQ001", Severity: models.SeverityBlocking, Description: "Variable from required_env_vars not defined"},
+}
+
+// LookupCheck returns the registry entry for a finding code
+func LookupCheck(code string) (CheckInfo, bool) {
+	for _, info := range Registry {
+		if info.Code == code {
+			return info, true
+		}
+	}
+	return CheckInfo{}, false
+}
+
+// CustomChecks lists the codes a config adds: code_metadata entries and the
+// codes custom_rules emit, sorted by code. Codes that collide with built-in
+// checks are left out.
+func CustomChecks(cfg *config.Config) []CheckInfo {
+	checks := make(map[string]CheckInfo)
+	for code, meta := range cfg.CodeMetadata {
+		checks[code] = CheckInfo{Code: code, Severity: customSeverity(meta.Severity), Description: meta.Description, Custom: true}
+	}
+	for _, rule := range cfg.CustomRules {
+		code := rule.FindingCode()
+		info, ok := checks[code]
+		if !ok {
+			info = CheckInfo{Code: code, Severity: customSeverity(rule.Severity), Description: rule.Description, Custom: true}
+		}
+		if info.Description == "" {
+			info.Description = rule.Description
+		}
+		checks[code] = info
+	}
+
+	var infos []CheckInfo
+	for code, info := range checks {
+		if _, builtin := LookupCheck(code); builtin {
+			continue
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Code < infos[j].Code })
+	return infos
+}
+
+// customSeverity converts a config severity, defaulting to warning
+func customSeverity(s string) models.Severity {
+	switch s {
+	case "blocking":
+		return models.SeverityBlocking
+	case "info":
+		return models.SeverityInfo
+	default:
+		return models.SeverityWarning
+	}
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// codeLiteral matches finding code string literals such as "ENV001"
+var codeLiteral = regexp.MustCompile(`"([A-Z]+[0-9]{3})"`)
+
+// emittedSeverity matches a NewFinding call with a literal code and severity
+var emittedSeverity = regexp.MustCompile(`NewFinding\(\s*"([A-Z]+[0-9]{3})",\s*models\.Severity(\w+),`)
+
+func TestRegistryMatchesEmittedCodes(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatalf("failed to list sources: %v", err)
+	}
+
+	emitted := make(map[string]bool)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") || file == "registry.go" {
+			continue
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+		for _, match := range codeLiteral.FindAllStringSubmatch(string(content), -1) {
+			emitted[match[1]] = true
+		}
+		for _, match := range emittedSeverity.FindAllStringSubmatch(string(content), -1) {
+			info, ok := LookupCheck(match[1])
+			if ok && !strings.EqualFold(string(info.Severity), match[2]) {
+				t.Errorf("%s emits %s as %s but Registry says %s", file, match[1], strings.ToLower(match[2]), info.Severity)
+			}
+		}
+	}
+
+	for code := range emitted {
+		if _, ok := LookupCheck(code); !ok {
+			t.Errorf("code %s is emitted but missing from Registry", code)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, info := range Registry {
+		if seen[info.Code] {
+			t.Errorf("code %s is registered twice", info.Code)
+		}
+		seen[info.Code] = true
+		if !emitted[info.Code] {
+			t.Errorf("code %s is registered but never emitted", info.Code)
+		}
+		if info.Description == "" {
+			t.Errorf("code %s has no description", info.Code)
+		}
+		if models.CategoryForCode(info.Code) == models.CategoryCustom {
+			t.Errorf("code %s has no category", info.Code)
+		}
+	}
+}
+
+func TestCustomChecks(t *testing.T) {
+	cfg := &config.Config{
+		CustomRules: []config.CustomRule{
+			{ID: "DB", Pattern: "^DATABASE_", Required: true, Code: "ORG001"},
+			{ID: "CACHE", Pattern: "^REDIS_", Required: true, Description: "Cache settings", Severity: "info"},
+		},
+		CodeMetadata: map[string]config.CodeMetadata{
+			"ORG001": {Description: "Database settings must be defined", Severity: "blocking"},
+			"ENV001": {Description: "Collides with a built-in"},
+		},
+	}
+
+	checks := CustomChecks(cfg)
+	expected := []CheckInfo{
+		{Code: "CUSTOM-CACHE", Severity: models.SeverityInfo, Description: "Cache settings", Custom: true},
+		{Code: "ORG001", Severity: models.SeverityBlocking, Description: "Database settings must be defined", Custom: true},
+	}
+	if len(checks) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, checks)
+	}
+	for i := range expected {
+		if checks[i] != expected[i] {
+			t.Errorf("expected %+v, got %+v", expected[i], checks[i])
+		}
+	}
+
+	// Rules take metadata defaults for what they leave out
+	findings := checkCustomRules(map[string]bool{}, cfg)
+	if len(findings) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(findings))
+	}
+	if f := findings[0]; f.Code != "ORG001" || f.Severity != models.SeverityBlocking || f.Details != "Database settings must be defined" {
+		t.Errorf("expected ORG001 with metadata defaults, got %s %s %q", f.Code, f.Severity, f.Details)
+	}
+}
@@ -0,0 +1,241 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// secretKeyRegex matches env var names that usually hold credentials
+var secretKeyRegex = regexp.MustCompile(`(?i)(SECRET|PASSWORD|PASSWD|TOKEN|API_?KEY|PRIVATE_KEY|ACCESS_KEY|CREDENTIAL)`)
+
+// hasSecrets reports whether env entries appear to hold credentials: a
+// secret-looking key with a value
+func hasSecrets(entries []envEntry) bool {
+	for _, entry := range entries {
+		if entry.Value != "" && secretKeyRegex.MatchString(entry.Key) {
+			return true
+		}
+	}
+	return false
+}
+
+// secretValuePatterns match credential formats with a recognizable shape
+var secretValuePatterns = []struct {
+	Name  string
+	Regex *regexp.Regexp
+}{
+	{"AWS access key", regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36}|github_pat_[A-Za-z0-9_]{22,})`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20}`)},
+	{"Stripe live key", regexp.MustCompile(`\b[sr]k_live_[A-Za-z0-9]{16,}`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abposr]-[A-Za-z0-9-]{10,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"OpenAI API key", regexp.MustCompile(`\bsk-(?:proj-)?[A-Za-z0-9_-]{32,}`)},
+	{"private key", regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+}
+
+// placeholderRegex matches values that are obviously placeholders
+var placeholderRegex = regexp.MustCompile(`(?i)change.?me|your|example|placeholder|replace|dummy|fake|xxx|<.*>|\$\{`)
+
+// shannonEntropy returns the entropy of s in bits per character
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// secretValueKind describes why an env value looks like a real secret, or
+// returns "". Known credential formats always match; other values must be
+// long, random-looking and stored under a secret-looking key.
+func secretValueKind(key, value string) string {
+	if value == "" || placeholderRegex.MatchString(value) {
+		return ""
+	}
+	for _, p := range secretValuePatterns {
+		if p.Regex.MatchString(value) {
+			return p.Name
+		}
+	}
+	if secretKeyRegex.MatchString(key) && len(value) >= 20 && !strings.ContainsAny(value, " /:") && shannonEntropy(value) >= 4 {
+		return "high-entropy value"
+	}
+	return ""
+}
+
+// checkExampleSecrets flags .env.example values that look like real
+// credentials. Example files are committed, so they should only hold
+// placeholders.
+func checkExampleSecrets(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	for _, example := range artifacts.EnvExamples {
+		if !example.Found {
+			continue
+		}
+
+		for _, entry := range parseEnvEntries(fsys, example.Path) {
+			kind := secretValueKind(entry.Key, entry.Value)
+			if kind == "" {
+				continue
+			}
+
+			findings = append(findings, models.NewFinding(
+				"SEC007",
+				models.SeverityWarning,
+				fmt.Sprintf("%s in %s looks like a real secret", entry.Key, example.Path),
+			).WithDetails(fmt.Sprintf("The value of %s matches a %s; %s is meant to be committed, so it should only contain placeholders", entry.Key, kind, example.Path)).
+				WithFile(example.Path, entry.Line).
+				WithFix(fmt.Sprintf("Replace the value with a placeholder such as %s=changeme, and rotate the secret if it was real", entry.Key)))
+		}
+	}
+
+	return findings
+}
+
+// checkEnvFilePermissions notes env files holding secrets that other users
+// on the machine can read. Unix permissions don't apply on Windows.
+func checkEnvFilePermissions(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	if runtime.GOOS == "windows" {
+		return findings
+	}
+
+	for _, envFile := range artifacts.EnvFiles {
+		if !envFile.Found {
+			continue
+		}
+
+		info, err := fsys.Stat(envFile.Path)
+		if err != nil {
+			continue
+		}
+		perm := info.Mode().Perm()
+		if perm&0o044 == 0 || !hasSecrets(parseEnvEntries(fsys, envFile.Path)) {
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"SEC006",
+			models.SeverityInfo,
+			fmt.Sprintf("%s is readable by other users (mode %04o)", envFile.Path, perm),
+		).WithDetails(fmt.Sprintf("%s appears to contain secrets, and mode %04o lets the group or other users on this machine read them", envFile.Path, perm)).
+			WithFile(envFile.Path, 0).
+			WithFix(fmt.Sprintf("chmod 600 %s", envFile.Path)))
+	}
+
+	return findings
+}
+
+// buildContext returns the build context of a service, resolved against
+// the directory of its compose file, or "" if it doesn't build or the
+// context is remote
+func buildContext(doc *composeDoc, svc *composeService) string {
+	node := svc.Field("build")
+	if node == nil {
+		return ""
+	}
+
+	context := node.Value
+	if c := mappingValue(node, "context"); c != nil {
+		context = c.Value
+	} else if node.Kind != 0 && node.Kind != yaml.ScalarNode {
+		context = "."
+	}
+
+	if context == "" || strings.Contains(context, "://") || strings.HasPrefix(context, "git@") {
+		return ""
+	}
+	if filepath.IsAbs(context) {
+		return filepath.Clean(context)
+	}
+	return filepath.Join(filepath.Dir(doc.Path), context)
+}
+
+// dockerignoreExcludes reports whether .dockerignore content excludes
+// name. As with docker, the last matching pattern wins and ! re-includes.
+func dockerignoreExcludes(content, name string) bool {
+	excluded := false
+	for _, line := range strings.Split(content, "\n") {
+		pattern := strings.TrimSpace(line)
+		if pattern == "" || strings.HasPrefix(pattern, "#") {
+			continue
+		}
+
+		negate := strings.HasPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(pattern, "!")
+		pattern = strings.TrimPrefix(path.Clean(strings.TrimPrefix(pattern, "/")), "**/")
+
+		if ok, _ := path.Match(pattern, name); ok {
+			excluded = !negate
+		}
+	}
+	return excluded
+}
+
+// checkDockerignoreEnv flags build contexts that contain a .env file not
+// excluded by .dockerignore; a COPY . . would bake its secrets into the image
+func checkDockerignoreEnv(fsys vfs.FS, docs []*composeDoc, filter serviceFilter) []*models.Finding {
+	var findings []*models.Finding
+
+	checked := make(map[string]bool)
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			context := buildContext(doc, svc)
+			if context == "" || checked[context] || !filter.includes(svc.Name) {
+				continue
+			}
+			checked[context] = true
+
+			envPath := filepath.Join(context, ".env")
+			if _, err := fsys.Stat(envPath); err != nil {
+				continue
+			}
+
+			ignorePath := filepath.Join(context, ".dockerignore")
+			content, err := fsys.ReadFile(ignorePath)
+			if err == nil && dockerignoreExcludes(string(content), ".env") {
+				continue
+			}
+
+			details := fmt.Sprintf("Build context %s for service %s contains %s", context, svc.Name, envPath)
+			if err != nil {
+				details += " and has no .dockerignore"
+			} else {
+				details += fmt.Sprintf(" that %s does not exclude", ignorePath)
+			}
+
+			findings = append(findings, models.NewFinding(
+				"SEC004",
+				models.SeverityWarning,
+				fmt.Sprintf("%s may be copied into the %s image", envPath, svc.Name),
+			).WithDetails(details+"; secrets in it can end up in the built image").
+				WithFile(doc.Path, svc.Field("build").Line).
+				WithFix(fmt.Sprintf("Add .env to %s", ignorePath)).
+				WithFixCommand(&models.FixCommand{
+					Action:  models.FixAppendLine,
+					Path:    ignorePath,
+					Content: ".env",
+				}))
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,32 @@
+package checker
+
+import (
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// serviceEnv is a service-local env file, kept next to a service's build
+// context in monorepos, and the names it defines
+type serviceEnv struct {
+	Path string
+	Vars map[string]bool
+}
+
+// serviceLocalEnvFiles returns the service-local env files the detector
+// found, such as services/api/.env, keyed by service name
+func serviceLocalEnvFiles(fsys vfs.FS, artifacts *models.Artifacts) map[string]serviceEnv {
+	local := make(map[string]serviceEnv)
+	for _, e := range artifacts.EnvFiles {
+		if !e.Found || len(e.Services) == 0 {
+			continue
+		}
+		vars := make(map[string]bool)
+		for _, entry := range parseEnvEntries(fsys, e.Path) {
+			vars[entry.Key] = true
+		}
+		for _, name := range e.Services {
+			local[name] = serviceEnv{Path: e.Path, Vars: vars}
+		}
+	}
+	return local
+}
@@ -0,0 +1,97 @@
+package checker
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// serviceFilter scopes compose checks to a set of services. A nil filter
+// includes every service. Checks still look up excluded services when
+// resolving references, they just don't report problems in them.
+type serviceFilter map[string]bool
+
+// newServiceFilter returns a filter for the named services, or nil for all
+func newServiceFilter(names []string) serviceFilter {
+	if len(names) == 0 {
+		return nil
+	}
+	filter := make(serviceFilter)
+	for _, name := range names {
+		filter[name] = true
+	}
+	return filter
+}
+
+// includes reports whether findings for the service should be reported
+func (f serviceFilter) includes(name string) bool {
+	return f == nil || f[name]
+}
+
+// serviceAtLine returns the service whose definition spans line in doc, or
+// "" for lines outside any service (top-level keys, x- extension fields)
+func serviceAtLine(doc *composeDoc, line int) string {
+	for _, svc := range doc.Services {
+		if line >= svc.Line && line <= serviceEndLine(doc, svc) {
+			return svc.Name
+		}
+	}
+	return ""
+}
+
+// serviceEndLine returns the last line of a service's definition: the line
+// before the next service or top-level key, or the end of the file. Node
+// lines can't be used, since a multi-line block scalar such as "command: |"
+// only records the line it starts on.
+func serviceEndLine(doc *composeDoc, svc *composeService) int {
+	end := math.MaxInt
+	for _, other := range doc.Services {
+		if other.Line > svc.Line && other.Line-1 < end {
+			end = other.Line - 1
+		}
+	}
+	if doc.Root != nil && len(doc.Root.Content) > 0 {
+		top := doc.Root.Content[0]
+		if top.Kind == yaml.MappingNode {
+			for i := 0; i < len(top.Content); i += 2 {
+				if key := top.Content[i]; key.Line > svc.Line && key.Line-1 < end {
+					end = key.Line - 1
+				}
+			}
+		}
+	}
+	return end
+}
+
+// checkUnknownServices flags --services names no compose file defines
+func checkUnknownServices(docs []*composeDoc, names []string) []*models.Finding {
+	var findings []*models.Finding
+
+	defined := make(map[string]bool)
+	var all []string
+	for _, doc := range docs {
+		for _, svc := range doc.Services {
+			if !defined[svc.Name] {
+				defined[svc.Name] = true
+				all = append(all, svc.Name)
+			}
+		}
+	}
+
+	for _, name := range names {
+		if defined[name] {
+			continue
+		}
+		findings = append(findings, models.NewFinding(
+			"SCAN002",
+			models.SeverityWarning,
+			fmt.Sprintf("Service %s selected with --services is not defined", name),
+		).WithDetails(fmt.Sprintf("No compose file defines %s, so nothing was checked for it. Defined services: %s", name, strings.Join(all, ", "))).
+			WithFix(fmt.Sprintf("Check the spelling of %s in --services", name)))
+	}
+
+	return findings
+}
@@ -0,0 +1,119 @@
+package checker
+
+import (
+	"bufio"
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// shellExtensions are the shell script extensions scanned for env var usage
+var shellExtensions = map[string]bool{
+	".sh":   true,
+	".bash": true,
+}
+
+// shellBuiltinVars are variables the shell sets itself
+var shellBuiltinVars = map[string]bool{
+	"BASH": true, "BASH_SOURCE": true, "BASH_VERSION": true, "BASH_REMATCH": true,
+	"FUNCNAME": true, "LINENO": true, "PIPESTATUS": true, "RANDOM": true,
+	"SECONDS": true, "OLDPWD": true, "IFS": true, "OPTARG": true, "OPTIND": true,
+	"PPID": true, "EUID": true, "HOSTTYPE": true, "OSTYPE": true, "REPLY": true,
+	"SHLVL": true, "TMPDIR": true, "LANG": true, "LOGNAME": true,
+}
+
+// shellAssignmentRegex matches variables a script sets itself: plain and
+// exported assignments, local/readonly/declare, for loops, read and getopts
+var shellAssignmentRegex = regexp.MustCompile(strings.Join([]string{
+	`(?m)(?:^\s*|[;&|(]\s*|\bexport\s+)([A-Za-z_]\w*)\+?=`,
+	`\b(?:local|readonly|declare|typeset)(?:\s+-\w+)*\s+([A-Za-z_]\w*)`,
+	`\bfor\s+([A-Za-z_]\w*)\s+in\b`,
+	`\bread\s+(?:-\w+\s+)*([A-Za-z_][\w ]*)`,
+	`\bgetopts\s+\S+\s+([A-Za-z_]\w*)`,
+}, "|"))
+
+// shellShebangRegex matches interpreter lines for sh-compatible shells
+var shellShebangRegex = regexp.MustCompile(`^#!\s*\S*/(?:env\s+)?(?:ba|da|k|z)?sh\b`)
+
+// hasShellShebang reports whether a file starts with a shell interpreter
+// line, reading only its first line
+func hasShellShebang(fsys vfs.FS, name string) bool {
+	file, err := fsys.Open(name)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	buf := make([]byte, 128)
+	n, _ := file.Read(buf)
+	first, _, _ := strings.Cut(string(buf[:n]), "\n")
+	return shellShebangRegex.MatchString(first)
+}
+
+// shellLocalVars returns the variables a script assigns, which are not
+// expected to come from the environment
+func shellLocalVars(content string) map[string]bool {
+	local := make(map[string]bool)
+	for _, match := range shellAssignmentRegex.FindAllStringSubmatch(content, -1) {
+		for _, name := range strings.Fields(firstGroup(match)) {
+			local[name] = true
+		}
+	}
+	return local
+}
+
+// stripShellLiterals removes single-quoted strings and escaped dollars,
+// where $ does not expand, keeping double-quoted text
+func stripShellLiterals(line string) string {
+	var b strings.Builder
+	inDouble := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			continue
+		case c == '"':
+			inDouble = !inDouble
+		case c == '\'' && !inDouble:
+			if end := strings.IndexByte(line[i+1:], '\''); end >= 0 {
+				i += end + 1
+				continue
+			}
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// shellEnvRefs returns the first reference to each variable in a shell
+// script that is neither set by the script, set by the shell, nor defined.
+// Defaults such as ${VAR:-x} satisfy a variable, as in compose files.
+func shellEnvRefs(content string, defined func(string) bool) []envRef {
+	var refs []envRef
+
+	local := shellLocalVars(content)
+	isDefined := func(name string) bool {
+		return local[name] || shellBuiltinVars[name] || defined(name)
+	}
+
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, name := range undefinedRefs(stripShellLiterals(line), isDefined) {
+			if !seen[name] {
+				seen[name] = true
+				refs = append(refs, envRef{Name: name, Line: lineNum})
+			}
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,78 @@
+package checker
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestShellEnvRefs(t *testing.T) {
+	script := `#!/usr/bin/env bash
+set -euo pipefail
+
+# $COMMENTED is never expanded
+ROOT="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+export LOG_LEVEL=debug
+
+for file in "$ROOT"/*.sql; do
+  psql "$DATABASE_URL" -f "$file"
+done
+
+read -r name age
+echo "$name $age $1 $@"
+echo 'literal $SINGLE_QUOTED' "\$ESCAPED"
+echo "${PORT:-8080} ${LOG_LEVEL}"
+curl -H "Authorization: $API_TOKEN" "$API_URL/$API_TOKEN"
+`
+
+	refs := shellEnvRefs(script, func(name string) bool { return name == "API_URL" })
+
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.Name)
+	}
+
+	expected := []string{"DATABASE_URL", "API_TOKEN"}
+	if len(got) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got[i])
+		}
+	}
+	if refs[0].Line != 9 {
+		t.Errorf("expected DATABASE_URL on line 9, got %d", refs[0].Line)
+	}
+}
+
+func TestCheckShellScripts(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml":     {Data: []byte("services:\n  api:\n    image: api:1.0\n")},
+		".env":             {Data: []byte("DATABASE_URL=postgres://localhost\n")},
+		"scripts/setup.sh": {Data: []byte("psql \"$DATABASE_URL\"\necho \"$SETUP_TOKEN\"\n")},
+		"tools/deploy":     {Data: []byte("#!/bin/sh\necho \"$DEPLOY_KEY\"\n")},
+		"tools/notes":      {Data: []byte("$NOT_A_SCRIPT\n")},
+	}
+
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	findings := CheckFS(fsys, artifacts, Options{EnableSourceScanning: true})
+
+	locations := make(map[string]string)
+	for _, f := range findings {
+		if f.Code == "SRC001" {
+			locations[f.Files[0].File] = f.Title
+		}
+	}
+
+	if len(locations) != 2 {
+		t.Fatalf("expected SRC001 in 2 scripts, got %v", locations)
+	}
+	if locations["scripts/setup.sh"] != "Environment variable 'SETUP_TOKEN' used in source but not defined" {
+		t.Errorf("unexpected finding for setup.sh: %q", locations["scripts/setup.sh"])
+	}
+	if locations["tools/deploy"] != "Environment variable 'DEPLOY_KEY' used in source but not defined" {
+		t.Errorf("unexpected finding for tools/deploy: %q", locations["tools/deploy"])
+	}
+}
@@ -0,0 +1,111 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/tools"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+)
+
+// stackTool is a tool the detected stack needs. Any of commands will do.
+type stackTool struct {
+	name     string
+	commands []string
+	reason   string
+}
+
+// languageTools maps languages to the toolchain they need
+var languageTools = map[models.Language]stackTool{
+	models.LangNodeJS: {"node", []string{"node"}, "Node.js project"},
+	models.LangGo:     {"go", []string{"go"}, "Go module"},
+	models.LangPython: {"python", []string{"python3", "python"}, "Python project"},
+	models.LangRust:   {"cargo", []string{"cargo"}, "Rust project"},
+	models.LangJava:   {"java", []string{"java"}, "Java project"},
+	models.LangCSharp: {"dotnet", []string{"dotnet"}, "C# project"},
+}
+
+// packageManagerTools maps detected package managers to their commands.
+// Managers provided by the language toolchain (go mod, cargo) are omitted.
+var packageManagerTools = map[string]stackTool{
+	"npm":    {"npm", []string{"npm"}, "npm lockfile"},
+	"pnpm":   {"pnpm", []string{"pnpm"}, "pnpm lockfile"},
+	"yarn":   {"yarn", []string{"yarn"}, "Yarn lockfile"},
+	"pip":    {"pip", []string{"pip3", "pip"}, "pip requirements"},
+	"pipenv": {"pipenv", []string{"pipenv"}, "Pipfile"},
+	"poetry": {"poetry", []string{"poetry"}, "poetry.lock"},
+	"maven":  {"mvn", []string{"mvn"}, "pom.xml"},
+	"gradle": {"gradle", []string{"gradle"}, "Gradle build"},
+}
+
+// wrapperScripts let Maven and Gradle projects build without a global install
+var wrapperScripts = map[string]string{
+	"maven":  "mvnw",
+	"gradle": "gradlew",
+}
+
+// stackTools infers the tools a project needs from its artifacts
+func stackTools(fsys vfs.FS, artifacts *models.Artifacts) []stackTool {
+	var needed []stackTool
+
+	if artifacts.HasCompose() {
+		needed = append(needed, stackTool{"docker", []string{"docker"}, "compose file"})
+	}
+	for _, lang := range artifacts.DetectedLanguages {
+		if tool, ok := languageTools[lang]; ok {
+			needed = append(needed, tool)
+		}
+	}
+	if tool, ok := packageManagerTools[artifacts.PackageManager]; ok {
+		if wrapper, ok := wrapperScripts[artifacts.PackageManager]; !ok || !fileExists(fsys, wrapper) {
+			needed = append(needed, tool)
+		}
+	}
+
+	return needed
+}
+
+// configuredTools returns tools already covered by tool_versions, which
+// report missing installs as TOOL001
+func configuredTools(cfg *config.Config) map[string]bool {
+	configured := make(map[string]bool)
+	if cfg == nil || cfg.ToolVersions == nil {
+		return configured
+	}
+	v := cfg.ToolVersions
+	configured["docker"] = v.Docker != ""
+	configured["go"] = v.Go != ""
+	configured["node"] = v.Node != ""
+	configured["python"] = v.Python != ""
+	return configured
+}
+
+// checkStackTools flags tools the detected stack needs that aren't
+// installed, without requiring tool_versions config
+func checkStackTools(fsys vfs.FS, artifacts *models.Artifacts, cfg *config.Config) []*models.Finding {
+	var findings []*models.Finding
+
+	configured := configuredTools(cfg)
+	for _, tool := range stackTools(fsys, artifacts) {
+		if configured[tool.name] || tools.Installed(tool.commands...) {
+			continue
+		}
+
+		findings = append(findings, models.NewFinding(
+			"TOOL009",
+			models.SeverityWarning,
+			fmt.Sprintf("%s is not installed", tool.name),
+		).WithDetails(fmt.Sprintf("The project needs %s (%s detected), but %s was not found in PATH", tool.name, tool.reason, strings.Join(tool.commands, " or "))).
+			WithFix(fmt.Sprintf("Install %s", tool.name)))
+	}
+
+	return findings
+}
+
+// fileExists checks if a regular file exists in fsys
+func fileExists(fsys vfs.FS, name string) bool {
+	info, err := fsys.Stat(name)
+	return err == nil && !info.IsDir()
+}
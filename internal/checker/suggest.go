@@ -0,0 +1,57 @@
+package checker
+
+import (
+	"strings"
+)
+
+// maxSuggestDistance is the largest edit distance still treated as a typo
+const maxSuggestDistance = 2
+
+// suggestVarName returns the defined variable an undefined reference most
+// likely meant: one differing only in case, or failing that the closest
+// within maxSuggestDistance edits, ignoring case. Short names only match on
+// case, since a couple of edits can turn them into any other short name.
+func suggestVarName(name string, definedVars map[string]bool) string {
+	upper := strings.ToUpper(name)
+	best := ""
+	bestDistance := maxSuggestDistance + 1
+
+	for candidate := range definedVars {
+		if candidate == name {
+			continue
+		}
+		d := levenshtein(upper, strings.ToUpper(candidate))
+		if d > 0 && len(name) <= 2*maxSuggestDistance {
+			continue
+		}
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best = candidate
+			bestDistance = d
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
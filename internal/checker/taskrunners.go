@@ -0,0 +1,116 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// taskRecipe is a recipe or task name and the line it is declared on
+type taskRecipe struct {
+	Name string
+	Line int
+}
+
+// justRecipeRegex matches recipe headers such as "dev:", "@test:" and
+// "build target='all': deps", but not ":=" assignments or settings
+var justRecipeRegex = regexp.MustCompile(`^@?([A-Za-z_][A-Za-z0-9_-]*)(?:\s+[^:]*)?:(?:[^=]|$)`)
+
+// entrypointRecipes are recipe names that usually start a project locally,
+// most likely first
+var entrypointRecipes = []string{"dev", "start", "run", "up", "serve"}
+
+// justRecipes returns the public recipes declared in a Justfile.
+// Recipes starting with an underscore are private.
+func justRecipes(content string) []taskRecipe {
+	var recipes []taskRecipe
+	for i, line := range strings.Split(content, "\n") {
+		match := justRecipeRegex.FindStringSubmatch(line)
+		if match == nil || strings.HasPrefix(match[1], "_") {
+			continue
+		}
+		recipes = append(recipes, taskRecipe{Name: match[1], Line: i + 1})
+	}
+	return recipes
+}
+
+// taskfileTasks returns the non-internal tasks declared in a Taskfile
+func taskfileTasks(content []byte) []taskRecipe {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+
+	tasks := mappingValue(root.Content[0], "tasks")
+	if tasks == nil || tasks.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var recipes []taskRecipe
+	for i := 0; i+1 < len(tasks.Content); i += 2 {
+		key, value := tasks.Content[i], tasks.Content[i+1]
+		if internal := mappingValue(value, "internal"); internal != nil && internal.Value == "true" {
+			continue
+		}
+		recipes = append(recipes, taskRecipe{Name: key.Value, Line: key.Line})
+	}
+	return recipes
+}
+
+// taskRunnerHint describes the recipes of a task runner file, pointing at
+// the likely entrypoint if there is one
+func taskRunnerHint(path, command string, recipes []taskRecipe) *models.Finding {
+	names := make([]string, len(recipes))
+	byName := make(map[string]taskRecipe, len(recipes))
+	for i, r := range recipes {
+		names[i] = r.Name
+		byName[r.Name] = r
+	}
+	details := fmt.Sprintf("Recipes: %s. Run one with: %s <name>", strings.Join(names, ", "), command)
+
+	for _, name := range entrypointRecipes {
+		if r, ok := byName[name]; ok {
+			return models.NewFinding(
+				"HINT005",
+				models.SeverityInfo,
+				fmt.Sprintf("Likely entrypoint: %s %s (from %s)", command, name, path),
+			).WithDetails(details).
+				WithFile(path, r.Line)
+		}
+	}
+
+	return models.NewFinding(
+		"HINT005",
+		models.SeverityInfo,
+		fmt.Sprintf("Recipes in %s: %s", path, strings.Join(names, ", ")),
+	).WithDetails(details).
+		WithFile(path, 0)
+}
+
+// checkTaskRunnerHints surfaces recipes from a Justfile and tasks from a
+// Taskfile as run hints
+func checkTaskRunnerHints(fsys vfs.FS, artifacts *models.Artifacts) []*models.Finding {
+	var findings []*models.Finding
+
+	if artifacts.Justfile != nil && artifacts.Justfile.Found {
+		if content, err := fsys.ReadFile(artifacts.Justfile.Path); err == nil {
+			if recipes := justRecipes(string(content)); len(recipes) > 0 {
+				findings = append(findings, taskRunnerHint(artifacts.Justfile.Path, "just", recipes))
+			}
+		}
+	}
+
+	if artifacts.Taskfile != nil && artifacts.Taskfile.Found {
+		if content, err := fsys.ReadFile(artifacts.Taskfile.Path); err == nil {
+			if tasks := taskfileTasks(content); len(tasks) > 0 {
+				findings = append(findings, taskRunnerHint(artifacts.Taskfile.Path, "task", tasks))
+			}
+		}
+	}
+
+	return findings
+}
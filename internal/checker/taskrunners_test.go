@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stackgen-cli/devcheck/internal/detector"
+)
+
+func TestJustRecipes(t *testing.T) {
+	content := `set dotenv-load := true
+version := "1.0"
+alias b := build
+
+# Build everything
+build target='all': _setup
+    go build ./...
+
+@dev:
+    go run .
+
+_setup:
+    mkdir -p bin
+`
+
+	recipes := justRecipes(content)
+	expected := []taskRecipe{{Name: "build", Line: 6}, {Name: "dev", Line: 9}}
+	if len(recipes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, recipes)
+	}
+	for i := range expected {
+		if recipes[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], recipes[i])
+		}
+	}
+}
+
+func TestCheckTaskRunnerHints(t *testing.T) {
+	fsys := fstest.MapFS{
+		"justfile": {Data: []byte("test:\n    go test ./...\n\nstart:\n    go run .\n")},
+		"Taskfile.yml": {Data: []byte(`version: '3'
+tasks:
+  lint:
+    cmds: [golangci-lint run]
+  setup:
+    internal: true
+    cmds: [go mod download]
+`)},
+	}
+	artifacts := detector.DetectFS(fsys, detector.Options{})
+	findings := CheckFS(fsys, artifacts, Options{})
+
+	var titles []string
+	for _, f := range findings {
+		if f.Code == "HINT005" {
+			titles = append(titles, f.Title)
+		}
+	}
+
+	expected := []string{
+		"Likely entrypoint: just start (from justfile)",
+		"Recipes in Taskfile.yml: lint",
+	}
+	if len(titles) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, titles)
+	}
+	for i := range expected {
+		if titles[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], titles[i])
+		}
+	}
+}
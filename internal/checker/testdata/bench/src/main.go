@@ -0,0 +1,13 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	dsn := os.Getenv("DATABASE_URL")
+	queue := os.Getenv("QUEUE_NAME")
+	level := os.Getenv("LOG_LEVEL")
+	fmt.Println(dsn, queue, level)
+}
@@ -0,0 +1,52 @@
+// module 10
+export function handler1(req) { return req.body + 1; }
+export function handler2(req) { return req.body + 2; }
+export function handler3(req) { return req.body + 3; }
+export function handler4(req) { return req.body + 4; }
+export function handler5(req) { return req.body + 5; }
+export function handler6(req) { return req.body + 6; }
+export function handler7(req) { return req.body + 7; }
+export function handler8(req) { return req.body + 8; }
+export function handler9(req) { return req.body + 9; }
+export function handler10(req) { return req.body + 10; }
+export function handler11(req) { return req.body + 11; }
+export function handler12(req) { return req.body + 12; }
+export function handler13(req) { return req.body + 13; }
+export function handler14(req) { return req.body + 14; }
+export function handler15(req) { return req.body + 15; }
+export function handler16(req) { return req.body + 16; }
+export function handler17(req) { return req.body + 17; }
+export function handler18(req) { return req.body + 18; }
+export function handler19(req) { return req.body + 19; }
+export function handler20(req) { return req.body + 20; }
+export function handler21(req) { return req.body + 21; }
+export function handler22(req) { return req.body + 22; }
+export function handler23(req) { return req.body + 23; }
+export function handler24(req) { return req.body + 24; }
+export function handler25(req) { return req.body + 25; }
+export function handler26(req) { return req.body + 26; }
+export function handler27(req) { return req.body + 27; }
+export function handler28(req) { return req.body + 28; }
+export function handler29(req) { return req.body + 29; }
+export function handler30(req) { return req.body + 30; }
+export function handler31(req) { return req.body + 31; }
+export function handler32(req) { return req.body + 32; }
+export function handler33(req) { return req.body + 33; }
+export function handler34(req) { return req.body + 34; }
+export function handler35(req) { return req.body + 35; }
+export function handler36(req) { return req.body + 36; }
+export function handler37(req) { return req.body + 37; }
+export function handler38(req) { return req.body + 38; }
+export function handler39(req) { return req.body + 39; }
+export function handler40(req) { return req.body + 40; }
+export function handler41(req) { return req.body + 41; }
+export function handler42(req) { return req.body + 42; }
+export function handler43(req) { return req.body + 43; }
+export function handler44(req) { return req.body + 44; }
+export function handler45(req) { return req.body + 45; }
+export function handler46(req) { return req.body + 46; }
+export function handler47(req) { return req.body + 47; }
+export function handler48(req) { return req.body + 48; }
+export function handler49(req) { return req.body + 49; }
+export function handler50(req) { return req.body + 50; }
+const flag10 = process.env.FEATURE_FLAG_10;
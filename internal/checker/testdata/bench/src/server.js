@@ -0,0 +1,18 @@
+const express = require("express");
+
+const app = express();
+const port = process.env.PORT || 3000;
+const dbUrl = process.env.DATABASE_URL;
+const secret = process.env["JWT_SECRET"];
+
+app.get("/health", (req, res) => {
+  res.json({ ok: true, env: process.env.NODE_ENV });
+});
+
+function connect() {
+  return { url: dbUrl, redis: process.env.REDIS_URL };
+}
+
+app.listen(port, () => {
+  console.log(`listening on ${port}`);
+});
@@ -0,0 +1,9 @@
+import os
+
+DATABASE_URL = os.environ["DATABASE_URL"]
+SENTRY_DSN = os.getenv("SENTRY_DSN")
+DEBUG = os.getenv("DEBUG", "false") == "true"
+
+
+def redis_url():
+    return os.environ["REDIS_URL"]
@@ -2,8 +2,12 @@
 package config
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,7 +20,8 @@ type Config struct {
 	// ToolVersions specifies minimum required tool versions
 	ToolVersions *ToolVersions `yaml:"tool_versions,omitempty"`
 
-	// IgnorePatterns are file patterns to ignore during scanning
+	// IgnorePatterns are files and directories source scanning skips, such
+	// as "*.min.js" or "vendor/"
 	IgnorePatterns []string `yaml:"ignore_patterns,omitempty"`
 
 	// IgnoreCodes are finding codes to ignore (e.g., "ENV001")
@@ -25,8 +30,57 @@ type Config struct {
 	// RequiredEnvVars is a list of env vars that must be defined
 	RequiredEnvVars []string `yaml:"required_env_vars,omitempty"`
 
+	// EnvPrefix scopes required_env_vars and custom_rules to variables
+	// starting with this prefix
+	EnvPrefix string `yaml:"env_prefix,omitempty"`
+
 	// BuildContexts maps service names to expected Dockerfile paths
 	BuildContexts map[string]string `yaml:"build_contexts,omitempty"`
+
+	// IncludePaths are extra directories searched for compose, env and
+	// manifest files, relative to the config file
+	IncludePaths []string `yaml:"include_paths,omitempty"`
+
+	// ReadmeHints are extra run commands to look for in the README, such
+	// as "just dev", reported alongside the built-in ones
+	ReadmeHints []string `yaml:"readme_hints,omitempty"`
+
+	// EnvPrecedence orders env files by priority, highest first; the first
+	// one found is compared against .env.example
+	EnvPrecedence []string `yaml:"env_precedence,omitempty"`
+
+	// CodeMetadata describes custom finding codes for list-checks and
+	// supplies defaults for custom_rules that emit them
+	CodeMetadata map[string]CodeMetadata `yaml:"code_metadata,omitempty"`
+
+	// KnownExternalVars are env vars the platform provides, such as CI or
+	// cloud variables, treated as defined by source scanning. They are
+	// merged with a built-in list unless DisableBuiltinExternalVars is set.
+	KnownExternalVars []string `yaml:"known_external_vars,omitempty"`
+
+	// DisableBuiltinExternalVars drops the built-in known external vars,
+	// leaving only KnownExternalVars
+	DisableBuiltinExternalVars bool `yaml:"disable_builtin_external_vars,omitempty"`
+
+	// CheckKeyOrder reports when .env orders its keys differently from
+	// .env.example (ENV026). Off by default since ordering is a style choice.
+	CheckKeyOrder bool `yaml:"check_key_order,omitempty"`
+
+	// Plugins are external check programs run after the built-in checks.
+	// Paths containing a slash are relative to the config file; other
+	// names are looked up in PATH.
+	Plugins []string `yaml:"plugins,omitempty"`
+
+	// PluginTimeout bounds each plugin run, such as "10s"; zero uses the
+	// checker's default
+	PluginTimeout time.Duration `yaml:"plugin_timeout,omitempty"`
+
+	// ScoreWeights overrides the points each finding takes off the
+	// readiness score; unset severities keep their defaults
+	ScoreWeights *ScoreWeights `yaml:"score_weights,omitempty"`
+
+	// path is the file the config was loaded from, if any
+	path string
 }
 
 // CustomRule defines a custom validation rule
@@ -36,6 +90,44 @@ type CustomRule struct {
 	Required    bool   `yaml:"required"`     // Whether matching vars must be defined
 	Description string `yaml:"description"`  // Human-readable description
 	Severity    string `yaml:"severity"`     // blocking, warning, info
+	Code        string `yaml:"code,omitempty"` // Finding code; defaults to CUSTOM-<id>
+}
+
+// FindingCode returns the code the rule's findings are reported under
+func (r CustomRule) FindingCode() string {
+	if r.Code != "" {
+		return r.Code
+	}
+	return "CUSTOM-" + r.ID
+}
+
+// CodeMetadata describes a custom finding code
+type CodeMetadata struct {
+	Description string `yaml:"description"`
+	Severity    string `yaml:"severity"` // blocking, warning, info
+}
+
+// ScoreWeights sets readiness score penalties per severity
+type ScoreWeights struct {
+	Blocking *int `yaml:"blocking,omitempty"`
+	Warning  *int `yaml:"warning,omitempty"`
+	Info     *int `yaml:"info,omitempty"`
+}
+
+// validate rejects negative weights, which would raise the score
+func (w *ScoreWeights) validate() error {
+	if w == nil {
+		return nil
+	}
+	for _, weight := range []struct {
+		name  string
+		value *int
+	}{{"blocking", w.Blocking}, {"warning", w.Warning}, {"info", w.Info}} {
+		if weight.value != nil && *weight.value < 0 {
+			return fmt.Errorf("score_weights.%s must not be negative", weight.name)
+		}
+	}
+	return nil
 }
 
 // ToolVersions specifies minimum tool versions
@@ -59,20 +151,40 @@ func DefaultConfig() *Config {
 // Load attempts to load a config from the given path
 // Returns default config if file doesn't exist
 func Load(basePath string) (*Config, error) {
-	configPaths := []string{
-		filepath.Join(basePath, ".devcheck.yaml"),
-		filepath.Join(basePath, ".devcheck.yml"),
-		filepath.Join(basePath, "devcheck.yaml"),
-		filepath.Join(basePath, "devcheck.yml"),
+	if path := Find(basePath); path != "" {
+		return loadFromFile(path)
 	}
 
-	for _, path := range configPaths {
+	// No config file found, return default
+	return DefaultConfig(), nil
+}
+
+// configNames are the config file names Load looks for, in order
+var configNames = []string{".devcheck.yaml", ".devcheck.yml", "devcheck.yaml", "devcheck.yml"}
+
+// Find returns the config file Load would use in basePath, or ""
+func Find(basePath string) string {
+	for _, name := range configNames {
+		path := filepath.Join(basePath, name)
 		if _, err := os.Stat(path); err == nil {
-			return loadFromFile(path)
+			return path
 		}
 	}
+	return ""
+}
 
-	// No config file found, return default
+// LoadFS loads configuration from the root of fsys, such as an opened
+// archive, falling back to the default config like Load. Plugin and
+// include paths in a config loaded this way resolve against the caller's
+// base directory, since the file has no location on disk.
+func LoadFS(fsys fs.FS) (*Config, error) {
+	for _, name := range configNames {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			continue
+		}
+		return parse(data)
+	}
 	return DefaultConfig(), nil
 }
 
@@ -83,11 +195,27 @@ func loadFromFile(path string) (*Config, error) {
 		return nil, err
 	}
 
+	config, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		config.path = abs
+	}
+
+	return config, nil
+}
+
+// parse decodes a config file over the defaults and validates it
+func parse(data []byte) (*Config, error) {
 	config := DefaultConfig()
 	if err := yaml.Unmarshal(data, config); err != nil {
 		return nil, err
 	}
-
+	if err := config.ScoreWeights.validate(); err != nil {
+		return nil, err
+	}
 	return config, nil
 }
 
@@ -96,6 +224,11 @@ func LoadFromFile(path string) (*Config, error) {
 	return loadFromFile(path)
 }
 
+// MatchesEnvPrefix checks if a variable is in scope for name-based checks
+func (c *Config) MatchesEnvPrefix(name string) bool {
+	return strings.HasPrefix(name, c.EnvPrefix)
+}
+
 // ShouldIgnoreCode checks if a finding code should be ignored
 func (c *Config) ShouldIgnoreCode(code string) bool {
 	for _, ignore := range c.IgnoreCodes {
@@ -106,6 +239,44 @@ func (c *Config) ShouldIgnoreCode(code string) bool {
 	return false
 }
 
+// ResolveIncludePaths returns include_paths as absolute directories,
+// resolved relative to the config file's directory, or baseDir when the
+// config was not loaded from a file
+func (c *Config) ResolveIncludePaths(baseDir string) []string {
+	dir := baseDir
+	if c.path != "" {
+		dir = filepath.Dir(c.path)
+	}
+
+	paths := make([]string, 0, len(c.IncludePaths))
+	for _, p := range c.IncludePaths {
+		if !filepath.IsAbs(p) {
+			p = filepath.Join(dir, p)
+		}
+		paths = append(paths, filepath.Clean(p))
+	}
+	return paths
+}
+
+// ResolvePlugins returns plugins with relative paths resolved against the
+// config file's directory, or baseDir when the config was not loaded from
+// a file. Bare names are kept for a PATH lookup.
+func (c *Config) ResolvePlugins(baseDir string) []string {
+	dir := baseDir
+	if c.path != "" {
+		dir = filepath.Dir(c.path)
+	}
+
+	plugins := make([]string, 0, len(c.Plugins))
+	for _, p := range c.Plugins {
+		if !filepath.IsAbs(p) && strings.ContainsAny(p, `/\`) {
+			p = filepath.Join(dir, p)
+		}
+		plugins = append(plugins, p)
+	}
+	return plugins
+}
+
 // ExampleConfig returns an example configuration string
 func ExampleConfig() string {
 	return `# .devcheck.yaml - devcheck configuration file
@@ -137,10 +308,61 @@ required_env_vars:
   - "NODE_ENV"
   - "DATABASE_URL"
 
+# Only apply required_env_vars and custom_rules to variables with this prefix
+# env_prefix: "MYAPP_"
+
 # Map service names to expected Dockerfile paths
 # devcheck will verify these exist
 build_contexts:
   api: "./api"
   web: "./frontend"
+
+# Extra directories to search for compose, env and manifest files
+# (relative to this file)
+# include_paths:
+#   - "deploy"
+
+# Extra run commands to look for in the README (HINT001), in addition to
+# the built-in ones such as docker compose up and npm run dev
+# readme_hints:
+#   - "just dev"
+#   - "task run"
+
+# Env files in priority order, highest first (default .env, .env.local,
+# .env.development, .env.dev); the first one found is compared against
+# .env.example
+# env_precedence:
+#   - ".env.local"
+#   - ".env"
+
+# Describe your own finding codes; custom_rules with a matching code use
+# these as their default description and severity
+# code_metadata:
+#   ORG001:
+#     description: "Service credentials must come from the vault"
+#     severity: blocking
+
+# Env vars provided by your platform, treated as defined by source scanning
+# (SRC001). Merged with built-ins such as CI, GITHUB_SHA and AWS_REGION;
+# set disable_builtin_external_vars: true to use only this list
+# known_external_vars:
+#   - "PLATFORM_TENANT"
+
+# Report when .env orders shared keys differently from .env.example (ENV026)
+# check_key_order: true
+
+# External check programs, run with the scan's artifacts as JSON on stdin
+# and expected to print {"findings": [...]} on stdout (see README). They
+# only run when scan is given --allow-plugins
+# plugins:
+#   - "./scripts/check-licenses"
+# plugin_timeout: "30s"
+
+# Points each finding takes off the 0-100 readiness score
+# (default blocking 25, warning 5, info 0)
+# score_weights:
+#   blocking: 25
+#   warning: 5
+#   info: 0
 `
 }
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadFromFileScoreWeights(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	cfg, err := LoadFromFile(write("valid.yaml", "score_weights:\n  warning: 10\n  info: 0\n"))
+	if err != nil {
+		t.Fatalf("expected valid weights to load, got %v", err)
+	}
+	if cfg.ScoreWeights == nil || *cfg.ScoreWeights.Warning != 10 || cfg.ScoreWeights.Blocking != nil {
+		t.Errorf("unexpected weights %+v", cfg.ScoreWeights)
+	}
+
+	_, err = LoadFromFile(write("negative.yaml", "score_weights:\n  blocking: 25\n  warning: -5\n"))
+	if err == nil || err.Error() != "score_weights.warning must not be negative" {
+		t.Errorf("expected negative weights to be rejected, got %v", err)
+	}
+
+	if _, err := Load(filepath.Dir(write(".devcheck.yaml", "score_weights:\n  info: -1\n"))); err == nil {
+		t.Error("expected Load to reject negative weights too")
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	cfg, err := LoadFS(fstest.MapFS{
+		"devcheck.yml": {Data: []byte("ignore_codes:\n  - ENV018\n")},
+	})
+	if err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	if !cfg.ShouldIgnoreCode("ENV018") {
+		t.Errorf("expected ignore_codes from the FS, got %v", cfg.IgnoreCodes)
+	}
+
+	cfg, err = LoadFS(fstest.MapFS{})
+	if err != nil || len(cfg.IgnoreCodes) != len(DefaultConfig().IgnoreCodes) {
+		t.Errorf("expected the default config without a file, got %+v, %v", cfg, err)
+	}
+
+	if _, err := LoadFS(fstest.MapFS{".devcheck.yaml": {Data: []byte("score_weights:\n  info: -1\n")}}); err == nil {
+		t.Error("expected LoadFS to reject negative weights")
+	}
+}
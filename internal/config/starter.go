@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// starterSection is a top-level key in a generated config with its comment
+type starterSection struct {
+	key     string
+	comment string
+	value   interface{}
+	empty   bool
+}
+
+// StarterConfig renders seed as a commented .devcheck.yaml for the named
+// profile. Only the fields set in seed are written.
+func StarterConfig(profile, description string, seed *Config) string {
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "# .devcheck.yaml - devcheck configuration for the %q profile\n", profile)
+	fmt.Fprintf(&sb, "# %s\n", description)
+	sb.WriteString("#\n")
+	fmt.Fprintf(&sb, "# Use with: devcheck scan --profile %s\n", profile)
+
+	sections := []starterSection{
+		{"custom_rules", "Define custom rules for environment variable validation", seed.CustomRules, len(seed.CustomRules) == 0},
+		{"tool_versions", "Minimum tool versions (checked with --check-tools)", seed.ToolVersions, seed.ToolVersions == nil},
+		{"ignore_codes", "Finding codes to ignore", seed.IgnoreCodes, len(seed.IgnoreCodes) == 0},
+		{"required_env_vars", "Environment variables that must always be defined", seed.RequiredEnvVars, len(seed.RequiredEnvVars) == 0},
+	}
+
+	for _, s := range sections {
+		if s.empty {
+			continue
+		}
+		var out strings.Builder
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err := enc.Encode(map[string]interface{}{s.key: s.value}); err != nil {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n# %s\n%s", s.comment, out.String())
+	}
+
+	return sb.String()
+}
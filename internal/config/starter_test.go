@@ -0,0 +1,30 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStarterConfigRoundTrip(t *testing.T) {
+	seed := &Config{
+		ToolVersions: &ToolVersions{Docker: "20.10.0"},
+		IgnoreCodes:  []string{"HINT001"},
+	}
+
+	path := filepath.Join(t.TempDir(), ".devcheck.yaml")
+	if err := os.WriteFile(path, []byte(StarterConfig("ci", "CI mode", seed)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadFromFile(path)
+	if err != nil {
+		t.Fatalf("generated config does not load: %v", err)
+	}
+	if cfg.ToolVersions == nil || cfg.ToolVersions.Docker != "20.10.0" {
+		t.Errorf("expected docker 20.10.0, got %+v", cfg.ToolVersions)
+	}
+	if !cfg.ShouldIgnoreCode("HINT001") || len(cfg.CustomRules) != 0 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError is a problem found in a config file. Line is 0 when the
+// position is unknown.
+type ValidationError struct {
+	Line    int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+	}
+	return e.Message
+}
+
+// validSeverities are the severities custom_rules accept; empty means warning
+var validSeverities = map[string]bool{"": true, "blocking": true, "warning": true, "info": true}
+
+// versionRegex matches the minimum versions tool_versions accepts
+var versionRegex = regexp.MustCompile(`^v?[0-9]+(\.[0-9]+){0,2}$`)
+
+// typeErrorLineRegex splits yaml.v3 type errors into line and message
+var typeErrorLineRegex = regexp.MustCompile(`^line ([0-9]+): (.*)$`)
+
+// unknownFieldRegex rewrites yaml.v3's unknown field errors without Go type names
+var unknownFieldRegex = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// Validate checks config content strictly: unknown fields, wrong types,
+// custom_rules patterns that don't compile, unknown severities, malformed
+// tool versions and custom codes for which builtin reports true. It
+// returns every problem found. builtin may be nil.
+func Validate(data []byte, builtin func(code string) bool) []ValidationError {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return []ValidationError{{Message: err.Error()}}
+	}
+
+	var problems []ValidationError
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&Config{}); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if !errors.As(err, &typeErr) {
+			return []ValidationError{{Message: err.Error()}}
+		}
+		for _, msg := range typeErr.Errors {
+			problem := ValidationError{Message: msg}
+			if m := typeErrorLineRegex.FindStringSubmatch(msg); m != nil {
+				problem.Line, _ = strconv.Atoi(m[1])
+				problem.Message = unknownFieldRegex.ReplaceAllString(m[2], "unknown field $1")
+			}
+			problems = append(problems, problem)
+		}
+	}
+
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return problems
+	}
+	doc := root.Content[0]
+
+	if rules := nodeValue(doc, "custom_rules"); rules != nil && rules.Kind == yaml.SequenceNode {
+		for i, rule := range rules.Content {
+			if pattern := nodeValue(rule, "pattern"); pattern == nil || pattern.Value == "" {
+				problems = append(problems, ValidationError{Line: rule.Line, Message: fmt.Sprintf("custom_rules[%d] has no pattern", i)})
+			} else if _, err := regexp.Compile(pattern.Value); err != nil {
+				problems = append(problems, ValidationError{Line: pattern.Line, Message: fmt.Sprintf("custom_rules[%d] pattern does not compile: %v", i, err)})
+			}
+
+			if severity := nodeValue(rule, "severity"); severity != nil && !validSeverities[severity.Value] {
+				problems = append(problems, ValidationError{Line: severity.Line, Message: fmt.Sprintf("custom_rules[%d] has unknown severity %q (use blocking, warning or info)", i, severity.Value)})
+			}
+
+			if code := nodeValue(rule, "code"); code != nil && builtin != nil && builtin(code.Value) {
+				problems = append(problems, ValidationError{Line: code.Line, Message: fmt.Sprintf("custom_rules[%d] code %s is a built-in code", i, code.Value)})
+			}
+		}
+	}
+
+	if versions := nodeValue(doc, "tool_versions"); versions != nil && versions.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(versions.Content); i += 2 {
+			key, value := versions.Content[i], versions.Content[i+1]
+			if value.Value != "" && !versionRegex.MatchString(value.Value) {
+				problems = append(problems, ValidationError{Line: value.Line, Message: fmt.Sprintf("tool_versions.%s %q is not a version like 20.10.0", key.Value, value.Value)})
+			}
+		}
+	}
+
+	if metadata := nodeValue(doc, "code_metadata"); metadata != nil && metadata.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(metadata.Content); i += 2 {
+			key, value := metadata.Content[i], metadata.Content[i+1]
+			if builtin != nil && builtin(key.Value) {
+				problems = append(problems, ValidationError{Line: key.Line, Message: fmt.Sprintf("code_metadata.%s is a built-in code", key.Value)})
+			}
+			if severity := nodeValue(value, "severity"); severity != nil && !validSeverities[severity.Value] {
+				problems = append(problems, ValidationError{Line: severity.Line, Message: fmt.Sprintf("code_metadata.%s has unknown severity %q (use blocking, warning or info)", key.Value, severity.Value)})
+			}
+		}
+	}
+
+	if weights := nodeValue(doc, "score_weights"); weights != nil && weights.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(weights.Content); i += 2 {
+			key, value := weights.Content[i], weights.Content[i+1]
+			if n, err := strconv.Atoi(value.Value); err == nil && n < 0 {
+				problems = append(problems, ValidationError{Line: value.Line, Message: fmt.Sprintf("score_weights.%s must not be negative", key.Value)})
+			}
+		}
+	}
+
+	return problems
+}
+
+// BuiltinCodes returns the custom_rules codes and code_metadata keys for
+// which builtin reports true. A scan refuses such a config, since the rule's
+// findings would be reported under a built-in code.
+func (c *Config) BuiltinCodes(builtin func(code string) bool) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, rule := range c.CustomRules {
+		if code := rule.FindingCode(); builtin(code) && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	var metadata []string
+	for code := range c.CodeMetadata {
+		if builtin(code) && !seen[code] {
+			metadata = append(metadata, code)
+		}
+	}
+	sort.Strings(metadata)
+	return append(codes, metadata...)
+}
+
+// nodeValue returns the value for key in a mapping node, or nil
+func nodeValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	valid := []byte(ExampleConfig())
+	if problems := Validate(valid, nil); len(problems) != 0 {
+		t.Fatalf("expected the example config to be valid, got %v", problems)
+	}
+
+	invalid := []byte(`custom_rules:
+  - id: "DB"
+    pattern: "^(DATABASE_"
+    severity: critical
+tool_versions:
+  docker: ">=20.10"
+  node: "18"
+ignore_code:
+  - HINT001
+code_metadata:
+  ENV001:
+    description: "Shadows a built-in"
+  ORG001:
+    severity: fatal
+score_weights:
+  warning: -5
+`)
+
+	expected := []ValidationError{
+		{Line: 8, Message: "unknown field ignore_code"},
+		{Line: 3, Message: "custom_rules[0] pattern does not compile: error parsing regexp: missing closing ): `^(DATABASE_`"},
+		{Line: 4, Message: `custom_rules[0] has unknown severity "critical" (use blocking, warning or info)`},
+		{Line: 6, Message: `tool_versions.docker ">=20.10" is not a version like 20.10.0`},
+		{Line: 11, Message: "code_metadata.ENV001 is a built-in code"},
+		{Line: 14, Message: `code_metadata.ORG001 has unknown severity "fatal" (use blocking, warning or info)`},
+		{Line: 16, Message: "score_weights.warning must not be negative"},
+	}
+
+	builtin := func(code string) bool { return code == "ENV001" }
+	problems := Validate(invalid, builtin)
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %d problems, got %v", len(expected), problems)
+	}
+	for i := range expected {
+		if problems[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], problems[i])
+		}
+	}
+}
+
+func TestBuiltinCodes(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.CustomRules = []CustomRule{
+		{ID: "DB", Pattern: "^DB_", Code: "ENV001"},
+		{ID: "API", Pattern: "^API_"},
+		{ID: "AGAIN", Pattern: "^X_", Code: "ENV001"},
+	}
+	cfg.CodeMetadata = map[string]CodeMetadata{
+		"ORG001": {Description: "Custom"},
+		"CMP001": {Description: "Shadows a built-in"},
+		"ENV001": {Description: "Shadows a built-in"},
+	}
+	builtin := func(code string) bool { return code == "ENV001" || code == "CMP001" }
+
+	codes := cfg.BuiltinCodes(builtin)
+	expected := []string{"ENV001", "CMP001"}
+	if len(codes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, codes)
+	}
+	for i := range expected {
+		if codes[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], codes[i])
+		}
+	}
+
+	if codes := DefaultConfig().BuiltinCodes(builtin); len(codes) != 0 {
+		t.Errorf("expected no built-in codes in the default config, got %v", codes)
+	}
+}
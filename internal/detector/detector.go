@@ -1,54 +1,119 @@
 package detector
 
 import (
-	"os"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
 	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
 )
 
+// Options configures artifact detection
+type Options struct {
+	// ComposeOverride is an explicit compose file path
+	ComposeOverride string
+	// EnvOverrides are explicit env file paths
+	EnvOverrides []string
+	// IncludePaths are extra directories searched for compose, env and
+	// manifest files. Artifacts found there keep paths relative to basePath.
+	IncludePaths []string
+	// FS is the project tree to inspect; defaults to basePath on disk
+	FS vfs.FS
+}
+
 // Detect scans a directory for project artifacts
 func Detect(basePath string, composeOverride string, envOverrides []string) *models.Artifacts {
+	return DetectWithOptions(basePath, Options{
+		ComposeOverride: composeOverride,
+		EnvOverrides:    envOverrides,
+	})
+}
+
+// DetectFS scans an fs.FS, such as an fstest.MapFS or an embedded tree,
+// for project artifacts. It replaces opts.FS.
+func DetectFS(fsys fs.FS, opts Options) *models.Artifacts {
+	opts.FS = vfs.FromFS(fsys)
+	return DetectWithOptions(".", opts)
+}
+
+// DetectWithOptions scans a directory, and any include paths, for project artifacts
+func DetectWithOptions(basePath string, opts Options) *models.Artifacts {
 	artifacts := models.NewArtifacts()
 
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = vfs.OS(basePath)
+	}
+
 	// Detect compose files
-	detectComposeFiles(basePath, composeOverride, artifacts)
+	detectComposeFiles(fsys, basePath, opts.ComposeOverride, artifacts)
 
 	// Detect env files
-	detectEnvFiles(basePath, envOverrides, artifacts)
+	detectEnvFiles(fsys, basePath, opts.EnvOverrides, artifacts)
 
 	// Detect language manifests
-	detectManifests(basePath, artifacts)
+	detectManifests(fsys, "", artifacts)
+
+	// Search include paths after the root so root artifacts take precedence
+	for _, dir := range opts.IncludePaths {
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(basePath, dir)
+		}
+		rel, err := filepath.Rel(basePath, dir)
+		if err != nil || rel == "." {
+			continue
+		}
+		detectComposeCandidates(fsys, rel, artifacts)
+		detectEnvCandidates(fsys, rel, false, artifacts)
+		detectManifests(fsys, rel, artifacts)
+	}
+
+	// Detect env files in service build contexts
+	detectServiceEnvFiles(fsys, artifacts)
 
 	// Detect README
-	detectReadme(basePath, artifacts)
+	detectReadme(fsys, artifacts)
 
 	// Detect Makefile
-	detectMakefile(basePath, artifacts)
+	detectMakefile(fsys, artifacts)
+
+	// Detect just and Task runner files
+	detectTaskRunners(fsys, artifacts)
+
+	// Detect Procfile
+	detectProcfile(fsys, artifacts)
+
+	// Detect dev container config
+	detectDevcontainer(fsys, artifacts)
 
 	return artifacts
 }
 
 // detectComposeFiles looks for Docker Compose files
-func detectComposeFiles(basePath string, override string, artifacts *models.Artifacts) {
+func detectComposeFiles(fsys vfs.FS, basePath string, override string, artifacts *models.Artifacts) {
 	// Check override first
 	if override != "" {
-		fullPath := override
-		if !filepath.IsAbs(override) {
-			fullPath = filepath.Join(basePath, override)
-		}
-		found := fileExists(fullPath)
+		override = vfs.RelPath(basePath, override)
+		found := fileExists(fsys, override)
+		target, _ := vfs.BrokenLink(fsys, override)
 		artifacts.ComposeFiles = append(artifacts.ComposeFiles, models.Artifact{
-			Type:  models.ArtifactCompose,
-			Path:  override,
-			Found: found,
+			Type:       models.ArtifactCompose,
+			Path:       override,
+			Found:      found,
+			LinkTarget: target,
 		})
 		if found {
 			return // Only use override if specified
 		}
 	}
 
+	detectComposeCandidates(fsys, "", artifacts)
+}
+
+// detectComposeCandidates looks for standard compose file names in the
+// directory rel (relative to basePath)
+func detectComposeCandidates(fsys vfs.FS, rel string, artifacts *models.Artifacts) {
 	// Standard compose file names
 	candidates := []string{
 		"compose.yaml",
@@ -60,28 +125,31 @@ func detectComposeFiles(basePath string, override string, artifacts *models.Arti
 	}
 
 	for _, name := range candidates {
-		fullPath := filepath.Join(basePath, name)
-		found := fileExists(fullPath)
+		path := filepath.Join(rel, name)
+		found := fileExists(fsys, path)
 		if found {
 			artifacts.ComposeFiles = append(artifacts.ComposeFiles, models.Artifact{
 				Type:  models.ArtifactCompose,
-				Path:  name,
+				Path:  path,
 				Found: true,
 			})
+		} else if target, broken := vfs.BrokenLink(fsys, path); broken {
+			artifacts.ComposeFiles = append(artifacts.ComposeFiles, models.Artifact{
+				Type:       models.ArtifactCompose,
+				Path:       path,
+				LinkTarget: target,
+			})
 		}
 	}
 }
 
 // detectEnvFiles looks for environment files
-func detectEnvFiles(basePath string, overrides []string, artifacts *models.Artifacts) {
+func detectEnvFiles(fsys vfs.FS, basePath string, overrides []string, artifacts *models.Artifacts) {
 	// Check overrides first
 	if len(overrides) > 0 {
 		for _, override := range overrides {
-			fullPath := override
-			if !filepath.IsAbs(override) {
-				fullPath = filepath.Join(basePath, override)
-			}
-			found := fileExists(fullPath)
+			override = vfs.RelPath(basePath, override)
+			found := fileExists(fsys, override)
 			if strings.Contains(override, "example") {
 				artifacts.EnvExamples = append(artifacts.EnvExamples, models.Artifact{
 					Type:  models.ArtifactEnvExample,
@@ -99,6 +167,13 @@ func detectEnvFiles(basePath string, overrides []string, artifacts *models.Artif
 		return
 	}
 
+	detectEnvCandidates(fsys, "", true, artifacts)
+}
+
+// detectEnvCandidates looks for standard env file names in the directory
+// rel (relative to basePath). Missing env files are recorded only when
+// includeMissing is set, so the root reports what it looked for.
+func detectEnvCandidates(fsys vfs.FS, rel string, includeMissing bool, artifacts *models.Artifacts) {
 	// Standard env file names
 	envCandidates := []string{
 		".env",
@@ -115,30 +190,41 @@ func detectEnvFiles(basePath string, overrides []string, artifacts *models.Artif
 	}
 
 	for _, name := range envCandidates {
-		fullPath := filepath.Join(basePath, name)
-		found := fileExists(fullPath)
-		artifacts.EnvFiles = append(artifacts.EnvFiles, models.Artifact{
-			Type:  models.ArtifactEnv,
-			Path:  name,
-			Found: found,
-		})
+		path := filepath.Join(rel, name)
+		found := fileExists(fsys, path)
+		target, broken := vfs.BrokenLink(fsys, path)
+		if found || broken || includeMissing {
+			artifacts.EnvFiles = append(artifacts.EnvFiles, models.Artifact{
+				Type:       models.ArtifactEnv,
+				Path:       path,
+				Found:      found,
+				LinkTarget: target,
+			})
+		}
 	}
 
 	for _, name := range exampleCandidates {
-		fullPath := filepath.Join(basePath, name)
-		found := fileExists(fullPath)
+		path := filepath.Join(rel, name)
+		found := fileExists(fsys, path)
 		if found {
 			artifacts.EnvExamples = append(artifacts.EnvExamples, models.Artifact{
 				Type:  models.ArtifactEnvExample,
-				Path:  name,
+				Path:  path,
 				Found: true,
 			})
+		} else if target, broken := vfs.BrokenLink(fsys, path); broken {
+			artifacts.EnvExamples = append(artifacts.EnvExamples, models.Artifact{
+				Type:       models.ArtifactEnvExample,
+				Path:       path,
+				LinkTarget: target,
+			})
 		}
 	}
 }
 
-// detectManifests looks for language-specific manifest files
-func detectManifests(basePath string, artifacts *models.Artifacts) {
+// detectManifests looks for language-specific manifest files in the
+// directory rel (relative to basePath)
+func detectManifests(fsys vfs.FS, rel string, artifacts *models.Artifacts) {
 	manifests := []struct {
 		file    string
 		lang    models.Language
@@ -179,15 +265,14 @@ func detectManifests(basePath string, artifacts *models.Artifacts) {
 
 		if strings.Contains(m.file, "*") {
 			// Glob pattern
-			matches, _ := filepath.Glob(filepath.Join(basePath, m.file))
+			matches, _ := fsys.Glob(filepath.Join(rel, m.file))
 			found = len(matches) > 0
 			if found {
-				actualPath = filepath.Base(matches[0])
+				actualPath = matches[0]
 			}
 		} else {
-			fullPath := filepath.Join(basePath, m.file)
-			found = fileExists(fullPath)
-			actualPath = m.file
+			actualPath = filepath.Join(rel, m.file)
+			found = fileExists(fsys, actualPath)
 		}
 
 		if found {
@@ -204,6 +289,11 @@ func detectManifests(basePath string, artifacts *models.Artifacts) {
 				artifacts.DetectedLang = m.lang
 			}
 
+			// Record every language once, in detection order
+			if !containsLanguage(artifacts.DetectedLanguages, m.lang) {
+				artifacts.DetectedLanguages = append(artifacts.DetectedLanguages, m.lang)
+			}
+
 			// Set package manager if more specific
 			if m.pkgMgr != "" && artifacts.PackageManager == "" {
 				artifacts.PackageManager = m.pkgMgr
@@ -213,7 +303,7 @@ func detectManifests(basePath string, artifacts *models.Artifacts) {
 }
 
 // detectReadme looks for README files
-func detectReadme(basePath string, artifacts *models.Artifacts) {
+func detectReadme(fsys vfs.FS, artifacts *models.Artifacts) {
 	candidates := []string{
 		"README.md",
 		"README.MD",
@@ -223,8 +313,7 @@ func detectReadme(basePath string, artifacts *models.Artifacts) {
 	}
 
 	for _, name := range candidates {
-		fullPath := filepath.Join(basePath, name)
-		if fileExists(fullPath) {
+		if fileExists(fsys, name) {
 			artifacts.Readme = &models.Artifact{
 				Type:  models.ArtifactReadme,
 				Path:  name,
@@ -236,7 +325,7 @@ func detectReadme(basePath string, artifacts *models.Artifacts) {
 }
 
 // detectMakefile looks for Makefile
-func detectMakefile(basePath string, artifacts *models.Artifacts) {
+func detectMakefile(fsys vfs.FS, artifacts *models.Artifacts) {
 	candidates := []string{
 		"Makefile",
 		"makefile",
@@ -244,8 +333,7 @@ func detectMakefile(basePath string, artifacts *models.Artifacts) {
 	}
 
 	for _, name := range candidates {
-		fullPath := filepath.Join(basePath, name)
-		if fileExists(fullPath) {
+		if fileExists(fsys, name) {
 			artifacts.Makefile = &models.Artifact{
 				Type:  models.ArtifactMakefile,
 				Path:  name,
@@ -256,9 +344,75 @@ func detectMakefile(basePath string, artifacts *models.Artifacts) {
 	}
 }
 
+// detectTaskRunners looks for a Justfile and a Taskfile
+func detectTaskRunners(fsys vfs.FS, artifacts *models.Artifacts) {
+	for _, name := range []string{"justfile", "Justfile", ".justfile"} {
+		if fileExists(fsys, name) {
+			artifacts.Justfile = &models.Artifact{
+				Type:  models.ArtifactJustfile,
+				Path:  name,
+				Found: true,
+			}
+			break
+		}
+	}
+
+	for _, name := range []string{"Taskfile.yml", "Taskfile.yaml", "taskfile.yml", "taskfile.yaml"} {
+		if fileExists(fsys, name) {
+			artifacts.Taskfile = &models.Artifact{
+				Type:  models.ArtifactTaskfile,
+				Path:  name,
+				Found: true,
+			}
+			break
+		}
+	}
+}
+
+// detectProcfile looks for a Heroku-style Procfile
+func detectProcfile(fsys vfs.FS, artifacts *models.Artifacts) {
+	if fileExists(fsys, "Procfile") {
+		artifacts.Procfile = &models.Artifact{
+			Type:  models.ArtifactProcfile,
+			Path:  "Procfile",
+			Found: true,
+		}
+	}
+}
+
+// detectDevcontainer looks for a VS Code dev container config
+func detectDevcontainer(fsys vfs.FS, artifacts *models.Artifacts) {
+	candidates := []string{
+		filepath.Join(".devcontainer", "devcontainer.json"),
+		".devcontainer.json",
+		"devcontainer.json",
+	}
+
+	for _, name := range candidates {
+		if fileExists(fsys, name) {
+			artifacts.Devcontainer = &models.Artifact{
+				Type:  models.ArtifactDevcontainer,
+				Path:  name,
+				Found: true,
+			}
+			return
+		}
+	}
+}
+
+// containsLanguage checks if lang is already in langs
+func containsLanguage(langs []models.Language, lang models.Language) bool {
+	for _, l := range langs {
+		if l == lang {
+			return true
+		}
+	}
+	return false
+}
+
 // fileExists checks if a file exists
-func fileExists(path string) bool {
-	info, err := os.Stat(path)
+func fileExists(fsys vfs.FS, name string) bool {
+	info, err := fsys.Stat(name)
 	if err != nil {
 		return false
 	}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stackgen-cli/devcheck/internal/models"
 )
@@ -172,3 +173,111 @@ func TestDetectWithCustomEnvFiles(t *testing.T) {
 		}
 	}
 }
+
+func TestDetectPolyglotLanguages(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "devcheck-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files := map[string]string{
+		"package.json":     `{"name": "test"}`,
+		"go.mod":           `module test`,
+		"requirements.txt": `flask>=2.0`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+	}
+
+	artifacts := Detect(tmpDir, "", nil)
+
+	if artifacts.DetectedLang != models.LangNodeJS {
+		t.Errorf("expected primary language %s, got %s", models.LangNodeJS, artifacts.DetectedLang)
+	}
+
+	expected := []models.Language{models.LangNodeJS, models.LangGo, models.LangPython}
+	if len(artifacts.DetectedLanguages) != len(expected) {
+		t.Fatalf("expected languages %v, got %v", expected, artifacts.DetectedLanguages)
+	}
+	for i, lang := range expected {
+		if artifacts.DetectedLanguages[i] != lang {
+			t.Errorf("expected language %d to be %s, got %s", i, lang, artifacts.DetectedLanguages[i])
+		}
+	}
+}
+
+func TestDetectWithIncludePaths(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "devcheck-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	deployDir := filepath.Join(tmpDir, "deploy")
+	if err := os.Mkdir(deployDir, 0755); err != nil {
+		t.Fatalf("failed to create deploy dir: %v", err)
+	}
+	files := map[string]string{
+		filepath.Join(tmpDir, "package.json"):        `{"name": "test"}`,
+		filepath.Join(deployDir, "compose.yaml"):     "services: {}",
+		filepath.Join(deployDir, ".env"):             "KEY=value",
+		filepath.Join(deployDir, "requirements.txt"): "flask",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to create %s: %v", path, err)
+		}
+	}
+
+	artifacts := DetectWithOptions(tmpDir, Options{IncludePaths: []string{deployDir}})
+
+	expectFound := func(list []models.Artifact, path string) {
+		t.Helper()
+		for _, a := range list {
+			if a.Path == path && a.Found {
+				return
+			}
+		}
+		t.Errorf("expected to find %s", path)
+	}
+	expectFound(artifacts.ComposeFiles, filepath.Join("deploy", "compose.yaml"))
+	expectFound(artifacts.EnvFiles, filepath.Join("deploy", ".env"))
+	expectFound(artifacts.Manifests, filepath.Join("deploy", "requirements.txt"))
+
+	if artifacts.DetectedLang != models.LangNodeJS {
+		t.Errorf("expected root language %s to stay primary, got %s", models.LangNodeJS, artifacts.DetectedLang)
+	}
+}
+
+func TestDetectFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"compose.yaml":        {Data: []byte("services: {}\n")},
+		".env.example":        {Data: []byte("FOO=\n")},
+		"go.mod":              {Data: []byte("module example\n")},
+		"App/App.csproj":      {Data: []byte("<Project />\n")},
+		"README.md":           {Data: []byte("# Example\n")},
+		"deploy/compose.yaml": {Data: []byte("services: {}\n")},
+	}
+
+	artifacts := DetectFS(fsys, Options{IncludePaths: []string{"deploy", "../outside"}})
+
+	var composePaths []string
+	for _, c := range artifacts.ComposeFiles {
+		composePaths = append(composePaths, c.Path)
+	}
+	if len(composePaths) != 2 || composePaths[0] != "compose.yaml" || composePaths[1] != filepath.Join("deploy", "compose.yaml") {
+		t.Errorf("unexpected compose files: %v", composePaths)
+	}
+	if !artifacts.HasEnvExample() || artifacts.HasEnv() {
+		t.Error("expected .env.example but no .env")
+	}
+	if artifacts.DetectedLang != models.LangGo {
+		t.Errorf("expected Go, got %s", artifacts.DetectedLang)
+	}
+	if artifacts.Readme == nil || artifacts.Readme.Path != "README.md" {
+		t.Error("expected README.md to be detected")
+	}
+}
@@ -0,0 +1,94 @@
+package detector
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/vfs"
+	"gopkg.in/yaml.v3"
+)
+
+// composeBuilds is the part of a compose file that locates build contexts
+type composeBuilds struct {
+	Services map[string]struct {
+		Build yaml.Node `yaml:"build"`
+	} `yaml:"services"`
+}
+
+// detectServiceEnvFiles adds the .env kept in a compose service's build
+// context, such as services/api/.env in a monorepo, as an env file scoped
+// to the services built there. Contexts are relative to the compose file's
+// directory. Env files already detected are project-wide and left alone.
+func detectServiceEnvFiles(fsys vfs.FS, artifacts *models.Artifacts) {
+	listed := make(map[string]bool)
+	for _, e := range artifacts.EnvFiles {
+		listed[filepath.Clean(e.Path)] = true
+	}
+	local := make(map[string]int)
+
+	for _, compose := range artifacts.ComposeFiles {
+		if !compose.Found {
+			continue
+		}
+		data, err := fsys.ReadFile(compose.Path)
+		if err != nil {
+			continue
+		}
+		var doc composeBuilds
+		if yaml.Unmarshal(data, &doc) != nil {
+			continue
+		}
+
+		names := make([]string, 0, len(doc.Services))
+		for name := range doc.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			context := buildContextPath(doc.Services[name].Build)
+			if context == "" || filepath.IsAbs(context) {
+				continue
+			}
+			envPath := filepath.Join(filepath.Dir(compose.Path), context, ".env")
+			if listed[envPath] {
+				continue
+			}
+			if i, ok := local[envPath]; ok {
+				artifacts.EnvFiles[i].Services = append(artifacts.EnvFiles[i].Services, name)
+				continue
+			}
+			if !fileExists(fsys, envPath) {
+				continue
+			}
+			local[envPath] = len(artifacts.EnvFiles)
+			artifacts.EnvFiles = append(artifacts.EnvFiles, models.Artifact{
+				Type:     models.ArtifactEnv,
+				Path:     envPath,
+				Found:    true,
+				Services: []string{name},
+			})
+		}
+	}
+}
+
+// buildContextPath returns the context of a service's build entry, either
+// the short form or build.context, or "" if it doesn't build or the
+// context is remote
+func buildContextPath(build yaml.Node) string {
+	context := build.Value
+	if build.Kind == yaml.MappingNode {
+		context = "."
+		for i := 0; i+1 < len(build.Content); i += 2 {
+			if build.Content[i].Value == "context" {
+				context = build.Content[i+1].Value
+			}
+		}
+	}
+	if context == "" || strings.Contains(context, "://") || strings.HasPrefix(context, "git@") {
+		return ""
+	}
+	return filepath.Clean(context)
+}
@@ -0,0 +1,267 @@
+// Package fixer applies the safe subset of suggested fixes to a project
+package fixer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// Status describes what happened to a finding's fix
+type Status string
+
+const (
+	StatusApplied Status = "applied"
+	StatusSkipped Status = "skipped"
+	StatusManual  Status = "manual"
+)
+
+// Options configures how fixes are applied
+type Options struct {
+	// AssumeYes applies every safe fix without prompting
+	AssumeYes bool
+	// In is read for confirmation answers
+	In io.Reader
+	// Out receives prompts
+	Out io.Writer
+}
+
+// Outcome records the result of fixing a single finding
+type Outcome struct {
+	Finding *models.Finding
+	Status  Status
+	Reason  string
+}
+
+// Result is the outcome of a fix run
+type Result struct {
+	Outcomes []Outcome
+}
+
+// Count returns the number of outcomes with the given status
+func (r *Result) Count(status Status) int {
+	count := 0
+	for _, o := range r.Outcomes {
+		if o.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// actionOrder makes copies run before appends so a freshly copied .env
+// is not shadowed by a stub created from an append
+var actionOrder = map[models.FixAction]int{
+	models.FixCopyFile:   0,
+	models.FixCreateDir:  1,
+	models.FixAppendLine: 2,
+}
+
+// Apply applies safe fixes for the given findings under basePath.
+// Existing files are never overwritten; fixes without a structured
+// command are reported as manual.
+func Apply(basePath string, findings []*models.Finding, opts Options) *Result {
+	result := &Result{}
+
+	var fixable []*models.Finding
+	for _, f := range findings {
+		if f.FixCommand == nil {
+			if f.SuggestedFix != "" {
+				result.Outcomes = append(result.Outcomes, Outcome{Finding: f, Status: StatusManual})
+			}
+			continue
+		}
+		fixable = append(fixable, f)
+	}
+
+	sort.SliceStable(fixable, func(i, j int) bool {
+		return actionOrder[fixable[i].FixCommand.Action] < actionOrder[fixable[j].FixCommand.Action]
+	})
+
+	var reader *bufio.Reader
+	if opts.In != nil {
+		reader = bufio.NewReader(opts.In)
+	}
+
+	seen := make(map[models.FixCommand]bool)
+	for _, f := range fixable {
+		cmd := *f.FixCommand
+		if seen[cmd] {
+			result.Outcomes = append(result.Outcomes, Outcome{Finding: f, Status: StatusSkipped, Reason: "duplicate of an earlier fix"})
+			continue
+		}
+		seen[cmd] = true
+
+		if !opts.AssumeYes && !confirm(reader, opts.Out, Describe(&cmd)) {
+			result.Outcomes = append(result.Outcomes, Outcome{Finding: f, Status: StatusSkipped, Reason: "declined"})
+			continue
+		}
+
+		applied, reason := applyCommand(basePath, &cmd)
+		status := StatusSkipped
+		if applied {
+			status = StatusApplied
+		}
+		result.Outcomes = append(result.Outcomes, Outcome{Finding: f, Status: status, Reason: reason})
+	}
+
+	return result
+}
+
+// Describe returns a human-readable description of a fix command
+func Describe(cmd *models.FixCommand) string {
+	switch cmd.Action {
+	case models.FixCopyFile:
+		return fmt.Sprintf("Copy %s to %s", cmd.Source, cmd.Path)
+	case models.FixAppendLine:
+		return fmt.Sprintf("Append %s to %s", cmd.Content, cmd.Path)
+	case models.FixCreateDir:
+		return fmt.Sprintf("Create directory %s", cmd.Path)
+	default:
+		return fmt.Sprintf("%s %s", cmd.Action, cmd.Path)
+	}
+}
+
+// WriteSummary prints applied, skipped and manual fixes
+func (r *Result) WriteSummary(w io.Writer) {
+	fmt.Fprintf(w, "Fixes: %d applied, %d skipped, %d manual\n",
+		r.Count(StatusApplied), r.Count(StatusSkipped), r.Count(StatusManual))
+
+	for _, o := range r.Outcomes {
+		switch o.Status {
+		case StatusApplied:
+			fmt.Fprintf(w, "  ✓ [%s] %s\n", o.Finding.Code, Describe(o.Finding.FixCommand))
+		case StatusSkipped:
+			fmt.Fprintf(w, "  - [%s] %s (%s)\n", o.Finding.Code, Describe(o.Finding.FixCommand), o.Reason)
+		case StatusManual:
+			fmt.Fprintf(w, "  ✗ [%s] manual: %s\n", o.Finding.Code, o.Finding.SuggestedFix)
+		}
+	}
+}
+
+// confirm asks a yes/no question, defaulting to no
+func confirm(reader *bufio.Reader, out io.Writer, question string) bool {
+	if reader == nil {
+		return false
+	}
+	if out != nil {
+		fmt.Fprintf(out, "%s? [y/N] ", question)
+	}
+	answer, err := reader.ReadString('\n')
+	if err != nil && answer == "" {
+		return false
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// applyCommand performs a single fix, returning whether it changed anything
+func applyCommand(basePath string, cmd *models.FixCommand) (bool, string) {
+	target, ok := resolve(basePath, cmd.Path)
+	if !ok {
+		return false, "path is outside the project"
+	}
+
+	switch cmd.Action {
+	case models.FixCopyFile:
+		source, ok := resolve(basePath, cmd.Source)
+		if !ok {
+			return false, "source is outside the project"
+		}
+		return copyFile(source, target)
+	case models.FixCreateDir:
+		if _, err := os.Stat(target); err == nil {
+			return false, "already exists"
+		}
+		if err := os.MkdirAll(target, 0755); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	case models.FixAppendLine:
+		return appendLine(target, cmd.Content)
+	default:
+		return false, "unsupported fix"
+	}
+}
+
+// resolve joins a project-relative path and rejects escapes from basePath
+func resolve(basePath, rel string) (string, bool) {
+	if rel == "" || filepath.IsAbs(rel) {
+		return "", false
+	}
+	full := filepath.Join(basePath, rel)
+	r, err := filepath.Rel(basePath, full)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return full, true
+}
+
+// copyFile copies src to dst, refusing to overwrite an existing dst
+func copyFile(src, dst string) (bool, string) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return false, "target already exists"
+		}
+		return false, err.Error()
+	}
+	defer out.Close()
+
+	if _, err := out.Write(data); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
+
+// appendLine appends a line unless it, or its KEY= prefix, is already in
+// effect. In ignore files a later "!line" negates an earlier line, so the
+// last of the two wins.
+func appendLine(path, line string) (bool, string) {
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, err.Error()
+	}
+
+	key := strings.SplitN(line, "=", 2)[0]
+	present := false
+	for _, l := range strings.Split(string(existing), "\n") {
+		l = strings.TrimPrefix(strings.TrimSpace(l), "export ")
+		switch {
+		case l == line:
+			present = true
+		case l == "!"+line:
+			present = false
+		case strings.HasPrefix(l, key+"="):
+			return false, "already defined"
+		}
+	}
+	if present {
+		return false, "already defined"
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return false, err.Error()
+	}
+	defer f.Close()
+
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		line = "\n" + line
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return false, err.Error()
+	}
+	return true, ""
+}
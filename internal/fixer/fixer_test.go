@@ -0,0 +1,160 @@
+package fixer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestApplySafeFixes(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "devcheck-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env.example"), []byte("API_KEY=\n"), 0644); err != nil {
+		t.Fatalf("failed to create .env.example: %v", err)
+	}
+
+	findings := []*models.Finding{
+		models.NewFinding("ENV001", models.SeverityBlocking, "${DB_URL} referenced but not defined").
+			WithFix("Add DB_URL=<value> to .env file").
+			WithFixCommand(&models.FixCommand{Action: models.FixAppendLine, Path: ".env", Content: "DB_URL="}),
+		models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing").
+			WithFix("Copy .env.example to .env and fill in values").
+			WithFixCommand(&models.FixCommand{Action: models.FixCopyFile, Path: ".env", Source: ".env.example"}),
+		models.NewFinding("BUILD002", models.SeverityBlocking, "Build context directory not found for service api").
+			WithFix("Create directory api or update build.context").
+			WithFixCommand(&models.FixCommand{Action: models.FixCreateDir, Path: "api"}),
+		models.NewFinding("TOOL001", models.SeverityBlocking, "Required tool 'docker' not found").
+			WithFix("Install docker version 20.10.0 or higher"),
+	}
+
+	result := Apply(tmpDir, findings, Options{AssumeYes: true})
+
+	if got := result.Count(StatusApplied); got != 3 {
+		t.Errorf("expected 3 applied fixes, got %d", got)
+	}
+	if got := result.Count(StatusManual); got != 1 {
+		t.Errorf("expected 1 manual fix, got %d", got)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, ".env"))
+	if err != nil {
+		t.Fatalf("expected .env to be created: %v", err)
+	}
+	if string(content) != "API_KEY=\nDB_URL=\n" {
+		t.Errorf("unexpected .env content: %q", content)
+	}
+
+	if info, err := os.Stat(filepath.Join(tmpDir, "api")); err != nil || !info.IsDir() {
+		t.Error("expected api directory to be created")
+	}
+}
+
+func TestApplyNeverOverwrites(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "devcheck-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env.example"), []byte("A=example\n"), 0644); err != nil {
+		t.Fatalf("failed to create .env.example: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, ".env"), []byte("A=real"), 0644); err != nil {
+		t.Fatalf("failed to create .env: %v", err)
+	}
+
+	findings := []*models.Finding{
+		models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing").
+			WithFixCommand(&models.FixCommand{Action: models.FixCopyFile, Path: ".env", Source: ".env.example"}),
+		models.NewFinding("ENV002", models.SeverityWarning, ".env.example has A but .env does not").
+			WithFixCommand(&models.FixCommand{Action: models.FixAppendLine, Path: ".env", Content: "A="}),
+		models.NewFinding("BUILD002", models.SeverityBlocking, "Build context directory not found").
+			WithFixCommand(&models.FixCommand{Action: models.FixCreateDir, Path: "../outside"}),
+	}
+
+	result := Apply(tmpDir, findings, Options{AssumeYes: true})
+
+	if got := result.Count(StatusApplied); got != 0 {
+		t.Errorf("expected 0 applied fixes, got %d", got)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tmpDir, ".env"))
+	if string(content) != "A=real" {
+		t.Errorf("expected .env to be untouched, got %q", content)
+	}
+}
+
+func TestApplyPromptsForConfirmation(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "devcheck-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	findings := []*models.Finding{
+		models.NewFinding("BUILD002", models.SeverityBlocking, "Build context directory not found for service api").
+			WithFixCommand(&models.FixCommand{Action: models.FixCreateDir, Path: "api"}),
+		models.NewFinding("BUILD002", models.SeverityBlocking, "Build context directory not found for service web").
+			WithFixCommand(&models.FixCommand{Action: models.FixCreateDir, Path: "web"}),
+	}
+
+	var out strings.Builder
+	result := Apply(tmpDir, findings, Options{In: strings.NewReader("y\nn\n"), Out: &out})
+
+	if got := result.Count(StatusApplied); got != 1 {
+		t.Errorf("expected 1 applied fix, got %d", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "web")); !os.IsNotExist(err) {
+		t.Error("expected declined fix not to create web directory")
+	}
+	if !strings.Contains(out.String(), "Create directory api? [y/N]") {
+		t.Errorf("expected prompt in output, got %q", out.String())
+	}
+}
+
+func TestApplyAppendLineAfterNegation(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing string
+		applied  bool
+		expected string
+	}{
+		{"negated", ".env\n!.env\n", true, ".env\n!.env\n.env\n"},
+		{"negation undone", "!.env\n.env\n", false, "!.env\n.env\n"},
+		{"only negated", "node_modules\n!.env", true, "node_modules\n!.env\n.env\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			path := filepath.Join(tmpDir, ".dockerignore")
+			if err := os.WriteFile(path, []byte(tt.existing), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			findings := []*models.Finding{
+				models.NewFinding("SEC004", models.SeverityWarning, ".env may be copied into the api image").
+					WithFixCommand(&models.FixCommand{Action: models.FixAppendLine, Path: ".dockerignore", Content: ".env"}),
+			}
+			result := Apply(tmpDir, findings, Options{AssumeYes: true})
+
+			if applied := result.Count(StatusApplied) == 1; applied != tt.applied {
+				t.Errorf("expected applied=%v, got %v", tt.applied, applied)
+			}
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(content) != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, content)
+			}
+		})
+	}
+}
@@ -0,0 +1,180 @@
+// Package git provides the git integration used to scope scans to changes
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotRepository is returned when a directory is not inside a git work tree
+var ErrNotRepository = errors.New("not a git repository")
+
+// ChangedFiles returns files that differ from ref in the working tree,
+// plus untracked files, as paths relative to dir
+func ChangedFiles(dir, ref string) ([]string, error) {
+	root, err := topLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := run(dir, "diff", "--name-only", ref, "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff against %s failed: %w", ref, err)
+	}
+	untracked, err := run(dir, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed: %w", err)
+	}
+
+	return relativeTo(dir, root, append(lines(diff), lines(untracked)...))
+}
+
+// StagedFiles returns files staged in the index, as paths relative to dir.
+// Deleted files are left out since there is nothing left to report on.
+func StagedFiles(dir string) ([]string, error) {
+	root, err := topLevel(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	staged, err := run(dir, "diff", "--cached", "--name-only", "--diff-filter=d", "--")
+	if err != nil {
+		return nil, fmt.Errorf("git diff --cached failed: %w", err)
+	}
+
+	return relativeTo(dir, root, lines(staged))
+}
+
+// HooksDir returns the absolute path of the hooks directory for the
+// repository containing dir, honoring core.hooksPath and worktrees
+func HooksDir(dir string) (string, error) {
+	if _, err := topLevel(dir); err != nil {
+		return "", err
+	}
+
+	out, err := run(dir, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("git rev-parse failed: %w", err)
+	}
+
+	hooks := filepath.FromSlash(strings.TrimSpace(out))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+	return hooks, nil
+}
+
+// topLevel returns the root of the work tree containing dir
+func topLevel(dir string) (string, error) {
+	out, err := run(dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", ErrNotRepository
+	}
+	return strings.TrimSpace(out), nil
+}
+
+// relativeTo converts repo-root-relative paths to paths relative to dir
+func relativeTo(dir, root string, files []string) ([]string, error) {
+	// git reports the resolved root, so resolve dir the same way
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []string
+	for _, f := range files {
+		rel, err := filepath.Rel(resolved, filepath.Join(root, filepath.FromSlash(f)))
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		result = append(result, rel)
+	}
+	return result, nil
+}
+
+// run executes a git command in dir and returns its stdout
+func run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", errors.New(msg)
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// lines splits command output into non-empty lines
+func lines(out string) []string {
+	var result []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// LineBlame identifies the commit that last changed a line
+type LineBlame struct {
+	Commit string
+	Author string
+	Date   time.Time
+}
+
+// BlameFile returns the commit that last changed each committed line of
+// file, keyed by line number. file is relative to dir. Lines that are not
+// committed yet are left out; untracked files return an error.
+func BlameFile(dir, file string) (map[int]LineBlame, error) {
+	if _, err := topLevel(dir); err != nil {
+		return nil, err
+	}
+
+	out, err := run(dir, "blame", "--line-porcelain", "--", file)
+	if err != nil {
+		return nil, fmt.Errorf("git blame %s failed: %w", file, err)
+	}
+	return parseLinePorcelain(out), nil
+}
+
+// parseLinePorcelain parses git blame --line-porcelain output, where each
+// line is a "<commit> <orig line> <final line>" header, key-value headers
+// and the tab-prefixed line content
+func parseLinePorcelain(out string) map[int]LineBlame {
+	result := make(map[int]LineBlame)
+
+	var current LineBlame
+	var line int
+	for _, raw := range strings.Split(out, "\n") {
+		switch {
+		case strings.HasPrefix(raw, "\t"):
+			// Uncommitted lines are blamed on an all-zero commit
+			if strings.Trim(current.Commit, "0") != "" {
+				result[line] = current
+			}
+			current, line = LineBlame{}, 0
+		case strings.HasPrefix(raw, "author "):
+			current.Author = strings.TrimPrefix(raw, "author ")
+		case strings.HasPrefix(raw, "author-time "):
+			if secs, err := strconv.ParseInt(strings.TrimPrefix(raw, "author-time "), 10, 64); err == nil {
+				current.Date = time.Unix(secs, 0).UTC()
+			}
+		case current.Commit == "":
+			fields := strings.Fields(raw)
+			if len(fields) >= 3 && len(fields[0]) >= 40 {
+				current.Commit = fields[0]
+				line, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return result
+}
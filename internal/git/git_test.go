@@ -0,0 +1,139 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestParseLinePorcelain(t *testing.T) {
+	out := `3f2a9c1e5b7d4f6a8c0e2b4d6f8a0c2e4b6d8f0a 1 1 1
+author Jane Doe
+author-mail <jane@example.com>
+author-time 1700000000
+author-tz +0000
+summary Add env file
+filename .env
+	PORT=3000
+0000000000000000000000000000000000000000 2 2 1
+author Not Committed Yet
+author-time 1800000000
+filename .env
+	DEBUG=true
+`
+	lines := parseLinePorcelain(out)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 blamed line, got %d", len(lines))
+	}
+
+	b, ok := lines[1]
+	if !ok {
+		t.Fatalf("expected line 1 to be blamed")
+	}
+	if b.Commit != "3f2a9c1e5b7d4f6a8c0e2b4d6f8a0c2e4b6d8f0a" {
+		t.Errorf("unexpected commit %q", b.Commit)
+	}
+	if b.Author != "Jane Doe" {
+		t.Errorf("unexpected author %q", b.Author)
+	}
+	if !b.Date.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("unexpected date %v", b.Date)
+	}
+}
+
+func TestStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	if _, err := run(dir, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{".env.example": "A=\n", ".env": "A=1\n", "compose.yaml": "services: {}\n"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := run(dir, "add", ".env.example", "compose.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	staged, err := StagedFiles(dir)
+	if err != nil {
+		t.Fatalf("StagedFiles: %v", err)
+	}
+	sort.Strings(staged)
+	if len(staged) != 2 || staged[0] != ".env.example" || staged[1] != "compose.yaml" {
+		t.Errorf("expected .env.example and compose.yaml, got %v", staged)
+	}
+
+	if _, err := StagedFiles(t.TempDir()); err != ErrNotRepository {
+		t.Errorf("expected ErrNotRepository outside a repository, got %v", err)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+
+	dir := t.TempDir()
+	svc := filepath.Join(dir, "svc")
+	if err := os.Mkdir(svc, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range map[string]string{"compose.yaml": "services: {}\n", "svc/.env": "A=1\n", "svc/.env.example": "A=\n"} {
+		write(name, content)
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=devcheck", "-c", "user.email=devcheck@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if _, err := run(dir, args...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A modified and an untracked file in svc, and a change outside it
+	write("svc/.env", "A=2\n")
+	write("svc/new.env", "B=1\n")
+	write("compose.yaml", "services:\n  app: {}\n")
+
+	changed, err := ChangedFiles(svc, "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	sort.Strings(changed)
+	if len(changed) != 2 || changed[0] != ".env" || changed[1] != "new.env" {
+		t.Errorf("expected .env and new.env relative to svc, got %v", changed)
+	}
+
+	changed, err = ChangedFiles(dir, "HEAD")
+	if err != nil {
+		t.Fatalf("ChangedFiles: %v", err)
+	}
+	sort.Strings(changed)
+	expected := []string{"compose.yaml", filepath.Join("svc", ".env"), filepath.Join("svc", "new.env")}
+	if len(changed) != len(expected) {
+		t.Fatalf("expected %v from the root, got %v", expected, changed)
+	}
+	for i := range expected {
+		if changed[i] != expected[i] {
+			t.Errorf("expected %s, got %s", expected[i], changed[i])
+		}
+	}
+
+	if _, err := ChangedFiles(dir, "no-such-ref"); err == nil {
+		t.Error("expected an unknown ref to fail")
+	}
+}
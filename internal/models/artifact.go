@@ -4,12 +4,16 @@ package models
 type ArtifactType string
 
 const (
-	ArtifactCompose    ArtifactType = "compose"
-	ArtifactEnv        ArtifactType = "env"
-	ArtifactEnvExample ArtifactType = "env_example"
-	ArtifactManifest   ArtifactType = "manifest"
-	ArtifactReadme     ArtifactType = "readme"
-	ArtifactMakefile   ArtifactType = "makefile"
+	ArtifactCompose      ArtifactType = "compose"
+	ArtifactEnv          ArtifactType = "env"
+	ArtifactEnvExample   ArtifactType = "env_example"
+	ArtifactManifest     ArtifactType = "manifest"
+	ArtifactReadme       ArtifactType = "readme"
+	ArtifactMakefile     ArtifactType = "makefile"
+	ArtifactProcfile     ArtifactType = "procfile"
+	ArtifactDevcontainer ArtifactType = "devcontainer"
+	ArtifactJustfile     ArtifactType = "justfile"
+	ArtifactTaskfile     ArtifactType = "taskfile"
 )
 
 // Language represents detected programming language
@@ -25,6 +29,24 @@ const (
 	LangUnknown Language = "unknown"
 )
 
+// languageNames maps languages to their display names
+var languageNames = map[Language]string{
+	LangNodeJS: "Node.js",
+	LangGo:     "Go",
+	LangPython: "Python",
+	LangRust:   "Rust",
+	LangJava:   "Java",
+	LangCSharp: "C#",
+}
+
+// DisplayName returns a human-readable language name
+func (l Language) DisplayName() string {
+	if name, ok := languageNames[l]; ok {
+		return name
+	}
+	return string(l)
+}
+
 // Artifact represents a detected file or configuration
 type Artifact struct {
 	Type     ArtifactType `json:"type"`
@@ -32,18 +54,28 @@ type Artifact struct {
 	Language Language     `json:"language,omitempty"`
 	Details  string       `json:"details,omitempty"`
 	Found    bool         `json:"found"`
+	// LinkTarget is set when the file is a symlink to a missing target
+	LinkTarget string `json:"link_target,omitempty"`
+	// Services are the compose services whose build context holds a
+	// service-local env file; such files only count for those services
+	Services []string `json:"services,omitempty"`
 }
 
 // Artifacts is a collection of detected artifacts
 type Artifacts struct {
-	ComposeFiles   []Artifact `json:"compose_files"`
-	EnvFiles       []Artifact `json:"env_files"`
-	EnvExamples    []Artifact `json:"env_examples"`
-	Manifests      []Artifact `json:"manifests"`
-	Readme         *Artifact  `json:"readme,omitempty"`
-	Makefile       *Artifact  `json:"makefile,omitempty"`
-	DetectedLang   Language   `json:"detected_language,omitempty"`
-	PackageManager string     `json:"package_manager,omitempty"`
+	ComposeFiles      []Artifact `json:"compose_files"`
+	EnvFiles          []Artifact `json:"env_files"`
+	EnvExamples       []Artifact `json:"env_examples"`
+	Manifests         []Artifact `json:"manifests"`
+	Readme            *Artifact  `json:"readme,omitempty"`
+	Makefile          *Artifact  `json:"makefile,omitempty"`
+	Procfile          *Artifact  `json:"procfile,omitempty"`
+	Devcontainer      *Artifact  `json:"devcontainer,omitempty"`
+	Justfile          *Artifact  `json:"justfile,omitempty"`
+	Taskfile          *Artifact  `json:"taskfile,omitempty"`
+	DetectedLang      Language   `json:"detected_language,omitempty"`
+	DetectedLanguages []Language `json:"detected_languages,omitempty"`
+	PackageManager    string     `json:"package_manager,omitempty"`
 }
 
 // NewArtifacts creates a new empty Artifacts
@@ -66,16 +98,29 @@ func (a *Artifacts) HasCompose() bool {
 	return false
 }
 
-// HasEnv checks if any .env file was found
+// HasEnv checks if any project-wide .env file was found
 func (a *Artifacts) HasEnv() bool {
 	for _, e := range a.EnvFiles {
-		if e.Found {
+		if e.Found && len(e.Services) == 0 {
 			return true
 		}
 	}
 	return false
 }
 
+// HasProject checks if any compose, env, env example or manifest file was
+// found
+func (a *Artifacts) HasProject() bool {
+	for _, group := range [][]Artifact{a.ComposeFiles, a.EnvFiles, a.EnvExamples, a.Manifests} {
+		for _, artifact := range group {
+			if artifact.Found {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // HasEnvExample checks if any .env.example file was found
 func (a *Artifacts) HasEnvExample() bool {
 	for _, e := range a.EnvExamples {
@@ -1,5 +1,15 @@
 package models
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
 // Severity represents the impact level of a finding
 type Severity string
 
@@ -9,6 +19,56 @@ const (
 	SeverityInfo     Severity = "info"
 )
 
+// Category groups findings by the area of the project they concern
+type Category string
+
+const (
+	CategoryEnv      Category = "env"
+	CategoryCompose  Category = "compose"
+	CategorySecurity Category = "security"
+	CategoryTooling  Category = "tooling"
+	CategorySource   Category = "source"
+	CategoryHints    Category = "hints"
+	CategoryScan     Category = "scan"
+	CategoryCustom   Category = "custom"
+)
+
+// Categories lists every category in display order
+var Categories = []Category{
+	CategoryEnv, CategoryCompose, CategorySecurity, CategoryTooling,
+	CategorySource, CategoryHints, CategoryScan, CategoryCustom,
+}
+
+// codeCategories maps finding code prefixes to their category
+var codeCategories = map[string]Category{
+	"ENV":    CategoryEnv,
+	"REQ":    CategoryEnv,
+	"MAKE":   CategoryEnv,
+	"CMP":    CategoryCompose,
+	"BUILD":  CategoryCompose,
+	"DKR":    CategoryCompose,
+	"DEVC":   CategoryCompose,
+	"SEC":    CategorySecurity,
+	"TOOL":   CategoryTooling,
+	"LANG":   CategoryTooling,
+	"SRC":    CategorySource,
+	"HINT":   CategoryHints,
+	"SCAN":   CategoryScan,
+	"DET":    CategoryScan,
+	"CFG":    CategoryScan,
+	"PLUGIN": CategoryScan,
+}
+
+// CategoryForCode returns the category of a finding code from its prefix;
+// codes devcheck doesn't know, such as custom codes, are CategoryCustom
+func CategoryForCode(code string) Category {
+	prefix := strings.TrimRight(code, "0123456789")
+	if c, ok := codeCategories[prefix]; ok {
+		return c
+	}
+	return CategoryCustom
+}
+
 // SourceLocation represents a location in a file
 type SourceLocation struct {
 	File   string `json:"file"`
@@ -16,6 +76,27 @@ type SourceLocation struct {
 	Column int    `json:"column,omitempty"`
 }
 
+// FixAction identifies a machine-applicable fix operation
+type FixAction string
+
+const (
+	// FixCopyFile copies Source to Path
+	FixCopyFile FixAction = "copy_file"
+	// FixAppendLine appends Content as a new line to Path
+	FixAppendLine FixAction = "append_line"
+	// FixCreateDir creates the directory Path
+	FixCreateDir FixAction = "create_dir"
+)
+
+// FixCommand is a structured, machine-applicable form of a suggested fix.
+// Paths are relative to the scanned project root.
+type FixCommand struct {
+	Action  FixAction `json:"action"`
+	Path    string    `json:"path"`
+	Source  string    `json:"source,omitempty"`
+	Content string    `json:"content,omitempty"`
+}
+
 // Finding represents a single finding from the scan
 type Finding struct {
 	Code         string           `json:"code"`
@@ -24,6 +105,36 @@ type Finding struct {
 	Details      string           `json:"details,omitempty"`
 	Files        []SourceLocation `json:"files,omitempty"`
 	SuggestedFix string           `json:"suggested_fix,omitempty"`
+	FixCommand   *FixCommand      `json:"fix_command,omitempty"`
+	Fingerprint  string           `json:"fingerprint,omitempty"`
+	// Category is the area of the project the finding concerns, set from
+	// its code unless the check sets one
+	Category Category `json:"category,omitempty"`
+	// Section is the env file section (from a "# === Name ===" style
+	// comment) the finding belongs to, used to group related findings
+	Section string `json:"section,omitempty"`
+	// Path is the scanned path a finding without files came from, set when
+	// the reports of several paths are merged
+	Path string `json:"path,omitempty"`
+	// Blame is the commit that last changed the finding's primary line,
+	// filled in by scan --blame
+	Blame *Blame `json:"blame,omitempty"`
+}
+
+// Blame identifies the commit that last changed a line
+type Blame struct {
+	Commit string    `json:"commit"`
+	Author string    `json:"author"`
+	Date   time.Time `json:"date"`
+}
+
+// String formats the blame as "<short commit> by <author> on <date>"
+func (b *Blame) String() string {
+	commit := b.Commit
+	if len(commit) > 8 {
+		commit = commit[:8]
+	}
+	return fmt.Sprintf("%s by %s on %s", commit, b.Author, b.Date.Format("2006-01-02"))
 }
 
 // NewFinding creates a new finding
@@ -35,6 +146,25 @@ func NewFinding(code string, severity Severity, title string) *Finding {
 	}
 }
 
+// ComputeFingerprint returns a stable ID for the finding, derived from its
+// code, primary location and title, plus its scanned path when set.
+// Severity is left out so profile overrides don't change a finding's
+// identity.
+func (f *Finding) ComputeFingerprint() string {
+	var file string
+	var line int
+	if len(f.Files) > 0 {
+		file, line = filepath.ToSlash(f.Files[0].File), f.Files[0].Line
+	}
+
+	key := fmt.Sprintf("%s\x00%s\x00%d\x00%s", f.Code, file, line, f.Title)
+	if f.Path != "" {
+		key += "\x00" + filepath.ToSlash(f.Path)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
+
 // WithDetails adds details to the finding
 func (f *Finding) WithDetails(details string) *Finding {
 	f.Details = details
@@ -53,6 +183,53 @@ func (f *Finding) WithFix(fix string) *Finding {
 	return f
 }
 
+// WithSection records the section the finding belongs to
+func (f *Finding) WithSection(section string) *Finding {
+	f.Section = section
+	return f
+}
+
+// WithCategory sets the finding's category
+func (f *Finding) WithCategory(c Category) *Finding {
+	f.Category = c
+	return f
+}
+
+// WithFixCommand attaches a structured fix to the finding
+func (f *Finding) WithFixCommand(cmd *FixCommand) *Finding {
+	f.FixCommand = cmd
+	return f
+}
+
+// DedupFindings collapses findings that share a code and title into the
+// first of them, collecting every file location in order. The merged
+// finding keeps the first finding's details and fixes and the highest
+// severity among them.
+func DedupFindings(findings []*Finding) []*Finding {
+	var result []*Finding
+	index := make(map[string]*Finding)
+
+	for _, f := range findings {
+		key := f.Code + "\x00" + f.Title
+		first, ok := index[key]
+		if !ok {
+			index[key] = f
+			result = append(result, f)
+			continue
+		}
+		for _, loc := range f.Files {
+			if !slices.Contains(first.Files, loc) {
+				first.Files = append(first.Files, loc)
+			}
+		}
+		if SeverityLevel(f.Severity) > SeverityLevel(first.Severity) {
+			first.Severity = f.Severity
+		}
+	}
+
+	return result
+}
+
 // SeverityLevel returns a numeric level for severity comparison
 func SeverityLevel(s Severity) int {
 	switch s {
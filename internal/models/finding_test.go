@@ -0,0 +1,99 @@
+package models
+
+import "testing"
+
+func TestFingerprintStable(t *testing.T) {
+	build := func() *Finding {
+		return NewFinding("ENV001", SeverityBlocking, "${API_KEY} referenced but not defined").
+			WithDetails("details may change freely").
+			WithFile("compose.yaml", 12)
+	}
+
+	a, b := build(), build()
+	if a.ComputeFingerprint() != b.ComputeFingerprint() {
+		t.Fatal("expected identical findings to share a fingerprint")
+	}
+	if got := a.ComputeFingerprint(); got != "162b33e40bfd776b" {
+		t.Errorf("fingerprint changed across versions: %s", got)
+	}
+
+	b.Details = "different details"
+	b.Severity = SeverityWarning
+	if a.ComputeFingerprint() != b.ComputeFingerprint() {
+		t.Error("details and severity should not affect the fingerprint")
+	}
+
+	for name, mutate := range map[string]func(*Finding){
+		"code":  func(f *Finding) { f.Code = "ENV002" },
+		"title": func(f *Finding) { f.Title = "${OTHER} referenced but not defined" },
+		"file":  func(f *Finding) { f.Files[0].File = "docker-compose.yml" },
+		"line":  func(f *Finding) { f.Files[0].Line = 13 },
+	} {
+		changed := build()
+		mutate(changed)
+		if changed.ComputeFingerprint() == a.ComputeFingerprint() {
+			t.Errorf("changing %s should change the fingerprint", name)
+		}
+	}
+}
+
+func TestCalculateSummaryAssignsFingerprints(t *testing.T) {
+	f := NewFinding("ENV003", SeverityWarning, ".env.example exists but .env is missing")
+	report := &Report{Findings: []*Finding{f}}
+	report.CalculateSummary()
+
+	if f.Fingerprint == "" || f.Fingerprint != f.ComputeFingerprint() {
+		t.Errorf("expected fingerprint to be assigned, got %q", f.Fingerprint)
+	}
+}
+
+func TestCategoryForCode(t *testing.T) {
+	tests := []struct {
+		code     string
+		expected Category
+	}{
+		{"ENV001", CategoryEnv},
+		{"REQ001", CategoryEnv},
+		{"CMP032", CategoryCompose},
+		{"BUILD001", CategoryCompose},
+		{"SEC006", CategorySecurity},
+		{"TOOL009", CategoryTooling},
+		{"HINT005", CategoryHints},
+		{"ORG001", CategoryCustom},
+		{"CUSTOM-VAULT", CategoryCustom},
+	}
+	for _, tt := range tests {
+		if got := CategoryForCode(tt.code); got != tt.expected {
+			t.Errorf("CategoryForCode(%q) = %q, want %q", tt.code, got, tt.expected)
+		}
+	}
+}
+
+func TestDedupFindings(t *testing.T) {
+	findings := []*Finding{
+		NewFinding("ENV001", SeverityBlocking, "${API_KEY} referenced but not defined").WithFile("compose.yaml", 4),
+		NewFinding("ENV002", SeverityWarning, "API_KEY missing from .env").WithFile(".env.example", 2),
+		NewFinding("ENV001", SeverityBlocking, "${API_KEY} referenced but not defined").WithFile("compose.override.yaml", 9),
+		NewFinding("ENV001", SeverityBlocking, "${API_KEY} referenced but not defined").WithFile("compose.yaml", 4),
+		NewFinding("ENV001", SeverityBlocking, "${DB_URL} referenced but not defined").WithFile("compose.yaml", 7),
+	}
+
+	deduped := DedupFindings(findings)
+	if len(deduped) != 3 {
+		t.Fatalf("expected 3 findings, got %d", len(deduped))
+	}
+
+	expected := []SourceLocation{{File: "compose.yaml", Line: 4}, {File: "compose.override.yaml", Line: 9}}
+	files := deduped[0].Files
+	if len(files) != len(expected) {
+		t.Fatalf("expected locations %v, got %v", expected, files)
+	}
+	for i := range expected {
+		if files[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], files[i])
+		}
+	}
+	if deduped[1].Code != "ENV002" || deduped[2].Title != "${DB_URL} referenced but not defined" {
+		t.Errorf("expected other findings to keep their order, got %v, %v", deduped[1].Title, deduped[2].Title)
+	}
+}
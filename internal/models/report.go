@@ -1,5 +1,12 @@
 package models
 
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
 // ReportSummary provides aggregate counts
 type ReportSummary struct {
 	TotalFindings int `json:"total_findings"`
@@ -14,12 +21,45 @@ type Report struct {
 	Artifacts *Artifacts    `json:"artifacts"`
 	Findings  []*Finding    `json:"findings"`
 	Summary   ReportSummary `json:"summary"`
+
+	// Score is the readiness score from CalculateScore, 0 to 100
+	Score int `json:"score"`
+
+	// Omitted counts findings left out by Truncated; Summary still
+	// covers every finding
+	Omitted *ReportSummary `json:"omitted,omitempty"`
+
+	// Title and Metadata describe the report for readers of archived or
+	// shared reports, e.g. a project name and the commit that was scanned
+	Title    string            `json:"title,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// scoreWeights are the weights Score was calculated with, reused when
+	// reports are merged; nil means DefaultScoreWeights
+	scoreWeights *ScoreWeights
+}
+
+// ScoreWeights are the points each finding takes off the readiness score
+type ScoreWeights struct {
+	Blocking int
+	Warning  int
+	Info     int
 }
 
-// CalculateSummary computes summary counts from findings
+// DefaultScoreWeights penalize blocking findings heavily and warnings
+// moderately; info findings don't affect the score
+var DefaultScoreWeights = ScoreWeights{Blocking: 25, Warning: 5, Info: 0}
+
+// CalculateSummary computes summary counts from findings, assigns
+// fingerprints to findings that don't have one yet and updates the
+// readiness score, with the weights it was last calculated with or
+// DefaultScoreWeights
 func (r *Report) CalculateSummary() {
 	r.Summary = ReportSummary{}
 	for _, f := range r.Findings {
+		if f.Fingerprint == "" {
+			f.Fingerprint = f.ComputeFingerprint()
+		}
 		r.Summary.TotalFindings++
 		switch f.Severity {
 		case SeverityBlocking:
@@ -30,6 +70,31 @@ func (r *Report) CalculateSummary() {
 			r.Summary.InfoCount++
 		}
 	}
+
+	weights := DefaultScoreWeights
+	if r.scoreWeights != nil {
+		weights = *r.scoreWeights
+	}
+	r.CalculateScoreWithWeights(weights)
+}
+
+// CalculateScore sets and returns the readiness score using
+// DefaultScoreWeights
+func (r *Report) CalculateScore() int {
+	return r.CalculateScoreWithWeights(DefaultScoreWeights)
+}
+
+// CalculateScoreWithWeights sets and returns the readiness score: 100 minus
+// each summary count times its weight, floored at 0. With the default
+// weights, one blocking finding scores 75 and four score 0.
+func (r *Report) CalculateScoreWithWeights(weights ScoreWeights) int {
+	penalty := r.Summary.BlockingCount*weights.Blocking +
+		r.Summary.WarningCount*weights.Warning +
+		r.Summary.InfoCount*weights.Info
+
+	r.Score = max(0, min(100, 100-penalty))
+	r.scoreWeights = &weights
+	return r.Score
 }
 
 // HasBlocking checks if there are any blocking findings
@@ -48,3 +113,124 @@ func (r *Report) FilterBySeverity(minSeverity Severity) []*Finding {
 	}
 	return result
 }
+
+// Truncated returns a copy of the report showing at most max findings, most
+// severe first, with the rest counted in Omitted. The summary is kept, so
+// it still reflects every finding. A max of zero or less keeps everything.
+func (r *Report) Truncated(max int) *Report {
+	if max <= 0 || len(r.Findings) <= max {
+		return r
+	}
+
+	sorted := append([]*Finding{}, r.Findings...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return SeverityLevel(sorted[i].Severity) > SeverityLevel(sorted[j].Severity)
+	})
+
+	omitted := &ReportSummary{}
+	for _, f := range sorted[max:] {
+		omitted.TotalFindings++
+		switch f.Severity {
+		case SeverityBlocking:
+			omitted.BlockingCount++
+		case SeverityWarning:
+			omitted.WarningCount++
+		case SeverityInfo:
+			omitted.InfoCount++
+		}
+	}
+
+	truncated := *r
+	truncated.Findings = sorted[:max]
+	truncated.Omitted = omitted
+	return &truncated
+}
+
+// String describes the counts, e.g. "2 blocking, 1 warning, 0 info"
+func (s ReportSummary) String() string {
+	warnings := "warnings"
+	if s.WarningCount == 1 {
+		warnings = "warning"
+	}
+	return fmt.Sprintf("%d blocking, %d %s, %d info", s.BlockingCount, s.WarningCount, warnings, s.InfoCount)
+}
+
+// MergeReports combines the reports of several scanned paths into one.
+// prefixes[i] is joined onto the relative file paths of reports[i], and
+// findings without a file get prefixes[i] as their Path, so every finding
+// shows which path it came from; fix command paths are prefixed
+// the same way. Fingerprints are recomputed from the prefixed paths and
+// the summary and score cover all findings, using the first report's
+// score weights. Artifact lists are concatenated; single artifacts such as
+// the README are left out.
+func MergeReports(reports []*Report, prefixes []string) *Report {
+	merged := &Report{Artifacts: NewArtifacts(), Findings: []*Finding{}}
+
+	var paths []string
+	seenLangs := make(map[Language]bool)
+	for i, r := range reports {
+		prefix := prefixes[i]
+		paths = append(paths, r.Path)
+
+		for _, f := range r.Findings {
+			copied := *f
+			copied.Fingerprint = ""
+			copied.Files = nil
+			for _, loc := range f.Files {
+				loc.File = prefixPath(prefix, loc.File)
+				copied.Files = append(copied.Files, loc)
+			}
+			if len(copied.Files) == 0 {
+				copied.Path = prefix
+			}
+			if f.FixCommand != nil {
+				fix := *f.FixCommand
+				fix.Path = prefixPath(prefix, fix.Path)
+				if fix.Source != "" {
+					fix.Source = prefixPath(prefix, fix.Source)
+				}
+				copied.FixCommand = &fix
+			}
+			merged.Findings = append(merged.Findings, &copied)
+		}
+
+		if r.Artifacts == nil {
+			continue
+		}
+		merged.Artifacts.ComposeFiles = append(merged.Artifacts.ComposeFiles, prefixArtifacts(prefix, r.Artifacts.ComposeFiles)...)
+		merged.Artifacts.EnvFiles = append(merged.Artifacts.EnvFiles, prefixArtifacts(prefix, r.Artifacts.EnvFiles)...)
+		merged.Artifacts.EnvExamples = append(merged.Artifacts.EnvExamples, prefixArtifacts(prefix, r.Artifacts.EnvExamples)...)
+		merged.Artifacts.Manifests = append(merged.Artifacts.Manifests, prefixArtifacts(prefix, r.Artifacts.Manifests)...)
+		for _, lang := range r.Artifacts.DetectedLanguages {
+			if !seenLangs[lang] {
+				seenLangs[lang] = true
+				merged.Artifacts.DetectedLanguages = append(merged.Artifacts.DetectedLanguages, lang)
+			}
+		}
+	}
+
+	merged.Path = strings.Join(paths, ", ")
+	if len(reports) > 0 {
+		merged.scoreWeights = reports[0].scoreWeights
+	}
+	merged.CalculateSummary()
+	return merged
+}
+
+// prefixPath joins prefix onto a relative path; absolute paths are kept
+func prefixPath(prefix, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(prefix, path)
+}
+
+// prefixArtifacts returns copies of artifacts with prefixed paths
+func prefixArtifacts(prefix string, artifacts []Artifact) []Artifact {
+	prefixed := make([]Artifact, len(artifacts))
+	for i, a := range artifacts {
+		a.Path = prefixPath(prefix, a.Path)
+		prefixed[i] = a
+	}
+	return prefixed
+}
@@ -0,0 +1,136 @@
+package models
+
+import "testing"
+
+func TestReportTruncated(t *testing.T) {
+	report := &Report{
+		Findings: []*Finding{
+			NewFinding("LANG001", SeverityInfo, "Detected Go project"),
+			NewFinding("ENV003", SeverityWarning, ".env.example exists but .env is missing"),
+			NewFinding("ENV001", SeverityBlocking, "${A} referenced but not defined"),
+			NewFinding("ENV002", SeverityWarning, ".env.example has B but .env does not"),
+		},
+	}
+	report.CalculateSummary()
+
+	shown := report.Truncated(2)
+	if len(shown.Findings) != 2 || shown.Findings[0].Code != "ENV001" || shown.Findings[1].Code != "ENV003" {
+		t.Fatalf("expected ENV001 then ENV003, got %v", codes(shown.Findings))
+	}
+	if shown.Omitted == nil || shown.Omitted.TotalFindings != 2 || shown.Omitted.WarningCount != 1 || shown.Omitted.InfoCount != 1 {
+		t.Errorf("unexpected omitted counts: %+v", shown.Omitted)
+	}
+	if shown.Summary.TotalFindings != 4 {
+		t.Errorf("expected the summary to keep all 4 findings, got %d", shown.Summary.TotalFindings)
+	}
+	if len(report.Findings) != 4 || report.Omitted != nil {
+		t.Error("expected the original report to be unchanged")
+	}
+
+	if report.Truncated(0) != report || report.Truncated(10) != report {
+		t.Error("expected no truncation for 0 or a cap above the finding count")
+	}
+}
+
+func codes(findings []*Finding) []string {
+	var result []string
+	for _, f := range findings {
+		result = append(result, f.Code)
+	}
+	return result
+}
+
+func TestMergeReports(t *testing.T) {
+	a := &Report{
+		Path: "/work/service-a",
+		Findings: []*Finding{
+			NewFinding("ENV003", SeverityWarning, ".env.example exists but .env is missing").WithFile(".env.example", 0),
+		},
+	}
+	b := &Report{
+		Path: "/work/service-b",
+		Findings: []*Finding{
+			NewFinding("ENV003", SeverityWarning, ".env.example exists but .env is missing").WithFile(".env.example", 0),
+			NewFinding("ENV001", SeverityBlocking, "${DB} referenced but not defined").WithFile("compose.yaml", 4),
+			NewFinding("LANG003", SeverityInfo, "Multiple languages detected"),
+		},
+	}
+	a.CalculateSummary()
+	b.CalculateSummary()
+
+	merged := MergeReports([]*Report{a, b}, []string{"service-a", "service-b"})
+
+	if merged.Path != "/work/service-a, /work/service-b" {
+		t.Errorf("unexpected path %q", merged.Path)
+	}
+	expected := []string{"service-a/.env.example", "service-b/.env.example", "service-b/compose.yaml"}
+	if len(merged.Findings) != len(expected)+1 {
+		t.Fatalf("expected %d findings, got %d", len(expected)+1, len(merged.Findings))
+	}
+	for i, file := range expected {
+		if got := merged.Findings[i].Files[0].File; got != file {
+			t.Errorf("finding %d: expected file %q, got %q", i, file, got)
+		}
+	}
+	if project := merged.Findings[3]; len(project.Files) != 0 || project.Path != "service-b" {
+		t.Errorf("expected the project-level finding to have no files and path service-b, got %+v and %q", project.Files, project.Path)
+	}
+
+	if merged.Summary.TotalFindings != 4 || merged.Summary.BlockingCount != 1 || merged.Summary.WarningCount != 2 || merged.Summary.InfoCount != 1 {
+		t.Errorf("expected summed counts, got %+v", merged.Summary)
+	}
+	if merged.Findings[0].Fingerprint == merged.Findings[1].Fingerprint {
+		t.Error("expected the same finding in different paths to get different fingerprints")
+	}
+	if b.Findings[0].Files[0].File != ".env.example" {
+		t.Error("expected the source reports to be unchanged")
+	}
+
+	lang := func() *Report {
+		return &Report{Findings: []*Finding{NewFinding("LANG003", SeverityInfo, "Multiple languages detected")}}
+	}
+	merged = MergeReports([]*Report{lang(), lang()}, []string{"service-a", "service-b"})
+	if merged.Findings[0].Fingerprint == merged.Findings[1].Fingerprint {
+		t.Error("expected a project-level finding in different paths to get different fingerprints")
+	}
+}
+
+func TestCalculateSummaryScore(t *testing.T) {
+	report := &Report{Findings: []*Finding{NewFinding("ENV001", SeverityBlocking, "${A} referenced but not defined")}}
+	report.CalculateSummary()
+	if report.Score != 75 {
+		t.Errorf("expected CalculateSummary to score 75 with the default weights, got %d", report.Score)
+	}
+
+	report.CalculateScoreWithWeights(ScoreWeights{Blocking: 40})
+	report.Findings = append(report.Findings, NewFinding("ENV002", SeverityBlocking, "x"))
+	report.CalculateSummary()
+	if report.Score != 20 {
+		t.Errorf("expected CalculateSummary to keep the last weights and score 20, got %d", report.Score)
+	}
+}
+
+func TestCalculateScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		summary  ReportSummary
+		weights  ScoreWeights
+		expected int
+	}{
+		{"clean", ReportSummary{}, DefaultScoreWeights, 100},
+		{"info only", ReportSummary{InfoCount: 7}, DefaultScoreWeights, 100},
+		{"one blocking, two warnings", ReportSummary{BlockingCount: 1, WarningCount: 2}, DefaultScoreWeights, 65},
+		{"floored at zero", ReportSummary{BlockingCount: 5}, DefaultScoreWeights, 0},
+		{"custom weights", ReportSummary{BlockingCount: 1, WarningCount: 1, InfoCount: 2}, ScoreWeights{Blocking: 50, Warning: 10, Info: 1}, 38},
+		{"negative weights capped at 100", ReportSummary{WarningCount: 3}, ScoreWeights{Warning: -5}, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &Report{Summary: tt.summary}
+			if got := report.CalculateScoreWithWeights(tt.weights); got != tt.expected || report.Score != tt.expected {
+				t.Errorf("expected score %d, got %d (Score %d)", tt.expected, got, report.Score)
+			}
+		})
+	}
+}
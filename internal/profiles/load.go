@@ -0,0 +1,59 @@
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadFile reads a custom profile from a YAML file with the same fields as
+// Profile, e.g.:
+//
+//	name: team
+//	min_severity: warning
+//	include_info: false
+//	disabled_checks: [CMP027]
+//
+// name and min_severity are required; unknown fields are rejected.
+func LoadFile(path string) (*Profile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(profile); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	if err := profile.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return profile, nil
+}
+
+// validate checks the fields a loaded profile needs
+func (p *Profile) validate() error {
+	if p.Name == "" {
+		return errors.New("profile has no name")
+	}
+	if p.MinSeverity == "" {
+		return errors.New("profile has no min_severity (use blocking, warning or info)")
+	}
+	if models.SeverityLevel(p.MinSeverity) == 0 {
+		return fmt.Errorf("unknown min_severity %q (use blocking, warning or info)", p.MinSeverity)
+	}
+	for code, severity := range p.SeverityOverrides {
+		if models.SeverityLevel(severity) == 0 {
+			return fmt.Errorf("unknown severity %q for %s in severity_overrides (use blocking, warning or info)", severity, code)
+		}
+	}
+	return nil
+}
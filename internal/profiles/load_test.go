@@ -0,0 +1,49 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	profile, err := LoadFile(write("team.yaml", `name: team
+min_severity: warning
+enable_source_scanning: true
+disabled_checks: [CMP027]
+severity_overrides:
+  ENV003: blocking
+`))
+	if err != nil {
+		t.Fatalf("expected a valid profile, got %v", err)
+	}
+	if profile.Name != "team" || profile.MinSeverity != models.SeverityWarning || !profile.EnableSourceScanning ||
+		len(profile.DisabledChecks) != 1 || profile.SeverityOverrides["ENV003"] != models.SeverityBlocking {
+		t.Errorf("unexpected profile: %+v", profile)
+	}
+
+	invalid := map[string]string{
+		"no-name.yaml":      "min_severity: info\n",
+		"no-severity.yaml":  "name: team\n",
+		"bad-severity.yaml": "name: team\nmin_severity: critical\n",
+		"bad-override.yaml": "name: team\nmin_severity: info\nseverity_overrides:\n  ENV003: fatal\n",
+		"unknown.yaml":      "name: team\nmin_severity: info\nminseverity: info\n",
+	}
+	for name, content := range invalid {
+		if _, err := LoadFile(write(name, content)); err == nil || !strings.Contains(err.Error(), name) {
+			t.Errorf("%s: expected an error naming the file, got %v", name, err)
+		}
+	}
+}
@@ -1,22 +1,32 @@
 // Package profiles provides preset configurations for devcheck
 package profiles
 
-import "github.com/stackgen-cli/devcheck/internal/models"
+import (
+	"github.com/stackgen-cli/devcheck/internal/config"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
 
 // Profile represents a configuration profile
 type Profile struct {
-	Name        string
-	Description string
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
 	// MinSeverity filters findings to this severity or higher
-	MinSeverity models.Severity
+	MinSeverity models.Severity `yaml:"min_severity"`
 	// EnabledChecks specifies which check codes to enable (empty = all)
-	EnabledChecks []string
+	EnabledChecks []string `yaml:"enabled_checks"`
 	// DisabledChecks specifies which check codes to disable
-	DisabledChecks []string
+	DisabledChecks []string `yaml:"disabled_checks"`
 	// EnableSourceScanning enables source code env var scanning
-	EnableSourceScanning bool
+	EnableSourceScanning bool `yaml:"enable_source_scanning"`
 	// IncludeInfo includes info-level findings in output
-	IncludeInfo bool
+	IncludeInfo bool `yaml:"include_info"`
+	// SeverityOverrides changes the severity of specific check codes. They
+	// are applied before MinSeverity and IncludeInfo, so an escalated info
+	// finding survives a warning threshold.
+	SeverityOverrides map[string]models.Severity `yaml:"severity_overrides"`
+	// Starter seeds the config written by init-config --profile; nil
+	// profiles get the general example config
+	Starter *config.Config `yaml:"-"`
 }
 
 // BuiltinProfiles contains all available preset profiles
@@ -34,6 +44,15 @@ var BuiltinProfiles = map[string]*Profile{
 		MinSeverity:          models.SeverityInfo,
 		EnableSourceScanning: true,
 		IncludeInfo:          true,
+		Starter: &config.Config{
+			ToolVersions: &config.ToolVersions{
+				Docker:        "24.0.0",
+				DockerCompose: "2.20.0",
+				Go:            "1.21.0",
+				Node:          "20.0.0",
+				Python:        "3.10.0",
+			},
+		},
 	},
 	"ci": {
 		Name:                 "ci",
@@ -41,6 +60,18 @@ var BuiltinProfiles = map[string]*Profile{
 		MinSeverity:          models.SeverityWarning,
 		EnableSourceScanning: false,
 		IncludeInfo:          false,
+		SeverityOverrides: map[string]models.Severity{
+			// A fresh CI checkout without .env usually means setup is broken
+			"ENV003": models.SeverityBlocking,
+		},
+		Starter: &config.Config{
+			ToolVersions: &config.ToolVersions{
+				Docker:        "20.10.0",
+				DockerCompose: "2.0.0",
+			},
+			// Informational detections are noise in CI logs
+			IgnoreCodes: []string{"LANG001", "LANG003", "HINT001", "HINT004", "HINT005"},
+		},
 	},
 	"minimal": {
 		Name:                 "minimal",
@@ -48,6 +79,9 @@ var BuiltinProfiles = map[string]*Profile{
 		MinSeverity:          models.SeverityBlocking,
 		EnableSourceScanning: false,
 		IncludeInfo:          false,
+		Starter: &config.Config{
+			IgnoreCodes: []string{"LANG001", "LANG003", "HINT001", "HINT004", "HINT005"},
+		},
 	},
 	"full": {
 		Name:                 "full",
@@ -72,11 +106,18 @@ func List() []string {
 	return names
 }
 
-// FilterFindings filters findings based on profile settings
+// FilterFindings filters findings based on profile settings. Findings
+// whose severity is overridden are copied rather than modified in place.
 func (p *Profile) FilterFindings(findings []*models.Finding) []*models.Finding {
 	var filtered []*models.Finding
 
 	for _, f := range findings {
+		if severity, ok := p.SeverityOverrides[f.Code]; ok && severity != f.Severity {
+			overridden := *f
+			overridden.Severity = severity
+			f = &overridden
+		}
+
 		// Check severity threshold
 		if models.SeverityLevel(f.Severity) < models.SeverityLevel(p.MinSeverity) {
 			continue
@@ -109,3 +150,11 @@ func contains(slice []string, s string) bool {
 	}
 	return false
 }
+
+// StarterConfig returns the config text init-config writes for a profile
+func (p *Profile) StarterConfig() string {
+	if p.Starter == nil {
+		return config.ExampleConfig()
+	}
+	return config.StarterConfig(p.Name, p.Description, p.Starter)
+}
@@ -0,0 +1,47 @@
+package profiles
+
+import (
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestFilterFindingsSeverityOverrides(t *testing.T) {
+	profile := &Profile{
+		Name:        "team",
+		MinSeverity: models.SeverityBlocking,
+		SeverityOverrides: map[string]models.Severity{
+			"ENV003": models.SeverityBlocking,
+			"ENV001": models.SeverityInfo,
+		},
+	}
+
+	escalated := models.NewFinding("ENV003", models.SeverityInfo, "Unused variable")
+	demoted := models.NewFinding("ENV001", models.SeverityBlocking, "Undefined variable")
+	untouched := models.NewFinding("CMP001", models.SeverityBlocking, "Unknown dependency")
+
+	filtered := profile.FilterFindings([]*models.Finding{escalated, demoted, untouched})
+
+	// The escalated info finding now passes min_severity: blocking, and the
+	// demoted one no longer does
+	if len(filtered) != 2 {
+		t.Fatalf("expected 2 findings, got %d", len(filtered))
+	}
+	if filtered[0].Code != "ENV003" || filtered[0].Severity != models.SeverityBlocking {
+		t.Errorf("expected ENV003 escalated to blocking, got %s %s", filtered[0].Code, filtered[0].Severity)
+	}
+	if filtered[1] != untouched {
+		t.Error("expected findings without an override to be passed through as they are")
+	}
+
+	// Overrides apply to copies; the caller's findings keep their severity
+	if filtered[0] == escalated {
+		t.Error("expected the escalated finding to be a copy")
+	}
+	if escalated.Severity != models.SeverityInfo {
+		t.Errorf("expected the original ENV003 to stay info, got %s", escalated.Severity)
+	}
+	if demoted.Severity != models.SeverityBlocking {
+		t.Errorf("expected the original ENV001 to stay blocking, got %s", demoted.Severity)
+	}
+}
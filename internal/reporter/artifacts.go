@@ -0,0 +1,104 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// artifactGroup is one branch of the artifact tree
+type artifactGroup struct {
+	name      string
+	artifacts []models.Artifact
+}
+
+// WriteArtifactTree prints the artifacts detected in root as a tree, for
+// seeing what devcheck picked up without running checks
+func WriteArtifactTree(w io.Writer, root string, artifacts *models.Artifacts) error {
+	single := func(a *models.Artifact) []models.Artifact {
+		if a == nil {
+			return nil
+		}
+		return []models.Artifact{*a}
+	}
+
+	groups := []artifactGroup{
+		{"Compose files", artifacts.ComposeFiles},
+		{"Env files", artifacts.EnvFiles},
+		{"Env examples", artifacts.EnvExamples},
+		{"Manifests", artifacts.Manifests},
+		{"README", single(artifacts.Readme)},
+		{"Makefile", single(artifacts.Makefile)},
+		{"Procfile", single(artifacts.Procfile)},
+		{"Dev container", single(artifacts.Devcontainer)},
+		{"Justfile", single(artifacts.Justfile)},
+		{"Taskfile", single(artifacts.Taskfile)},
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, root)
+	for _, g := range groups {
+		if len(g.artifacts) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "├── %s\n", g.name)
+		for i, a := range g.artifacts {
+			branch := "├──"
+			if i == len(g.artifacts)-1 {
+				branch = "└──"
+			}
+			fmt.Fprintf(&b, "│   %s %s\n", branch, artifactLabel(a))
+		}
+	}
+	fmt.Fprintf(&b, "└── %s\n", languageLabel(artifacts))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// artifactLabel describes one artifact: its path plus details, language
+// and whether it is missing
+func artifactLabel(a models.Artifact) string {
+	label := a.Path
+	var notes []string
+	if a.Language != "" && a.Language != models.LangUnknown {
+		notes = append(notes, a.Language.DisplayName())
+	}
+	if a.Details != "" {
+		notes = append(notes, a.Details)
+	}
+	if len(a.Services) > 0 {
+		notes = append(notes, "for "+strings.Join(a.Services, ", "))
+	}
+	if a.LinkTarget != "" {
+		notes = append(notes, "broken symlink to "+a.LinkTarget)
+	} else if !a.Found {
+		notes = append(notes, "not found")
+	}
+	if len(notes) > 0 {
+		label += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return label
+}
+
+// languageLabel summarizes the detected languages and package manager
+func languageLabel(artifacts *models.Artifacts) string {
+	var names []string
+	for _, lang := range artifacts.DetectedLanguages {
+		names = append(names, lang.DisplayName())
+	}
+	if len(names) == 0 && artifacts.DetectedLang != "" {
+		names = append(names, artifacts.DetectedLang.DisplayName())
+	}
+	if len(names) == 0 {
+		names = append(names, "none detected")
+	}
+
+	label := "Language: " + strings.Join(names, ", ")
+	if artifacts.PackageManager != "" {
+		label += " (package manager: " + artifacts.PackageManager + ")"
+	}
+	return label
+}
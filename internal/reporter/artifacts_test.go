@@ -0,0 +1,38 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestWriteArtifactTree(t *testing.T) {
+	artifacts := models.NewArtifacts()
+	artifacts.ComposeFiles = []models.Artifact{{Type: models.ArtifactCompose, Path: "compose.yaml", Found: true}}
+	artifacts.EnvFiles = []models.Artifact{
+		{Type: models.ArtifactEnv, Path: ".env", Found: true},
+		{Type: models.ArtifactEnv, Path: ".env.local", LinkTarget: "../shared/.env"},
+	}
+	artifacts.Manifests = []models.Artifact{{Type: models.ArtifactManifest, Path: "go.mod", Language: models.LangGo, Found: true}}
+	artifacts.DetectedLanguages = []models.Language{models.LangGo}
+
+	var b strings.Builder
+	if err := WriteArtifactTree(&b, "/src/app", artifacts); err != nil {
+		t.Fatalf("WriteArtifactTree failed: %v", err)
+	}
+
+	expected := `/src/app
+├── Compose files
+│   └── compose.yaml
+├── Env files
+│   ├── .env
+│   └── .env.local (broken symlink to ../shared/.env)
+├── Manifests
+│   └── go.mod (Go)
+└── Language: Go
+`
+	if b.String() != expected {
+		t.Errorf("unexpected tree:\n%s\nwant:\n%s", b.String(), expected)
+	}
+}
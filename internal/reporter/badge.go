@@ -0,0 +1,85 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// Badge colors, matching shields.io's red, yellow and brightgreen
+const (
+	badgeRed    = "#e05d44"
+	badgeYellow = "#dfb317"
+	badgeGreen  = "#4c1"
+)
+
+// badgeTemplate is a flat shields.io-style badge. Its arguments are the
+// total width, label width, message width, color, then the label and
+// message text with their x positions (in tenths of a pixel, as shields
+// does, so text scales cleanly).
+const badgeTemplate = `<svg xmlns="http://www.w3.org/2000/svg" width="%[1]d" height="20" role="img" aria-label="%[5]s: %[7]s">
+  <title>%[5]s: %[7]s</title>
+  <linearGradient id="s" x2="0" y2="100%%">
+    <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
+    <stop offset="1" stop-opacity=".1"/>
+  </linearGradient>
+  <clipPath id="r">
+    <rect width="%[1]d" height="20" rx="3" fill="#fff"/>
+  </clipPath>
+  <g clip-path="url(#r)">
+    <rect width="%[2]d" height="20" fill="#555"/>
+    <rect x="%[2]d" width="%[3]d" height="20" fill="%[4]s"/>
+    <rect width="%[1]d" height="20" fill="url(#s)"/>
+  </g>
+  <g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="110">
+    <text x="%[6]d" y="140" transform="scale(.1)">%[5]s</text>
+    <text x="%[8]d" y="140" transform="scale(.1)">%[7]s</text>
+  </g>
+</svg>
+`
+
+// BadgeReporter outputs an SVG status badge for READMEs: "passing" in
+// green, or the blocking (red) or warning (yellow) count. Info findings
+// don't affect the badge.
+type BadgeReporter struct {
+	writer io.Writer
+}
+
+// NewBadgeReporter creates a new BadgeReporter
+func NewBadgeReporter(w io.Writer) *BadgeReporter {
+	return &BadgeReporter{writer: w}
+}
+
+// Report outputs the report as an SVG badge
+func (r *BadgeReporter) Report(report *models.Report) error {
+	message, fill := badgeStatus(report.Summary)
+	label := "devcheck"
+
+	labelWidth := badgeTextWidth(label)
+	messageWidth := badgeTextWidth(message)
+	_, err := fmt.Fprintf(r.writer, badgeTemplate,
+		labelWidth+messageWidth, labelWidth, messageWidth, fill,
+		label, labelWidth*5, message, labelWidth*10+messageWidth*5)
+	return err
+}
+
+// badgeStatus returns the badge message and color for a summary
+func badgeStatus(summary models.ReportSummary) (string, string) {
+	switch {
+	case summary.BlockingCount > 0:
+		return fmt.Sprintf("%d blocking", summary.BlockingCount), badgeRed
+	case summary.WarningCount == 1:
+		return "1 warning", badgeYellow
+	case summary.WarningCount > 0:
+		return fmt.Sprintf("%d warnings", summary.WarningCount), badgeYellow
+	default:
+		return "passing", badgeGreen
+	}
+}
+
+// badgeTextWidth estimates the rendered width of badge text in pixels,
+// using an average Verdana 11px character width plus padding
+func badgeTextWidth(text string) int {
+	return len(text)*7 + 10
+}
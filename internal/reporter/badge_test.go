@@ -0,0 +1,51 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestBadgeReporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []*models.Finding
+		message  string
+		color    string
+	}{
+		{"clean", nil, "passing", badgeGreen},
+		{"info only", []*models.Finding{models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project")}, "passing", badgeGreen},
+		{"warning", []*models.Finding{models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing")}, "1 warning", badgeYellow},
+		{"blocking", []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined"),
+			models.NewFinding("ENV001", models.SeverityBlocking, "${B} referenced but not defined"),
+			models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing"),
+		}, "2 blocking", badgeRed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &models.Report{Findings: tt.findings}
+			report.CalculateSummary()
+
+			var buf bytes.Buffer
+			if err := NewBadgeReporter(&buf).Report(report); err != nil {
+				t.Fatal(err)
+			}
+			svg := buf.String()
+
+			if err := xml.Unmarshal(buf.Bytes(), new(struct{})); err != nil {
+				t.Fatalf("badge is not valid XML: %v", err)
+			}
+			if !strings.Contains(svg, ">"+tt.message+"</text>") {
+				t.Errorf("expected message %q in badge:\n%s", tt.message, svg)
+			}
+			if !strings.Contains(svg, `fill="`+tt.color+`"`) {
+				t.Errorf("expected color %s in badge:\n%s", tt.color, svg)
+			}
+		})
+	}
+}
@@ -42,9 +42,7 @@ func (r *ChecklistReporter) Report(report *models.Report) error {
 	if len(blocking) > 0 {
 		fmt.Fprintln(r.writer, "## 🚫 Must Fix (Blocking)")
 		fmt.Fprintln(r.writer)
-		for _, f := range blocking {
-			r.writeChecklistItem(f)
-		}
+		r.writeChecklistItems(blocking)
 		fmt.Fprintln(r.writer)
 	}
 
@@ -52,9 +50,7 @@ func (r *ChecklistReporter) Report(report *models.Report) error {
 	if len(warnings) > 0 {
 		fmt.Fprintln(r.writer, "## ⚠️ Should Fix (Warnings)")
 		fmt.Fprintln(r.writer)
-		for _, f := range warnings {
-			r.writeChecklistItem(f)
-		}
+		r.writeChecklistItems(warnings)
 		fmt.Fprintln(r.writer)
 	}
 
@@ -70,12 +66,28 @@ func (r *ChecklistReporter) Report(report *models.Report) error {
 
 	// Summary
 	fmt.Fprintln(r.writer, "---")
+	omitted := omittedCounts(report)
 	fmt.Fprintf(r.writer, "**Total:** %d blocking, %d warnings, %d info\n",
-		len(blocking), len(warnings), len(info))
+		len(blocking)+omitted.BlockingCount, len(warnings)+omitted.WarningCount, len(info)+omitted.InfoCount)
+	if report.Omitted != nil {
+		fmt.Fprintf(r.writer, "\n_…and %d more (%s)_\n", report.Omitted.TotalFindings, report.Omitted)
+	}
 
 	return nil
 }
 
+// writeChecklistItems writes items grouped under their env file sections
+func (r *ChecklistReporter) writeChecklistItems(findings []*models.Finding) {
+	for _, g := range groupBySection(findings) {
+		if g.Section != "" {
+			fmt.Fprintf(r.writer, "### %s\n\n", g.Section)
+		}
+		for _, f := range g.Findings {
+			r.writeChecklistItem(f)
+		}
+	}
+}
+
 func (r *ChecklistReporter) writeChecklistItem(f *models.Finding) {
 	fmt.Fprintf(r.writer, "- [ ] **[%s]** %s\n", f.Code, f.Title)
 
@@ -104,7 +116,9 @@ func (r *ChecklistReporter) writeInfoItem(f *models.Finding) {
 	}
 }
 
-// GenerateShellScript generates a shell script with fix commands
+// GenerateShellScript generates a shell script with fix commands. Findings
+// with a suggested fix but no generated command are listed in a leading
+// comment so they can be handled manually.
 func GenerateShellScript(report *models.Report) string {
 	var sb strings.Builder
 
@@ -112,6 +126,20 @@ func GenerateShellScript(report *models.Report) string {
 	sb.WriteString("# Auto-generated fix script from devcheck\n")
 	sb.WriteString("# Review carefully before running!\n\n")
 
+	var manual []*models.Finding
+	for _, f := range report.Findings {
+		if f.SuggestedFix != "" && scriptCommand(f) == "" {
+			manual = append(manual, f)
+		}
+	}
+	if len(manual) > 0 {
+		sb.WriteString("# Manual steps (no command generated):\n")
+		for _, f := range manual {
+			sb.WriteString(fmt.Sprintf("#   [%s] %s\n#       Fix: %s\n", f.Code, f.Title, f.SuggestedFix))
+		}
+		sb.WriteString("\n")
+	}
+
 	sb.WriteString("set -e\n\n")
 
 	for _, f := range report.Findings {
@@ -120,53 +148,50 @@ func GenerateShellScript(report *models.Report) string {
 		}
 
 		sb.WriteString(fmt.Sprintf("# [%s] %s\n", f.Code, f.Title))
-
-		// Try to generate actual commands from common fix patterns
-		fix := f.SuggestedFix
-		switch {
-		case strings.HasPrefix(fix, "Add ") && strings.Contains(fix, "to .env"):
-			// Parse "Add VAR=<value> to .env file"
-			parts := strings.SplitN(fix, " ", 2)
-			if len(parts) >= 2 {
-				varPart := strings.TrimSuffix(strings.TrimPrefix(parts[1], " "), " to .env file")
-				varPart = strings.TrimSuffix(varPart, " file")
-				varPart = strings.Split(varPart, " to ")[0]
-				sb.WriteString(fmt.Sprintf("echo '%s' >> .env\n", varPart))
-			} else {
-				sb.WriteString(fmt.Sprintf("# TODO: %s\n", fix))
-			}
-		case strings.HasPrefix(fix, "Copy "):
-			// Parse "Copy X to Y"
-			fix = strings.TrimPrefix(fix, "Copy ")
-			parts := strings.Split(fix, " to ")
-			if len(parts) == 2 {
-				src := strings.TrimSpace(parts[0])
-				dst := strings.Split(parts[1], " and ")[0]
-				dst = strings.TrimSpace(dst)
-				sb.WriteString(fmt.Sprintf("cp %s %s\n", src, dst))
-			} else {
-				sb.WriteString(fmt.Sprintf("# TODO: %s\n", f.SuggestedFix))
-			}
-		case strings.HasPrefix(fix, "Create "):
-			// Parse "Create directory X"
-			if strings.Contains(fix, "directory") {
-				dir := strings.TrimPrefix(fix, "Create directory ")
-				dir = strings.Split(dir, " or ")[0]
-				sb.WriteString(fmt.Sprintf("mkdir -p %s\n", dir))
-			} else {
-				file := strings.TrimPrefix(fix, "Create ")
-				file = strings.Split(file, " or ")[0]
-				file = strings.Split(file, " in ")[0]
-				sb.WriteString(fmt.Sprintf("touch %s\n", file))
-			}
-		case strings.HasPrefix(fix, "Install "):
-			sb.WriteString(fmt.Sprintf("# TODO: %s\n", fix))
-		default:
-			sb.WriteString(fmt.Sprintf("# TODO: %s\n", fix))
+		if cmd := scriptCommand(f); cmd != "" {
+			sb.WriteString(cmd + "\n")
+		} else {
+			sb.WriteString(fmt.Sprintf("# TODO: %s\n", f.SuggestedFix))
 		}
-
 		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
+
+// scriptCommand returns a shell command for a finding's fix, or "" if none
+// can be generated from its common fix patterns
+func scriptCommand(f *models.Finding) string {
+	fix := f.SuggestedFix
+	switch {
+	case strings.HasPrefix(fix, "Add ") && strings.Contains(fix, "to .env"):
+		// Parse "Add VAR=<value> to .env file"
+		parts := strings.SplitN(fix, " ", 2)
+		varPart := strings.TrimSuffix(strings.TrimPrefix(parts[1], " "), " to .env file")
+		varPart = strings.TrimSuffix(varPart, " file")
+		varPart = strings.Split(varPart, " to ")[0]
+		return fmt.Sprintf("echo '%s' >> .env", varPart)
+	case strings.HasPrefix(fix, "Copy "):
+		// Parse "Copy X to Y"
+		parts := strings.Split(strings.TrimPrefix(fix, "Copy "), " to ")
+		if len(parts) != 2 {
+			return ""
+		}
+		src := strings.TrimSpace(parts[0])
+		dst := strings.TrimSpace(strings.Split(parts[1], " and ")[0])
+		return fmt.Sprintf("cp %s %s", src, dst)
+	case strings.HasPrefix(fix, "Create "):
+		// Parse "Create directory X"
+		if strings.Contains(fix, "directory") {
+			dir := strings.TrimPrefix(fix, "Create directory ")
+			dir = strings.Split(dir, " or ")[0]
+			return fmt.Sprintf("mkdir -p %s", dir)
+		}
+		file := strings.TrimPrefix(fix, "Create ")
+		file = strings.Split(file, " or ")[0]
+		file = strings.Split(file, " in ")[0]
+		return fmt.Sprintf("touch %s", file)
+	default:
+		return ""
+	}
+}
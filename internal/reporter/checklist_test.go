@@ -0,0 +1,34 @@
+package reporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestGenerateShellScript(t *testing.T) {
+	report := &models.Report{
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${API_KEY} referenced but not defined").
+				WithFix("Add API_KEY=<value> to .env file"),
+			models.NewFinding("CMP001", models.SeverityBlocking, "Service web depends on unknown service cache").
+				WithFix("Add service cache to compose.yaml or remove from depends_on"),
+			models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project"),
+		},
+	}
+
+	script := GenerateShellScript(report)
+
+	if !strings.Contains(script, "echo 'API_KEY=<value>' >> .env\n") {
+		t.Errorf("expected an echo command for ENV001, got:\n%s", script)
+	}
+
+	header := script[:strings.Index(script, "set -e")]
+	if !strings.Contains(header, "#   [CMP001] Service web depends on unknown service cache\n") {
+		t.Errorf("expected CMP001 in the manual steps header, got:\n%s", header)
+	}
+	if strings.Contains(header, "ENV001") || strings.Contains(header, "LANG001") {
+		t.Errorf("expected only findings without a command in the header, got:\n%s", header)
+	}
+}
@@ -0,0 +1,119 @@
+package reporter
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// fileGroup holds the findings that point at one file
+type fileGroup struct {
+	File     string
+	Findings []*models.Finding
+}
+
+// groupByFile groups findings by the files they reference, most severe
+// first within each file. A finding with several files appears under each
+// of them. Findings without files are returned separately.
+func groupByFile(findings []*models.Finding) ([]fileGroup, []*models.Finding) {
+	byFile := make(map[string][]*models.Finding)
+	var noFile []*models.Finding
+
+	for _, f := range findings {
+		if len(f.Files) == 0 {
+			noFile = append(noFile, f)
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, loc := range f.Files {
+			if !seen[loc.File] {
+				seen[loc.File] = true
+				byFile[loc.File] = append(byFile[loc.File], f)
+			}
+		}
+	}
+
+	groups := make([]fileGroup, 0, len(byFile))
+	for file, fs := range byFile {
+		sort.SliceStable(fs, func(i, j int) bool {
+			return models.SeverityLevel(fs[i].Severity) > models.SeverityLevel(fs[j].Severity)
+		})
+		groups = append(groups, fileGroup{File: file, Findings: fs})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].File < groups[j].File
+	})
+
+	return groups, noFile
+}
+
+// categoryGroup holds the findings in one category
+type categoryGroup struct {
+	Category models.Category
+	Findings []*models.Finding
+}
+
+// groupByCategory groups findings by category in models.Categories order,
+// most severe first within each category. Findings without a known
+// category are grouped as custom.
+func groupByCategory(findings []*models.Finding) []categoryGroup {
+	byCategory := make(map[models.Category][]*models.Finding)
+	for _, f := range findings {
+		category := f.Category
+		if !slices.Contains(models.Categories, category) {
+			category = models.CategoryCustom
+		}
+		byCategory[category] = append(byCategory[category], f)
+	}
+
+	var groups []categoryGroup
+	for _, category := range models.Categories {
+		fs := byCategory[category]
+		if len(fs) == 0 {
+			continue
+		}
+		sort.SliceStable(fs, func(i, j int) bool {
+			return models.SeverityLevel(fs[i].Severity) > models.SeverityLevel(fs[j].Severity)
+		})
+		groups = append(groups, categoryGroup{Category: category, Findings: fs})
+	}
+	return groups
+}
+
+// omittedCounts returns the findings left out by Report.Truncated, or zero
+// counts for a complete report
+func omittedCounts(report *models.Report) models.ReportSummary {
+	if report.Omitted == nil {
+		return models.ReportSummary{}
+	}
+	return *report.Omitted
+}
+
+// sectionGroup holds findings from one env file section
+type sectionGroup struct {
+	Section  string
+	Findings []*models.Finding
+}
+
+// groupBySection groups findings by Finding.Section, keeping findings
+// without a section first and sections in order of first appearance
+func groupBySection(findings []*models.Finding) []sectionGroup {
+	groups := []sectionGroup{{}}
+	index := map[string]int{"": 0}
+
+	for _, f := range findings {
+		i, ok := index[f.Section]
+		if !ok {
+			i = len(groups)
+			index[f.Section] = i
+			groups = append(groups, sectionGroup{Section: f.Section})
+		}
+		groups[i].Findings = append(groups[i].Findings, f)
+	}
+
+	if len(groups[0].Findings) == 0 {
+		groups = groups[1:]
+	}
+	return groups
+}
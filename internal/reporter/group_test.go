@@ -0,0 +1,80 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestGroupByFile(t *testing.T) {
+	shared := models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined").
+		WithFile("compose.yaml", 3).
+		WithFile("compose.override.yaml", 7).
+		WithFile("compose.yaml", 9)
+	info := models.NewFinding("CMP031", models.SeverityInfo, "Service db runs postgres without resource limits").
+		WithFile("compose.yaml", 12)
+	override := models.NewFinding("CMP030", models.SeverityWarning, "Service api overrides PORT").
+		WithFile("compose.override.yaml", 2)
+	project := models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing")
+
+	groups, noFile := groupByFile([]*models.Finding{info, shared, override, project})
+
+	// Files are sorted, and a finding is listed once per file however many
+	// of its locations are in it
+	expected := []struct {
+		file  string
+		codes []string
+	}{
+		{"compose.override.yaml", []string{"ENV001", "CMP030"}},
+		{"compose.yaml", []string{"ENV001", "CMP031"}},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %d groups, got %+v", len(expected), groups)
+	}
+	for i, e := range expected {
+		if groups[i].File != e.file {
+			t.Errorf("expected group %d for %s, got %s", i, e.file, groups[i].File)
+		}
+		var codes []string
+		for _, f := range groups[i].Findings {
+			codes = append(codes, f.Code)
+		}
+		if strings.Join(codes, ",") != strings.Join(e.codes, ",") {
+			t.Errorf("%s: expected %v, most severe first, got %v", e.file, e.codes, codes)
+		}
+	}
+
+	if len(noFile) != 1 || noFile[0] != project {
+		t.Errorf("expected only ENV003 without a file, got %v", noFile)
+	}
+}
+
+func TestMarkdownGroupByFileProjectSection(t *testing.T) {
+	report := &models.Report{
+		Path: "/project",
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined").WithFile("compose.yaml", 3),
+			models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing"),
+		},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	r := NewMarkdownReporter(&buf)
+	r.GroupByFile = true
+	if err := r.Report(report); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	file := strings.Index(out, "## `compose.yaml`")
+	project := strings.Index(out, "## Project")
+	if file < 0 || project < 0 || project < file {
+		t.Fatalf("expected a compose.yaml section followed by a Project section, got:\n%s", out)
+	}
+	if !strings.Contains(out[project:], "ENV003") || strings.Contains(out[project:], "ENV001") {
+		t.Errorf("expected only the finding without a file under Project, got:\n%s", out[project:])
+	}
+}
@@ -2,15 +2,33 @@ package reporter
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"strings"
 
 	"github.com/stackgen-cli/devcheck/internal/models"
 )
 
+// SchemaVersion is the version of the JSON report format. Bump the minor
+// version when fields are added and the major version when fields are
+// removed, renamed or change meaning.
+const SchemaVersion = "1.4"
+
 // JSONReporter outputs findings as JSON
 type JSONReporter struct {
 	writer io.Writer
 	pretty bool
+
+	// FindingsOnly writes just the findings array, without artifacts or
+	// summary, for consumers of large reports that only need findings
+	FindingsOnly bool
+}
+
+// jsonReport is the serialized report, versioned for downstream tools
+type jsonReport struct {
+	SchemaVersion string `json:"schema_version"`
+	*models.Report
 }
 
 // NewJSONReporter creates a new JSONReporter
@@ -18,6 +36,67 @@ func NewJSONReporter(w io.Writer, pretty bool) *JSONReporter {
 	return &JSONReporter{writer: w, pretty: pretty}
 }
 
+// ErrNotReport is returned by LoadReport for JSON that isn't a full
+// devcheck report, such as --json-findings-only output
+var ErrNotReport = errors.New("not a devcheck JSON report")
+
+// LoadReport reads a report written by --format json. Reports from any 1.x
+// schema load; findings from reports older than categories get theirs from
+// their code.
+func LoadReport(r io.Reader) (*models.Report, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		SchemaVersion string          `json:"schema_version"`
+		Findings      json.RawMessage `json:"findings"`
+		Score         *int            `json:"score"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Value == "array" {
+			return nil, fmt.Errorf("%w: found a findings array; save reports without --json-findings-only", ErrNotReport)
+		}
+		if errors.As(err, &typeErr) {
+			return nil, fmt.Errorf("%w: expected a JSON object, found %s", ErrNotReport, typeErr.Value)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrNotReport, err)
+	}
+	if header.SchemaVersion == "" {
+		return nil, fmt.Errorf("%w: missing schema_version", ErrNotReport)
+	}
+	if header.Findings == nil {
+		return nil, fmt.Errorf("%w: missing findings", ErrNotReport)
+	}
+	major, _, _ := strings.Cut(header.SchemaVersion, ".")
+	current, _, _ := strings.Cut(SchemaVersion, ".")
+	if major != current {
+		return nil, fmt.Errorf("unsupported report schema version %s (expected %s.x)", header.SchemaVersion, current)
+	}
+
+	decoded := jsonReport{Report: &models.Report{}}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotReport, err)
+	}
+
+	report := decoded.Report
+	if report.Artifacts == nil {
+		report.Artifacts = models.NewArtifacts()
+	}
+	for _, f := range report.Findings {
+		if f.Category == "" {
+			f.Category = models.CategoryForCode(f.Code)
+		}
+	}
+	// Reports older than schema 1.4 have no score
+	if header.Score == nil {
+		report.CalculateScore()
+	}
+	return report, nil
+}
+
 // Report outputs the report as JSON
 func (r *JSONReporter) Report(report *models.Report) error {
 	var encoder *json.Encoder
@@ -25,5 +104,12 @@ func (r *JSONReporter) Report(report *models.Report) error {
 	if r.pretty {
 		encoder.SetIndent("", "  ")
 	}
-	return encoder.Encode(report)
+	if r.FindingsOnly {
+		findings := report.Findings
+		if findings == nil {
+			findings = []*models.Finding{}
+		}
+		return encoder.Encode(findings)
+	}
+	return encoder.Encode(jsonReport{SchemaVersion: SchemaVersion, Report: report})
 }
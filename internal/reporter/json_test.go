@@ -0,0 +1,126 @@
+package reporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestJSONReporterSchemaVersion(t *testing.T) {
+	report := &models.Report{
+		Path:     "/project",
+		Findings: []*models.Finding{models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing")},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewJSONReporter(&buf, false).Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if string(decoded["schema_version"]) != `"`+SchemaVersion+`"` {
+		t.Errorf("expected schema_version %q, got %s", SchemaVersion, decoded["schema_version"])
+	}
+	for _, key := range []string{"path", "artifacts", "findings", "summary"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected top-level %q alongside schema_version", key)
+		}
+	}
+}
+
+func TestJSONReporterFindingsOnly(t *testing.T) {
+	report := &models.Report{Path: "/project"}
+
+	var buf bytes.Buffer
+	r := NewJSONReporter(&buf, false)
+	r.FindingsOnly = true
+	if err := r.Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("expected an empty array for a clean report, got %q", buf.String())
+	}
+
+	report.Findings = []*models.Finding{
+		models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing"),
+		models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project"),
+	}
+	buf.Reset()
+	if err := r.Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	var findings []models.Finding
+	if err := json.Unmarshal(buf.Bytes(), &findings); err != nil {
+		t.Fatalf("expected a JSON array of findings: %v", err)
+	}
+	if len(findings) != 2 || findings[0].Code != "ENV003" || findings[1].Code != "LANG001" {
+		t.Errorf("expected findings in report order, got %+v", findings)
+	}
+}
+
+func TestLoadReport(t *testing.T) {
+	report := &models.Report{
+		Path:      "/project",
+		Artifacts: models.NewArtifacts(),
+		Findings: []*models.Finding{
+			models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing").WithFile(".env.example", 0),
+		},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewJSONReporter(&buf, true).Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadReport(&buf)
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if loaded.Path != report.Path || loaded.Summary != report.Summary {
+		t.Errorf("expected path and summary to round-trip, got %q %+v", loaded.Path, loaded.Summary)
+	}
+	if len(loaded.Findings) != 1 || loaded.Findings[0].Fingerprint != report.Findings[0].Fingerprint {
+		t.Fatalf("expected the finding to round-trip, got %+v", loaded.Findings)
+	}
+	if loaded.Findings[0].Category != models.CategoryEnv {
+		t.Errorf("expected category %q from the code, got %q", models.CategoryEnv, loaded.Findings[0].Category)
+	}
+
+	// Reports from before schema 1.4 have no score and get the default one
+	old := `{"schema_version": "1.3", "path": "/project", "findings": [], "summary": {"total_findings": 1, "warning_count": 1}}`
+	loaded, err = LoadReport(strings.NewReader(old))
+	if err != nil {
+		t.Fatalf("LoadReport: %v", err)
+	}
+	if loaded.Score != 95 {
+		t.Errorf("expected score 95 for one warning, got %d", loaded.Score)
+	}
+}
+
+func TestLoadReportInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"findings only", `[{"code":"ENV003"}]`},
+		{"other JSON", `{"name":"package.json"}`},
+		{"not JSON", `devcheck scan: .`},
+		{"future major version", `{"schema_version":"2.0","findings":[]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadReport(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("expected an error loading %s", tt.input)
+			}
+		})
+	}
+}
@@ -3,6 +3,8 @@ package reporter
 import (
 	"fmt"
 	"io"
+	"sort"
+	"strings"
 
 	"github.com/stackgen-cli/devcheck/internal/models"
 )
@@ -10,6 +12,13 @@ import (
 // MarkdownReporter outputs findings as Markdown
 type MarkdownReporter struct {
 	writer io.Writer
+
+	// GroupByFile lists findings under per-file headings instead of by severity
+	GroupByFile bool
+
+	// GroupByCategory lists findings under per-category headings instead
+	// of by severity
+	GroupByCategory bool
 }
 
 // NewMarkdownReporter creates a new MarkdownReporter
@@ -20,8 +29,13 @@ func NewMarkdownReporter(w io.Writer) *MarkdownReporter {
 // Report outputs the report as Markdown
 func (r *MarkdownReporter) Report(report *models.Report) error {
 	// Header
-	fmt.Fprintf(r.writer, "# devcheck Report\n\n")
+	title := report.Title
+	if title == "" {
+		title = "devcheck Report"
+	}
+	fmt.Fprintf(r.writer, "# %s\n\n", markdownEscape(title))
 	fmt.Fprintf(r.writer, "**Path:** `%s`\n\n", report.Path)
+	r.printMetadata(report.Metadata)
 
 	// Summary
 	blocking := 0
@@ -38,58 +52,125 @@ func (r *MarkdownReporter) Report(report *models.Report) error {
 		}
 	}
 
+	// Totals include findings left out by --max-findings
+	omitted := omittedCounts(report)
+	totalBlocking := blocking + omitted.BlockingCount
+	totalWarnings := warnings + omitted.WarningCount
+	totalInfo := info + omitted.InfoCount
+
 	fmt.Fprintf(r.writer, "## Summary\n\n")
 	fmt.Fprintf(r.writer, "| Severity | Count |\n")
 	fmt.Fprintf(r.writer, "|----------|-------|\n")
-	fmt.Fprintf(r.writer, "| 🔴 Blocking | %d |\n", blocking)
-	fmt.Fprintf(r.writer, "| 🟡 Warning | %d |\n", warnings)
-	fmt.Fprintf(r.writer, "| 🔵 Info | %d |\n\n", info)
+	fmt.Fprintf(r.writer, "| 🔴 Blocking | %d |\n", totalBlocking)
+	fmt.Fprintf(r.writer, "| 🟡 Warning | %d |\n", totalWarnings)
+	fmt.Fprintf(r.writer, "| 🔵 Info | %d |\n\n", totalInfo)
+	fmt.Fprintf(r.writer, "**Readiness score:** %d/100\n\n", report.Score)
+
+	if r.GroupByFile {
+		r.printByFile(report.Findings)
+	} else if r.GroupByCategory {
+		r.printByCategory(report.Findings)
+	} else {
+		r.printBySeverity(report.Findings, blocking, warnings, info)
+	}
+
+	if report.Omitted != nil {
+		fmt.Fprintf(r.writer, "_…and %d more (%s)_\n\n", report.Omitted.TotalFindings, report.Omitted)
+	}
+
+	// Verdict
+	fmt.Fprintf(r.writer, "---\n\n")
+	if totalBlocking > 0 {
+		fmt.Fprintf(r.writer, "**❌ Project has blocking issues that must be resolved**\n")
+	} else if totalWarnings > 0 {
+		fmt.Fprintf(r.writer, "**⚠️ Project has warnings to review**\n")
+	} else {
+		fmt.Fprintf(r.writer, "**✅ Project looks ready to run**\n")
+	}
 
+	return nil
+}
+
+// printBySeverity prints blocking issues, then warnings, then info
+func (r *MarkdownReporter) printBySeverity(findings []*models.Finding, blocking, warnings, info int) {
 	// Blocking issues
 	if blocking > 0 {
 		fmt.Fprintf(r.writer, "## 🔴 Blocking Issues\n\n")
-		for _, f := range report.Findings {
-			if f.Severity == models.SeverityBlocking {
-				r.printFinding(f)
-			}
-		}
+		r.printFindings(findings, models.SeverityBlocking)
 	}
 
 	// Warnings
 	if warnings > 0 {
 		fmt.Fprintf(r.writer, "## 🟡 Warnings\n\n")
-		for _, f := range report.Findings {
-			if f.Severity == models.SeverityWarning {
-				r.printFinding(f)
-			}
-		}
+		r.printFindings(findings, models.SeverityWarning)
 	}
 
 	// Info
 	if info > 0 {
 		fmt.Fprintf(r.writer, "## 🔵 Info\n\n")
-		for _, f := range report.Findings {
-			if f.Severity == models.SeverityInfo {
-				r.printFinding(f)
-			}
+		r.printFindings(findings, models.SeverityInfo)
+	}
+}
+
+// printByFile prints one section per file, then findings without a file
+func (r *MarkdownReporter) printByFile(findings []*models.Finding) {
+	groups, noFile := groupByFile(findings)
+
+	for _, g := range groups {
+		fmt.Fprintf(r.writer, "## `%s`\n\n", g.File)
+		for _, f := range g.Findings {
+			r.printFinding(f)
 		}
 	}
 
-	// Verdict
-	fmt.Fprintf(r.writer, "---\n\n")
-	if blocking > 0 {
-		fmt.Fprintf(r.writer, "**❌ Project has blocking issues that must be resolved**\n")
-	} else if warnings > 0 {
-		fmt.Fprintf(r.writer, "**⚠️ Project has warnings to review**\n")
-	} else {
-		fmt.Fprintf(r.writer, "**✅ Project looks ready to run**\n")
+	if len(noFile) > 0 {
+		fmt.Fprintf(r.writer, "## Project\n\n")
+		for _, f := range noFile {
+			r.printFinding(f)
+		}
 	}
+}
 
-	return nil
+// printByCategory prints one section per category
+func (r *MarkdownReporter) printByCategory(findings []*models.Finding) {
+	for _, g := range groupByCategory(findings) {
+		fmt.Fprintf(r.writer, "## %s\n\n", strings.ToUpper(string(g.Category[:1]))+string(g.Category[1:]))
+		for _, f := range g.Findings {
+			r.printFinding(f)
+		}
+	}
+}
+
+// markdownMarkers match the emoji used in the summary table
+var markdownMarkers = map[models.Severity]string{
+	models.SeverityBlocking: "🔴",
+	models.SeverityWarning:  "🟡",
+	models.SeverityInfo:     "🔵",
+}
+
+// printFindings prints the findings with the given severity, keeping those
+// from the same env file section next to each other
+func (r *MarkdownReporter) printFindings(findings []*models.Finding, severity models.Severity) {
+	var matching []*models.Finding
+	for _, f := range findings {
+		if f.Severity == severity {
+			matching = append(matching, f)
+		}
+	}
+
+	for _, g := range groupBySection(matching) {
+		for _, f := range g.Findings {
+			r.printFinding(f)
+		}
+	}
 }
 
 func (r *MarkdownReporter) printFinding(f *models.Finding) {
-	fmt.Fprintf(r.writer, "### `%s` %s\n\n", f.Code, f.Title)
+	if r.GroupByFile || r.GroupByCategory {
+		fmt.Fprintf(r.writer, "### %s `%s` %s\n\n", markdownMarkers[f.Severity], f.Code, f.Title)
+	} else {
+		fmt.Fprintf(r.writer, "### `%s` %s\n\n", f.Code, f.Title)
+	}
 
 	for _, loc := range f.Files {
 		if loc.Line > 0 {
@@ -98,14 +179,54 @@ func (r *MarkdownReporter) printFinding(f *models.Finding) {
 			fmt.Fprintf(r.writer, "- **File:** `%s`\n", loc.File)
 		}
 	}
+	if len(f.Files) == 0 && f.Path != "" {
+		fmt.Fprintf(r.writer, "- **Path:** `%s`\n", f.Path)
+	}
+
+	if f.Section != "" {
+		fmt.Fprintf(r.writer, "- **Section:** %s\n", f.Section)
+	}
 
 	if f.Details != "" {
 		fmt.Fprintf(r.writer, "- **Details:** %s\n", f.Details)
 	}
 
+	if f.Blame != nil {
+		fmt.Fprintf(r.writer, "- **Last changed:** %s\n", f.Blame)
+	}
+
 	if f.SuggestedFix != "" {
 		fmt.Fprintf(r.writer, "- **Fix:** %s\n", f.SuggestedFix)
 	}
 
 	fmt.Fprintln(r.writer)
 }
+
+// markdownEscaper backslash-escapes Markdown syntax and folds line breaks,
+// so user-supplied text can't add headings, links or HTML to the report
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`, "~", `\~`,
+	"\r\n", " ", "\n", " ", "\r", " ",
+)
+
+// markdownEscape returns s as literal Markdown text
+func markdownEscape(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// printMetadata lists --meta entries sorted by key
+func (r *MarkdownReporter) printMetadata(metadata map[string]string) {
+	if len(metadata) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(r.writer, "- **%s:** %s\n", markdownEscape(key), markdownEscape(metadata[key]))
+	}
+	fmt.Fprintln(r.writer)
+}
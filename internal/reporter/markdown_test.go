@@ -0,0 +1,69 @@
+package reporter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestMarkdownTitleAndMetadata(t *testing.T) {
+	report := &models.Report{
+		Path:  "/work/app",
+		Title: "Nightly *build* <b>",
+		Metadata: map[string]string{
+			"commit": "abc123",
+			"branch": "feat/[x]_y",
+			"note":   "line one\n# Injected heading",
+		},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewMarkdownReporter(&buf).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	expected := "# Nightly \\*build\\* \\<b\\>\n\n" +
+		"**Path:** `/work/app`\n\n" +
+		"- **branch:** feat/\\[x\\]\\_y\n" +
+		"- **commit:** abc123\n" +
+		"- **note:** line one \\# Injected heading\n\n"
+	if !strings.HasPrefix(out, expected) {
+		t.Errorf("expected the report to start with:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestMarkdownDefaultTitle(t *testing.T) {
+	report := &models.Report{Path: "/work/app"}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewMarkdownReporter(&buf).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "# devcheck Report\n\n**Path:** `/work/app`\n\n## ") {
+		t.Errorf("expected the default title without metadata, got:\n%s", buf.String())
+	}
+}
+
+func TestMarkdownScore(t *testing.T) {
+	report := &models.Report{
+		Path: "/work/app",
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined"),
+			models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project"),
+		},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewMarkdownReporter(&buf).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "| 🔵 Info | 1 |\n\n**Readiness score:** 75/100\n\n") {
+		t.Errorf("expected the score after the summary table, got:\n%s", buf.String())
+	}
+}
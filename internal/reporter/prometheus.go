@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// PrometheusReporter outputs finding counts as OpenMetrics text for
+// scraping scheduled scans. Metric names are part of the public interface:
+//
+//	devcheck_scan_success                          1, or 0 if the scan was partial (SCAN001)
+//	devcheck_findings{severity}                    findings per severity
+//	devcheck_findings_by_code{code,severity}       findings per finding code
+type PrometheusReporter struct {
+	writer io.Writer
+}
+
+// NewPrometheusReporter creates a new PrometheusReporter
+func NewPrometheusReporter(w io.Writer) *PrometheusReporter {
+	return &PrometheusReporter{writer: w}
+}
+
+// Report outputs the report as OpenMetrics text
+func (r *PrometheusReporter) Report(report *models.Report) error {
+	success := 1
+	byCode := make(map[string]int)
+	codeSeverity := make(map[string]models.Severity)
+	for _, f := range report.Findings {
+		if f.Code == "SCAN001" {
+			success = 0
+		}
+		byCode[f.Code]++
+		codeSeverity[f.Code] = f.Severity
+	}
+
+	fmt.Fprintln(r.writer, "# HELP devcheck_scan_success Whether the scan ran to completion.")
+	fmt.Fprintln(r.writer, "# TYPE devcheck_scan_success gauge")
+	fmt.Fprintf(r.writer, "devcheck_scan_success %d\n", success)
+
+	fmt.Fprintln(r.writer, "# HELP devcheck_findings Findings by severity.")
+	fmt.Fprintln(r.writer, "# TYPE devcheck_findings gauge")
+	fmt.Fprintf(r.writer, "devcheck_findings{severity=\"blocking\"} %d\n", report.Summary.BlockingCount)
+	fmt.Fprintf(r.writer, "devcheck_findings{severity=\"warning\"} %d\n", report.Summary.WarningCount)
+	fmt.Fprintf(r.writer, "devcheck_findings{severity=\"info\"} %d\n", report.Summary.InfoCount)
+
+	codes := make([]string, 0, len(byCode))
+	for code := range byCode {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	fmt.Fprintln(r.writer, "# HELP devcheck_findings_by_code Findings by finding code.")
+	fmt.Fprintln(r.writer, "# TYPE devcheck_findings_by_code gauge")
+	for _, code := range codes {
+		fmt.Fprintf(r.writer, "devcheck_findings_by_code{code=%q,severity=%q} %d\n", code, codeSeverity[code], byCode[code])
+	}
+
+	_, err := fmt.Fprintln(r.writer, "# EOF")
+	return err
+}
@@ -0,0 +1,42 @@
+package reporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestPrometheusReporter(t *testing.T) {
+	report := &models.Report{
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined"),
+			models.NewFinding("ENV001", models.SeverityBlocking, "${B} referenced but not defined"),
+			models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project"),
+		},
+	}
+	report.CalculateSummary()
+
+	var buf bytes.Buffer
+	if err := NewPrometheusReporter(&buf).Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# HELP devcheck_scan_success Whether the scan ran to completion.
+# TYPE devcheck_scan_success gauge
+devcheck_scan_success 1
+# HELP devcheck_findings Findings by severity.
+# TYPE devcheck_findings gauge
+devcheck_findings{severity="blocking"} 2
+devcheck_findings{severity="warning"} 0
+devcheck_findings{severity="info"} 1
+# HELP devcheck_findings_by_code Findings by finding code.
+# TYPE devcheck_findings_by_code gauge
+devcheck_findings_by_code{code="ENV001",severity="blocking"} 2
+devcheck_findings_by_code{code="LANG001",severity="info"} 1
+# EOF
+`
+	if buf.String() != expected {
+		t.Errorf("unexpected output:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,133 @@
+package reporter
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// Quickfix is a fixable finding with the edits that fix it, for editors
+// that offer "apply fix" code actions
+type Quickfix struct {
+	Code        string         `json:"code"`
+	Title       string         `json:"title"`
+	Fingerprint string         `json:"fingerprint,omitempty"`
+	Description string         `json:"description"`
+	Edits       []QuickfixEdit `json:"edits"`
+}
+
+// Quickfix edit kinds
+const (
+	EditInsert     = "insert"
+	EditCreateFile = "create_file"
+	EditCreateDir  = "create_dir"
+)
+
+// QuickfixEdit is one change to the project. Insert edits carry a range;
+// create_file edits carry the whole file as Text. File is relative to the
+// scanned path.
+type QuickfixEdit struct {
+	Kind  string         `json:"kind"`
+	File  string         `json:"file"`
+	Range *QuickfixRange `json:"range,omitempty"`
+	Text  string         `json:"text,omitempty"`
+}
+
+// QuickfixRange is a span of a file in LSP coordinates: zero-based lines
+// and UTF-16 character offsets
+type QuickfixRange struct {
+	Start QuickfixPosition `json:"start"`
+	End   QuickfixPosition `json:"end"`
+}
+
+// QuickfixPosition is a zero-based line and UTF-16 character offset
+type QuickfixPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+// QuickfixReporter outputs the edits for every finding with a structured
+// fix as a JSON array
+type QuickfixReporter struct {
+	writer io.Writer
+}
+
+// NewQuickfixReporter creates a new QuickfixReporter
+func NewQuickfixReporter(w io.Writer) *QuickfixReporter {
+	return &QuickfixReporter{writer: w}
+}
+
+// Report outputs the quickfixes as JSON. Files are read relative to
+// report.Path to place insertions; fixes whose edits can't be computed,
+// such as a copy from a missing file, are left out.
+func (r *QuickfixReporter) Report(report *models.Report) error {
+	fixes := []Quickfix{}
+	for _, f := range report.Findings {
+		if f.FixCommand == nil {
+			continue
+		}
+		edits, ok := quickfixEdits(report.Path, f.FixCommand)
+		if !ok {
+			continue
+		}
+		fixes = append(fixes, Quickfix{
+			Code:        f.Code,
+			Title:       f.Title,
+			Fingerprint: f.Fingerprint,
+			Description: f.SuggestedFix,
+			Edits:       edits,
+		})
+	}
+
+	encoder := json.NewEncoder(r.writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(fixes)
+}
+
+// quickfixEdits converts a fix command into edits
+func quickfixEdits(root string, cmd *models.FixCommand) ([]QuickfixEdit, bool) {
+	switch cmd.Action {
+	case models.FixCreateDir:
+		return []QuickfixEdit{{Kind: EditCreateDir, File: cmd.Path}}, true
+	case models.FixCopyFile:
+		data, err := os.ReadFile(filepath.Join(root, cmd.Source))
+		if err != nil {
+			return nil, false
+		}
+		return []QuickfixEdit{{Kind: EditCreateFile, File: cmd.Path, Text: string(data)}}, true
+	case models.FixAppendLine:
+		data, err := os.ReadFile(filepath.Join(root, cmd.Path))
+		if err != nil {
+			return []QuickfixEdit{{Kind: EditCreateFile, File: cmd.Path, Text: cmd.Content + "\n"}}, true
+		}
+		end, text := appendAt(string(data), cmd.Content)
+		return []QuickfixEdit{{
+			Kind:  EditInsert,
+			File:  cmd.Path,
+			Range: &QuickfixRange{Start: end, End: end},
+			Text:  text,
+		}}, true
+	default:
+		return nil, false
+	}
+}
+
+// appendAt returns the end-of-file position of content and the text that
+// appends line there, starting a new line if the file doesn't end with one
+func appendAt(content, line string) (QuickfixPosition, string) {
+	lastNewline := strings.LastIndex(content, "\n")
+	last := content[lastNewline+1:]
+	pos := QuickfixPosition{
+		Line:      strings.Count(content, "\n"),
+		Character: len(utf16.Encode([]rune(last))),
+	}
+	if last != "" {
+		return pos, "\n" + line + "\n"
+	}
+	return pos, line + "\n"
+}
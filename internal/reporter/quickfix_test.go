@@ -0,0 +1,63 @@
+package reporter
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestQuickfixReporter(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=3000\nHOST=é"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".env.example"), []byte("PORT=\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	report := &models.Report{
+		Path: dir,
+		Findings: []*models.Finding{
+			models.NewFinding("ENV002", models.SeverityWarning, "API_KEY missing").
+				WithFixCommand(&models.FixCommand{Action: models.FixAppendLine, Path: ".env", Content: "API_KEY="}),
+			models.NewFinding("ENV003", models.SeverityWarning, ".env missing").
+				WithFixCommand(&models.FixCommand{Action: models.FixCopyFile, Path: "api/.env", Source: ".env.example"}),
+			models.NewFinding("ENV002", models.SeverityWarning, "TOKEN missing").
+				WithFixCommand(&models.FixCommand{Action: models.FixAppendLine, Path: "web/.env", Content: "TOKEN="}),
+			models.NewFinding("ENV003", models.SeverityWarning, "copy from a missing example").
+				WithFixCommand(&models.FixCommand{Action: models.FixCopyFile, Path: "db/.env", Source: "db/.env.example"}),
+			models.NewFinding("CMP001", models.SeverityBlocking, "manual fix only"),
+		},
+	}
+
+	var b strings.Builder
+	if err := NewQuickfixReporter(&b).Report(report); err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+
+	var fixes []Quickfix
+	if err := json.Unmarshal([]byte(b.String()), &fixes); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, b.String())
+	}
+	if len(fixes) != 3 {
+		t.Fatalf("expected 3 quickfixes, got %d:\n%s", len(fixes), b.String())
+	}
+
+	// .env lacks a trailing newline, so the insertion starts one
+	insert := fixes[0].Edits[0]
+	end := QuickfixPosition{Line: 1, Character: 6}
+	if insert.Kind != EditInsert || insert.Range == nil || insert.Range.Start != end || insert.Range.End != end || insert.Text != "\nAPI_KEY=\n" {
+		t.Errorf("unexpected insert edit: %+v", insert)
+	}
+
+	if copied := fixes[1].Edits[0]; copied.Kind != EditCreateFile || copied.File != "api/.env" || copied.Text != "PORT=\n" {
+		t.Errorf("unexpected copy edit: %+v", copied)
+	}
+	if created := fixes[2].Edits[0]; created.Kind != EditCreateFile || created.File != "web/.env" || created.Text != "TOKEN=\n" {
+		t.Errorf("unexpected append to a missing file: %+v", created)
+	}
+}
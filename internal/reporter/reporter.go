@@ -0,0 +1,29 @@
+// Package reporter renders scan reports in the supported output formats
+package reporter
+
+import (
+	"io"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// Reporter renders a report to its writer
+type Reporter interface {
+	Report(report *models.Report) error
+}
+
+// ScriptReporter outputs the fix script from GenerateShellScript
+type ScriptReporter struct {
+	writer io.Writer
+}
+
+// NewScriptReporter creates a new ScriptReporter
+func NewScriptReporter(w io.Writer) *ScriptReporter {
+	return &ScriptReporter{writer: w}
+}
+
+// Report outputs the report as a bash fix script
+func (r *ScriptReporter) Report(report *models.Report) error {
+	_, err := io.WriteString(r.writer, GenerateShellScript(report))
+	return err
+}
@@ -13,16 +13,35 @@ import (
 type TextReporter struct {
 	writer io.Writer
 	noColor bool
+
+	// GroupByFile lists findings under per-file headings instead of by severity
+	GroupByFile bool
+
+	// GroupByCategory lists findings under per-category headings instead
+	// of by severity
+	GroupByCategory bool
+
+	// SeverityWriters sends the findings of a severity, with their section
+	// heading, to another writer such as stderr. The summary, verdict and
+	// other severities go to the main writer.
+	SeverityWriters map[models.Severity]io.Writer
 }
 
-// NewTextReporter creates a new TextReporter
+// NewTextReporter creates a new TextReporter. noColor disables color for
+// this reporter only; otherwise the color package's setting applies.
 func NewTextReporter(w io.Writer, noColor bool) *TextReporter {
-	if noColor {
-		color.NoColor = true
-	}
 	return &TextReporter{writer: w, noColor: noColor}
 }
 
+// paint returns a color for the reporter's output, disabled with noColor
+func (r *TextReporter) paint(attrs ...color.Attribute) *color.Color {
+	c := color.New(attrs...)
+	if r.noColor {
+		c.DisableColor()
+	}
+	return c
+}
+
 // Report outputs the report as colored text
 func (r *TextReporter) Report(report *models.Report) error {
 	// Header
@@ -45,91 +64,198 @@ func (r *TextReporter) Report(report *models.Report) error {
 		}
 	}
 
+	// Totals include findings left out by --max-findings
+	omitted := omittedCounts(report)
+	totalBlocking := blocking + omitted.BlockingCount
+	totalWarnings := warnings + omitted.WarningCount
+	totalInfo := info + omitted.InfoCount
+
 	// Print summary line
-	redBold := color.New(color.FgRed, color.Bold)
-	yellowBold := color.New(color.FgYellow, color.Bold)
-	cyanBold := color.New(color.FgCyan)
-	greenBold := color.New(color.FgGreen, color.Bold)
+	redBold := r.paint(color.FgRed, color.Bold)
+	yellowBold := r.paint(color.FgYellow, color.Bold)
+	cyanBold := r.paint(color.FgCyan)
+	greenBold := r.paint(color.FgGreen, color.Bold)
 
-	if blocking > 0 {
-		redBold.Fprintf(r.writer, "BLOCKING: %d  ", blocking)
+	if totalBlocking > 0 {
+		redBold.Fprintf(r.writer, "BLOCKING: %d  ", totalBlocking)
 	}
-	if warnings > 0 {
-		yellowBold.Fprintf(r.writer, "WARNINGS: %d  ", warnings)
+	if totalWarnings > 0 {
+		yellowBold.Fprintf(r.writer, "WARNINGS: %d  ", totalWarnings)
 	}
-	if info > 0 {
-		cyanBold.Fprintf(r.writer, "INFO: %d", info)
+	if totalInfo > 0 {
+		cyanBold.Fprintf(r.writer, "INFO: %d", totalInfo)
 	}
 	fmt.Fprintln(r.writer)
+	fmt.Fprintf(r.writer, "Readiness score: %d/100\n", report.Score)
 	fmt.Fprintln(r.writer)
 
+	if r.GroupByFile {
+		r.printByFile(report.Findings)
+	} else if r.GroupByCategory {
+		r.printByCategory(report.Findings)
+	} else {
+		r.printBySeverity(report.Findings, blocking, warnings, info)
+	}
+
+	if report.Omitted != nil {
+		fmt.Fprintf(r.writer, "…and %d more (%s)\n\n", report.Omitted.TotalFindings, report.Omitted)
+	}
+
+	// Final verdict
+	fmt.Fprintln(r.writer, strings.Repeat("=", 60))
+	if totalBlocking > 0 {
+		redBold.Fprintln(r.writer, "✗ Project has blocking issues that must be resolved")
+	} else if totalWarnings > 0 {
+		yellowBold.Fprintln(r.writer, "⚠ Project has warnings to review")
+	} else {
+		greenBold.Fprintln(r.writer, "✓ Project looks ready to run")
+	}
+
+	return nil
+}
+
+// writerFor returns where findings of a severity are written
+func (r *TextReporter) writerFor(s models.Severity) io.Writer {
+	if w, ok := r.SeverityWriters[s]; ok {
+		return w
+	}
+	return r.writer
+}
+
+// printBySeverity prints blocking issues, then warnings, then info
+func (r *TextReporter) printBySeverity(findings []*models.Finding, blocking, warnings, info int) {
+	redBold := r.paint(color.FgRed, color.Bold)
+	yellowBold := r.paint(color.FgYellow, color.Bold)
+	cyanBold := r.paint(color.FgCyan)
+
 	// Print blocking issues first
 	if blocking > 0 {
-		redBold.Fprintln(r.writer, "BLOCKING ISSUES")
-		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
-		for _, f := range report.Findings {
+		w := r.writerFor(models.SeverityBlocking)
+		redBold.Fprintln(w, "BLOCKING ISSUES")
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		for _, f := range findings {
 			if f.Severity == models.SeverityBlocking {
 				r.printFinding(f, redBold)
 			}
 		}
-		fmt.Fprintln(r.writer)
+		fmt.Fprintln(w)
 	}
 
 	// Print warnings
 	if warnings > 0 {
-		yellowBold.Fprintln(r.writer, "WARNINGS")
-		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
-		for _, f := range report.Findings {
+		w := r.writerFor(models.SeverityWarning)
+		yellowBold.Fprintln(w, "WARNINGS")
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		for _, f := range findings {
 			if f.Severity == models.SeverityWarning {
 				r.printFinding(f, yellowBold)
 			}
 		}
-		fmt.Fprintln(r.writer)
+		fmt.Fprintln(w)
 	}
 
 	// Print info
 	if info > 0 {
-		cyanBold.Fprintln(r.writer, "INFO")
-		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
-		for _, f := range report.Findings {
+		w := r.writerFor(models.SeverityInfo)
+		cyanBold.Fprintln(w, "INFO")
+		fmt.Fprintln(w, strings.Repeat("-", 40))
+		for _, f := range findings {
 			if f.Severity == models.SeverityInfo {
 				r.printFinding(f, cyanBold)
 			}
 		}
-		fmt.Fprintln(r.writer)
+		fmt.Fprintln(w)
 	}
+}
 
-	// Final verdict
-	fmt.Fprintln(r.writer, strings.Repeat("=", 60))
-	if blocking > 0 {
-		redBold.Fprintln(r.writer, "✗ Project has blocking issues that must be resolved")
-	} else if warnings > 0 {
-		yellowBold.Fprintln(r.writer, "⚠ Project has warnings to review")
-	} else {
-		greenBold.Fprintln(r.writer, "✓ Project looks ready to run")
+// printByFile prints one section per file, then findings without a file
+func (r *TextReporter) printByFile(findings []*models.Finding) {
+	bold := r.paint(color.Bold)
+	groups, noFile := groupByFile(findings)
+
+	for _, g := range groups {
+		bold.Fprintln(r.writer, g.File)
+		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
+		for _, f := range g.Findings {
+			r.printFinding(f, r.severityColor(f.Severity))
+		}
 	}
 
-	return nil
+	if len(noFile) > 0 {
+		bold.Fprintln(r.writer, "PROJECT")
+		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
+		for _, f := range noFile {
+			r.printFinding(f, r.severityColor(f.Severity))
+		}
+	}
+}
+
+// printByCategory prints one section per category
+func (r *TextReporter) printByCategory(findings []*models.Finding) {
+	bold := r.paint(color.Bold)
+
+	for _, g := range groupByCategory(findings) {
+		bold.Fprintln(r.writer, strings.ToUpper(string(g.Category)))
+		fmt.Fprintln(r.writer, strings.Repeat("-", 40))
+		for _, f := range g.Findings {
+			r.printFinding(f, r.severityColor(f.Severity))
+		}
+	}
+}
+
+// severityColor returns the color used for a severity's findings
+func (r *TextReporter) severityColor(s models.Severity) *color.Color {
+	switch s {
+	case models.SeverityBlocking:
+		return r.paint(color.FgRed, color.Bold)
+	case models.SeverityWarning:
+		return r.paint(color.FgYellow, color.Bold)
+	default:
+		return r.paint(color.FgCyan)
+	}
+}
+
+// severityMarker returns the symbol shown before a finding's code
+func severityMarker(s models.Severity) string {
+	switch s {
+	case models.SeverityBlocking:
+		return "✗"
+	case models.SeverityWarning:
+		return "⚠"
+	default:
+		return "ℹ"
+	}
 }
 
 func (r *TextReporter) printFinding(f *models.Finding, c *color.Color) {
-	c.Fprintf(r.writer, "[%s] ", f.Code)
-	fmt.Fprintln(r.writer, f.Title)
+	w := r.writerFor(f.Severity)
+	if r.GroupByFile || r.GroupByCategory {
+		c.Fprintf(w, "%s ", severityMarker(f.Severity))
+	}
+	c.Fprintf(w, "[%s] ", f.Code)
+	fmt.Fprintln(w, f.Title)
 
 	for _, loc := range f.Files {
 		if loc.Line > 0 {
-			fmt.Fprintf(r.writer, "    at %s:%d\n", loc.File, loc.Line)
+			fmt.Fprintf(w, "    at %s:%d\n", loc.File, loc.Line)
 		} else {
-			fmt.Fprintf(r.writer, "    in %s\n", loc.File)
+			fmt.Fprintf(w, "    in %s\n", loc.File)
 		}
 	}
+	if len(f.Files) == 0 && f.Path != "" {
+		fmt.Fprintf(w, "    for %s\n", f.Path)
+	}
 
 	if f.Details != "" {
-		fmt.Fprintf(r.writer, "    %s\n", f.Details)
+		fmt.Fprintf(w, "    %s\n", f.Details)
+	}
+
+	if f.Blame != nil {
+		fmt.Fprintf(w, "    Last changed: %s\n", f.Blame)
 	}
 
 	if f.SuggestedFix != "" {
-		color.New(color.FgGreen).Fprintf(r.writer, "    → Fix: %s\n", f.SuggestedFix)
+		r.paint(color.FgGreen).Fprintf(w, "    → Fix: %s\n", f.SuggestedFix)
 	}
-	fmt.Fprintln(r.writer)
+	fmt.Fprintln(w)
 }
@@ -0,0 +1,104 @@
+package reporter
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+func TestTextReporterSeverityWriters(t *testing.T) {
+	report := &models.Report{
+		Path: "/project",
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined"),
+			models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing"),
+			models.NewFinding("LANG001", models.SeverityInfo, "Detected Go project"),
+		},
+	}
+	report.CalculateSummary()
+
+	for _, groupBy := range []string{"severity", "file"} {
+		t.Run(groupBy, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			r := NewTextReporter(&stdout, true)
+			r.GroupByFile = groupBy == "file"
+			r.SeverityWriters = map[models.Severity]io.Writer{models.SeverityBlocking: &stderr}
+			if err := r.Report(report); err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(stderr.String(), "[ENV001]") || strings.Contains(stdout.String(), "[ENV001]") {
+				t.Errorf("expected the blocking finding only on stderr\nstdout:\n%s\nstderr:\n%s", stdout.String(), stderr.String())
+			}
+			for _, code := range []string{"[ENV003]", "[LANG001]", "BLOCKING: 1", "blocking issues"} {
+				if !strings.Contains(stdout.String(), code) {
+					t.Errorf("expected %q on stdout, got:\n%s", code, stdout.String())
+				}
+			}
+		})
+	}
+}
+
+func TestTextReporterNoColorIsPerReporter(t *testing.T) {
+	saved := color.NoColor
+	color.NoColor = false
+	t.Cleanup(func() { color.NoColor = saved })
+
+	report := &models.Report{
+		Path:     "/project",
+		Findings: []*models.Finding{models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined")},
+	}
+	report.CalculateSummary()
+
+	var plain, colored bytes.Buffer
+	if err := NewTextReporter(&plain, true).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if color.NoColor {
+		t.Error("expected a no-color reporter to leave color.NoColor alone")
+	}
+	if err := NewTextReporter(&colored, false).Report(report); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(plain.String(), "\x1b[") {
+		t.Errorf("expected no escape codes with noColor, got %q", plain.String())
+	}
+	if !strings.Contains(colored.String(), "\x1b[") {
+		t.Error("expected a later reporter to still use color")
+	}
+}
+
+func TestTextReporterScore(t *testing.T) {
+	report := &models.Report{
+		Path: "/project",
+		Findings: []*models.Finding{
+			models.NewFinding("ENV001", models.SeverityBlocking, "${A} referenced but not defined"),
+			models.NewFinding("ENV003", models.SeverityWarning, ".env.example exists but .env is missing"),
+			models.NewFinding("ENV020", models.SeverityWarning, ".env does not end with a newline"),
+		},
+	}
+
+	// CalculateSummary alone scores with the default weights
+	report.CalculateSummary()
+	var buf bytes.Buffer
+	if err := NewTextReporter(&buf, true).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Readiness score: 65/100\n") {
+		t.Errorf("expected the default score, got:\n%s", buf.String())
+	}
+
+	report.CalculateScoreWithWeights(models.ScoreWeights{Blocking: 50, Warning: 10})
+	buf.Reset()
+	if err := NewTextReporter(&buf, true).Report(report); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Readiness score: 30/100\n") {
+		t.Errorf("expected the weighted score, got:\n%s", buf.String())
+	}
+}
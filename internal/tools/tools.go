@@ -2,11 +2,15 @@
 package tools
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // ToolInfo contains detected tool version information
@@ -28,48 +32,150 @@ type VersionCheck struct {
 	Error      string
 }
 
-// DetectTools checks for common development tools
-func DetectTools() map[string]ToolInfo {
-	tools := make(map[string]ToolInfo)
+// commandTimeout bounds each version command so a hung tool can't stall a scan
+const commandTimeout = 5 * time.Second
 
-	// Docker
-	tools["docker"] = detectTool("docker", "--version", `Docker version (\d+\.\d+\.\d+)`)
+// maxParallelDetections bounds how many version commands run at once
+const maxParallelDetections = 4
 
-	// Docker Compose (v2 style: docker compose)
-	dockerComposeV2 := detectToolWithArgs("docker", []string{"compose", "version"}, `v?(\d+\.\d+\.\d+)`)
-	if dockerComposeV2.Available {
-		dockerComposeV2.Name = "docker-compose"
-		tools["docker-compose"] = dockerComposeV2
-	} else {
+// toolSpec describes how to detect a single tool
+type toolSpec struct {
+	name   string
+	detect func() ToolInfo
+}
+
+// toolSpecs lists the tools known to DetectTools
+var toolSpecs = []toolSpec{
+	{"docker", func() ToolInfo {
+		return detectTool("docker", "--version", `Docker version (\d+\.\d+\.\d+)`)
+	}},
+	{"docker-compose", func() ToolInfo {
+		// Docker Compose (v2 style: docker compose)
+		dockerComposeV2 := detectToolWithArgs("docker", []string{"compose", "version"}, `v?(\d+\.\d+\.\d+)`)
+		if dockerComposeV2.Available {
+			dockerComposeV2.Name = "docker-compose"
+			return dockerComposeV2
+		}
 		// Fall back to docker-compose (v1)
-		tools["docker-compose"] = detectTool("docker-compose", "--version", `docker-compose version (\d+\.\d+\.\d+)`)
-	}
+		return detectTool("docker-compose", "--version", `docker-compose version (\d+\.\d+\.\d+)`)
+	}},
+	{"go", func() ToolInfo {
+		return detectTool("go", "version", `go(\d+\.\d+\.?\d*)`)
+	}},
+	{"node", func() ToolInfo {
+		return detectTool("node", "--version", `v?(\d+\.\d+\.\d+)`)
+	}},
+	{"python", func() ToolInfo {
+		info := detectTool("python3", "--version", `Python (\d+\.\d+\.\d+)`)
+		if !info.Available {
+			info = detectTool("python", "--version", `Python (\d+\.\d+\.\d+)`)
+		}
+		return info
+	}},
+	{"npm", func() ToolInfo {
+		return detectTool("npm", "--version", `(\d+\.\d+\.\d+)`)
+	}},
+	{"pnpm", func() ToolInfo {
+		return detectTool("pnpm", "--version", `(\d+\.\d+\.\d+)`)
+	}},
+	{"yarn", func() ToolInfo {
+		return detectTool("yarn", "--version", `(\d+\.\d+\.\d+)`)
+	}},
+	{"make", func() ToolInfo {
+		return detectTool("make", "--version", `GNU Make (\d+\.\d+\.?\d*)`)
+	}},
+	{"java", DetectJava},
+}
+
+// toolCache holds one detection of every tool
+type toolCache struct {
+	once  sync.Once
+	tools map[string]ToolInfo
+}
 
-	// Go
-	tools["go"] = detectTool("go", "version", `go(\d+\.\d+\.?\d*)`)
+// cache is the process-wide toolCache; ResetCache swaps in an empty one
+var cache atomic.Pointer[toolCache]
 
-	// Node
-	tools["node"] = detectTool("node", "--version", `v?(\d+\.\d+\.\d+)`)
+func init() {
+	ResetCache()
+}
 
-	// Python
-	tools["python"] = detectTool("python3", "--version", `Python (\d+\.\d+\.\d+)`)
-	if !tools["python"].Available {
-		tools["python"] = detectTool("python", "--version", `Python (\d+\.\d+\.\d+)`)
+// ResetCache discards cached tool detections so the next DetectTools call
+// runs every version command again, e.g. between runs in a watch loop
+func ResetCache() {
+	cache.Store(&toolCache{})
+}
+
+// DetectTools checks for common development tools. Tools are detected
+// once per process, so scanning several paths runs each version command
+// only once; see ResetCache.
+func DetectTools() map[string]ToolInfo {
+	c := cache.Load()
+	c.once.Do(func() {
+		c.tools = detectToolsParallel()
+	})
+
+	tools := make(map[string]ToolInfo, len(c.tools))
+	for name, info := range c.tools {
+		tools[name] = info
 	}
+	return tools
+}
 
-	// npm
-	tools["npm"] = detectTool("npm", "--version", `(\d+\.\d+\.\d+)`)
+// detectToolsParallel runs every detection concurrently, bounded by
+// maxParallelDetections
+func detectToolsParallel() map[string]ToolInfo {
+	tools := make(map[string]ToolInfo, len(toolSpecs))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxParallelDetections)
+
+	for _, spec := range toolSpecs {
+		wg.Add(1)
+		go func(spec toolSpec) {
+			defer wg.Done()
+			sem <- struct{}{}
+			info := spec.detect()
+			<-sem
+
+			mu.Lock()
+			tools[spec.name] = info
+			mu.Unlock()
+		}(spec)
+	}
 
-	// pnpm
-	tools["pnpm"] = detectTool("pnpm", "--version", `(\d+\.\d+\.\d+)`)
+	wg.Wait()
+	return tools
+}
 
-	// yarn
-	tools["yarn"] = detectTool("yarn", "--version", `(\d+\.\d+\.\d+)`)
+// Installed reports whether any of the given commands is on PATH. It only
+// looks the commands up and never runs them.
+func Installed(commands ...string) bool {
+	for _, command := range commands {
+		if _, err := exec.LookPath(command); err == nil {
+			return true
+		}
+	}
+	return false
+}
 
-	// Make
-	tools["make"] = detectTool("make", "--version", `GNU Make (\d+\.\d+\.?\d*)`)
+// DetectJava detects the installed JDK. java -version prints to stderr,
+// e.g. `openjdk version "17.0.1"` or `java version "1.8.0_292"`.
+func DetectJava() ToolInfo {
+	return detectTool("java", "-version", `version "(\d+(?:\.\d+)*)`)
+}
 
-	return tools
+// JavaMajorVersion returns the feature release of a Java version string,
+// treating legacy "1.x" numbering as x (1.8 -> 8)
+func JavaMajorVersion(v string) int {
+	parts := parseVersion(v)
+	if len(parts) == 0 {
+		return 0
+	}
+	if parts[0] == 1 && len(parts) > 1 {
+		return parts[1]
+	}
+	return parts[0]
 }
 
 // detectTool detects a tool's version
@@ -95,8 +201,15 @@ func detectToolWithArgs(command string, args []string, pattern string) ToolInfo
 	info.Available = true
 
 	// Run command to get version
-	cmd := exec.Command(command, args...)
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command, args...)
 	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		info.Error = fmt.Sprintf("timed out after %s", commandTimeout)
+		return info
+	}
 	if err != nil {
 		info.Error = fmt.Sprintf("failed to get version: %v", err)
 		return info
@@ -0,0 +1,110 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDetectToolsMatchesSerial(t *testing.T) {
+	// Fake detections, some slow, so the result doesn't depend on the
+	// tools installed on the host
+	saved := toolSpecs
+	toolSpecs = nil
+	for i := 0; i < 3*maxParallelDetections; i++ {
+		name := fmt.Sprintf("tool%d", i)
+		version := fmt.Sprintf("1.%d.0", i)
+		available := i%3 != 0
+		delay := time.Duration(i%4) * time.Millisecond
+		toolSpecs = append(toolSpecs, toolSpec{name, func() ToolInfo {
+			time.Sleep(delay)
+			if !available {
+				return ToolInfo{Name: name, Error: "not found"}
+			}
+			return ToolInfo{Name: name, Version: version, Available: true}
+		}})
+	}
+	ResetCache()
+	t.Cleanup(func() {
+		toolSpecs = saved
+		ResetCache()
+	})
+
+	parallel := DetectTools()
+	serial := detectToolsSerial()
+
+	if len(parallel) != len(toolSpecs) {
+		t.Errorf("expected %d tools, got %d", len(toolSpecs), len(parallel))
+	}
+	for _, spec := range toolSpecs {
+		if _, ok := parallel[spec.name]; !ok {
+			t.Errorf("expected tool %s in results", spec.name)
+		}
+	}
+
+	if !reflect.DeepEqual(parallel, serial) {
+		t.Errorf("parallel detection differs from serial:\n  parallel: %+v\n  serial:   %+v", parallel, serial)
+	}
+}
+
+func TestDetectToolsCached(t *testing.T) {
+	var detections int
+	saved := toolSpecs
+	toolSpecs = []toolSpec{{"fake", func() ToolInfo {
+		detections++
+		return ToolInfo{Name: "fake", Version: "1.2.3", Available: true}
+	}}}
+	ResetCache()
+	t.Cleanup(func() {
+		toolSpecs = saved
+		ResetCache()
+	})
+
+	// Two scans checking versions share one detection
+	for i := 0; i < 2; i++ {
+		checks := CheckVersions(map[string]string{"fake": "1.0.0"})
+		if len(checks) != 1 || !checks[0].Satisfied {
+			t.Fatalf("expected fake 1.2.3 to satisfy 1.0.0, got %+v", checks)
+		}
+	}
+	if detections != 1 {
+		t.Errorf("expected 1 detection across two checks, got %d", detections)
+	}
+
+	ResetCache()
+	DetectTools()
+	if detections != 2 {
+		t.Errorf("expected ResetCache to force detection again, got %d detections", detections)
+	}
+}
+
+func TestJavaMajorVersion(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected int
+	}{
+		{"17.0.1", 17},
+		{"21", 21},
+		{"1.8.0", 8},
+		{"1.8", 8},
+		{"11.0.20", 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := JavaMajorVersion(tt.version); got != tt.expected {
+				t.Errorf("JavaMajorVersion(%s) = %d, want %d", tt.version, got, tt.expected)
+			}
+		})
+	}
+}
+
+// detectToolsSerial runs every detection one after another
+func detectToolsSerial() map[string]ToolInfo {
+	tools := make(map[string]ToolInfo, len(toolSpecs))
+	for _, spec := range toolSpecs {
+		tools[spec.name] = spec.detect()
+	}
+	return tools
+}
@@ -0,0 +1,220 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+// Archives are read into memory, so entries are capped to keep a small
+// archive that decompresses to gigabytes (a zip bomb) from exhausting it.
+// Variables so tests can lower them.
+var (
+	// maxArchiveEntrySize is the largest file an archive may contain
+	maxArchiveEntrySize int64 = 64 << 20
+	// maxArchiveTotalSize caps the decompressed size of all files together
+	maxArchiveTotalSize int64 = 512 << 20
+)
+
+// archiveKinds maps supported archive suffixes to their format
+var archiveKinds = []struct {
+	suffix string
+	kind   string
+}{
+	{".tar.gz", "tgz"},
+	{".tgz", "tgz"},
+	{".tar", "tar"},
+	{".zip", "zip"},
+}
+
+// archiveKind returns the archive format for a file name, or ""
+func archiveKind(name string) string {
+	lower := strings.ToLower(name)
+	for _, k := range archiveKinds {
+		if strings.HasSuffix(lower, k.suffix) {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// IsArchive reports whether name has a supported archive extension
+// (.tar, .tar.gz, .tgz or .zip)
+func IsArchive(name string) bool {
+	return archiveKind(name) != ""
+}
+
+// OpenArchive reads an archive into memory without extracting it. When
+// every entry sits under a single top-level directory, as with GitHub
+// downloads or git archive --prefix, that directory becomes the root.
+func OpenArchive(name string) (FS, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var files map[string]*memFile
+	switch archiveKind(name) {
+	case "tgz":
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+		files, err = readTar(gz)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+	case "tar":
+		files, err = readTar(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+	case "zip":
+		files, err = readZip(data)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", name, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s is not a supported archive (.tar, .tar.gz, .tgz, .zip)", name)
+	}
+
+	fsys := newMemFS(files)
+	if top := singleTopDir(files); top != "" {
+		sub, err := fs.Sub(fsys, top)
+		if err != nil {
+			return nil, err
+		}
+		return FromFS(sub), nil
+	}
+	return FromFS(fsys), nil
+}
+
+// archivePath cleans an entry name, returning "" for names that would
+// escape the archive root
+func archivePath(name string) string {
+	p := path.Clean(strings.TrimPrefix(name, "./"))
+	if p == "." || !fs.ValidPath(p) {
+		return ""
+	}
+	return p
+}
+
+// archiveReader reads archive entries, enforcing the size caps
+type archiveReader struct {
+	total int64
+}
+
+// read reads one entry, failing once it or the archive as a whole is over
+// its cap. Declared sizes can lie, so the cap applies to the bytes read.
+func (a *archiveReader) read(name string, r io.Reader) ([]byte, error) {
+	limit := min(maxArchiveEntrySize, maxArchiveTotalSize-a.total)
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		if limit < maxArchiveEntrySize {
+			return nil, fmt.Errorf("archive expands to more than %d bytes", maxArchiveTotalSize)
+		}
+		return nil, fmt.Errorf("%s is larger than %d bytes", name, maxArchiveEntrySize)
+	}
+	a.total += int64(len(data))
+	return data, nil
+}
+
+// readTar loads regular files and directories from a tar stream.
+// Symlinks and other special entries are skipped.
+func readTar(r io.Reader) (map[string]*memFile, error) {
+	files := map[string]*memFile{}
+	tr := tar.NewReader(r)
+	var ar archiveReader
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p := archivePath(hdr.Name)
+		if p == "" {
+			continue
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			files[p] = &memFile{Mode: fs.ModeDir | fs.FileMode(hdr.Mode).Perm(), ModTime: hdr.ModTime}
+		case tar.TypeReg:
+			data, err := ar.read(p, tr)
+			if err != nil {
+				return nil, err
+			}
+			files[p] = &memFile{Data: data, Mode: fs.FileMode(hdr.Mode).Perm(), ModTime: hdr.ModTime}
+		}
+	}
+}
+
+// readZip loads regular files and directories from zip data
+func readZip(data []byte) (map[string]*memFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]*memFile{}
+	var ar archiveReader
+	for _, zf := range zr.File {
+		p := archivePath(zf.Name)
+		if p == "" {
+			continue
+		}
+
+		mode := zf.Mode()
+		if mode.IsDir() {
+			files[p] = &memFile{Mode: mode, ModTime: zf.Modified}
+			continue
+		}
+		if !mode.IsRegular() {
+			continue
+		}
+
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		content, err := ar.read(p, rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		files[p] = &memFile{Data: content, Mode: mode, ModTime: zf.Modified}
+	}
+
+	return files, nil
+}
+
+// singleTopDir returns the directory containing every entry, if there is
+// exactly one and nothing else sits at the top level
+func singleTopDir(files map[string]*memFile) string {
+	top := ""
+	for name := range files {
+		first, _, nested := strings.Cut(name, "/")
+		if !nested && !files[name].Mode.IsDir() {
+			return "" // a file at the top level
+		}
+		if top != "" && first != top {
+			return ""
+		}
+		top = first
+	}
+	return top
+}
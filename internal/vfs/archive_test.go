@@ -0,0 +1,155 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var archiveFiles = map[string]string{
+	"proj/compose.yaml": "services: {}\n",
+	"proj/.env":         "FOO=bar\n",
+	"proj/api/main.go":  "package main\n",
+}
+
+func writeTarGz(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for name, content := range archiveFiles {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Entries escaping the root must be ignored
+	if err := tw.WriteHeader(&tar.Header{Name: "../evil", Mode: 0644, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+	gz.Close()
+}
+
+func writeZip(t *testing.T, path string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for name, content := range archiveFiles {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	zw.Close()
+}
+
+func TestOpenArchive(t *testing.T) {
+	dir := t.TempDir()
+	tgz := filepath.Join(dir, "proj.tar.gz")
+	zipPath := filepath.Join(dir, "proj.zip")
+	writeTarGz(t, tgz)
+	writeZip(t, zipPath)
+
+	for _, path := range []string{tgz, zipPath} {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			fsys, err := OpenArchive(path)
+			if err != nil {
+				t.Fatalf("OpenArchive: %v", err)
+			}
+
+			// The single top-level directory becomes the root
+			data, err := fsys.ReadFile(".env")
+			if err != nil || string(data) != "FOO=bar\n" {
+				t.Errorf("ReadFile(.env) = %q, %v", data, err)
+			}
+			if _, err := fsys.Stat(filepath.Join("api", "main.go")); err != nil {
+				t.Errorf("Stat(api/main.go): %v", err)
+			}
+			if _, err := fsys.Stat(filepath.Join("..", "evil")); err == nil {
+				t.Error("expected paths outside the archive to be invalid")
+			}
+
+			var walked []string
+			err = fsys.WalkDir(".", func(name string, d os.DirEntry, err error) error {
+				if err == nil && !d.IsDir() {
+					walked = append(walked, name)
+				}
+				return err
+			})
+			if err != nil || len(walked) != len(archiveFiles) {
+				t.Errorf("WalkDir found %v, %v", walked, err)
+			}
+		})
+	}
+}
+
+func TestIsArchive(t *testing.T) {
+	for name, want := range map[string]bool{
+		"proj.tar.gz": true,
+		"proj.TGZ":    true,
+		"proj.tar":    true,
+		"proj.zip":    true,
+		"proj":        false,
+		"compose.yml": false,
+	} {
+		if got := IsArchive(name); got != want {
+			t.Errorf("IsArchive(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestOpenArchiveSizeCaps(t *testing.T) {
+	savedEntry, savedTotal := maxArchiveEntrySize, maxArchiveTotalSize
+	t.Cleanup(func() {
+		maxArchiveEntrySize, maxArchiveTotalSize = savedEntry, savedTotal
+	})
+	dir := t.TempDir()
+
+	// The test archives hold files of 8 and 13 bytes
+	maxArchiveEntrySize, maxArchiveTotalSize = 8, 1<<20
+	for _, name := range []string{"big.tar.gz", "big.zip"} {
+		path := filepath.Join(dir, name)
+		if filepath.Ext(name) == ".zip" {
+			writeZip(t, path)
+		} else {
+			writeTarGz(t, path)
+		}
+		_, err := OpenArchive(path)
+		if err == nil || !strings.Contains(err.Error(), "larger than 8 bytes") {
+			t.Errorf("%s: expected an entry over the cap to be rejected, got %v", name, err)
+		}
+	}
+
+	maxArchiveEntrySize, maxArchiveTotalSize = 1<<20, 20
+	path := filepath.Join(dir, "total.zip")
+	writeZip(t, path)
+	if _, err := OpenArchive(path); err == nil || !strings.Contains(err.Error(), "more than 20 bytes") {
+		t.Errorf("expected an archive over the total cap to be rejected, got %v", err)
+	}
+
+	maxArchiveEntrySize, maxArchiveTotalSize = 13, 34
+	path = filepath.Join(dir, "fits.tar.gz")
+	writeTarGz(t, path)
+	if _, err := OpenArchive(path); err != nil {
+		t.Errorf("expected an archive exactly at both caps to load, got %v", err)
+	}
+}
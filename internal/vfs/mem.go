@@ -0,0 +1,137 @@
+package vfs
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"time"
+)
+
+// memFile is a file or directory held in memory
+type memFile struct {
+	Data    []byte
+	Mode    fs.FileMode
+	ModTime time.Time
+}
+
+// memFS is an in-memory tree keyed by slash-separated names, such as an
+// archive's entries. Parent directories of listed files exist whether or
+// not they are listed themselves.
+type memFS struct {
+	files map[string]*memFile
+	// dirs maps every directory, listed or implied, to its sorted
+	// child names, so listing one doesn't scan every file
+	dirs map[string][]string
+}
+
+// newMemFS indexes the directories of files
+func newMemFS(files map[string]*memFile) *memFS {
+	m := &memFS{files: files, dirs: map[string][]string{".": nil}}
+	linked := make(map[string]bool)
+	for name, f := range files {
+		if !fs.ValidPath(name) {
+			continue // never opened, see Open
+		}
+		if _, ok := m.dirs[name]; !ok && f.Mode.IsDir() {
+			m.dirs[name] = nil
+		}
+		// Link name into its parent, and so on up until an ancestor
+		// that is already linked
+		for child := name; child != "." && !linked[child]; child = path.Dir(child) {
+			linked[child] = true
+			parent := path.Dir(child)
+			m.dirs[parent] = append(m.dirs[parent], path.Base(child))
+		}
+	}
+	for _, children := range m.dirs {
+		sort.Strings(children)
+	}
+	return m
+}
+
+func (m *memFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if f, ok := m.files[name]; ok && !f.Mode.IsDir() {
+		return &memHandle{info: memInfo{name: path.Base(name), file: f}, r: bytes.NewReader(f.Data)}, nil
+	}
+
+	// A directory, listed or implied by the files under it
+	children, ok := m.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	prefix := name + "/"
+	if name == "." {
+		prefix = ""
+	}
+	dir, listed := m.files[name]
+	if !listed {
+		dir = &memFile{Mode: fs.ModeDir | 0o555}
+	}
+
+	entries := make([]fs.DirEntry, len(children))
+	for i, child := range children {
+		f, ok := m.files[prefix+child]
+		if !ok {
+			f = &memFile{Mode: fs.ModeDir | 0o555}
+		}
+		entries[i] = fs.FileInfoToDirEntry(memInfo{name: child, file: f})
+	}
+
+	return &memDir{info: memInfo{name: path.Base(name), file: dir}, entries: entries}, nil
+}
+
+// memInfo describes a memFile
+type memInfo struct {
+	name string
+	file *memFile
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return int64(len(i.file.Data)) }
+func (i memInfo) Mode() fs.FileMode  { return i.file.Mode }
+func (i memInfo) ModTime() time.Time { return i.file.ModTime }
+func (i memInfo) IsDir() bool        { return i.file.Mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+// memHandle is an open memFile
+type memHandle struct {
+	info memInfo
+	r    *bytes.Reader
+}
+
+func (h *memHandle) Stat() (fs.FileInfo, error) { return h.info, nil }
+func (h *memHandle) Read(b []byte) (int, error) { return h.r.Read(b) }
+func (h *memHandle) Close() error               { return nil }
+
+// memDir is an open directory of a memFS
+type memDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *memDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *memDir) Close() error               { return nil }
+
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *memDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	n = min(n, len(rest))
+	d.offset += n
+	return rest[:n], nil
+}
@@ -0,0 +1,28 @@
+package vfs
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMemFS(t *testing.T) {
+	files := newMemFS(map[string]*memFile{
+		"compose.yaml":      {Data: []byte("services: {}\n"), Mode: 0o644},
+		"api/main.go":       {Data: []byte("package main\n"), Mode: 0o644},
+		"api/internal/x.go": {Data: []byte("package internal\n"), Mode: 0o644},
+		"docs":              {Mode: fs.ModeDir | 0o755},
+	})
+
+	// Parent directories such as api/internal exist without being listed
+	if err := fstest.TestFS(files, "compose.yaml", "api/main.go", "api/internal/x.go", "docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := fs.Stat(files, "missing"); err == nil {
+		t.Error("expected a missing name not to exist")
+	}
+	if _, err := files.Open("../evil"); err == nil {
+		t.Error("expected an invalid name to be rejected")
+	}
+}
@@ -0,0 +1,74 @@
+package vfs
+
+import (
+	"io/fs"
+	"path/filepath"
+)
+
+// WithFile returns an FS that serves data as name and defers every other
+// name to base. It adds content that isn't on disk, such as a compose file
+// read from stdin, to a project tree. The file is not listed by Glob or
+// WalkDir.
+func WithFile(base FS, name string, data []byte) FS {
+	name = filepath.Clean(name)
+	return overlayFS{
+		base: base,
+		name: name,
+		file: newMemFS(map[string]*memFile{filepath.ToSlash(name): {Data: data, Mode: 0o644}}),
+	}
+}
+
+type overlayFS struct {
+	base FS
+	name string
+	file *memFS
+}
+
+func (f overlayFS) isFile(name string) bool {
+	return filepath.Clean(name) == f.name
+}
+
+func (f overlayFS) Open(name string) (fs.File, error) {
+	if f.isFile(name) {
+		return f.file.Open(filepath.ToSlash(f.name))
+	}
+	return f.base.Open(name)
+}
+
+func (f overlayFS) ReadFile(name string) ([]byte, error) {
+	if f.isFile(name) {
+		return fs.ReadFile(f.file, filepath.ToSlash(f.name))
+	}
+	return f.base.ReadFile(name)
+}
+
+func (f overlayFS) Stat(name string) (fs.FileInfo, error) {
+	if f.isFile(name) {
+		return fs.Stat(f.file, filepath.ToSlash(f.name))
+	}
+	return f.base.Stat(name)
+}
+
+func (f overlayFS) Lstat(name string) (fs.FileInfo, error) {
+	links, ok := f.base.(linkFS)
+	if f.isFile(name) || !ok {
+		return f.Stat(name)
+	}
+	return links.Lstat(name)
+}
+
+func (f overlayFS) Readlink(name string) (string, error) {
+	links, ok := f.base.(linkFS)
+	if f.isFile(name) || !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return links.Readlink(name)
+}
+
+func (f overlayFS) Glob(pattern string) ([]string, error) {
+	return f.base.Glob(pattern)
+}
+
+func (f overlayFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return f.base.WalkDir(root, fn)
+}
@@ -0,0 +1,28 @@
+package vfs
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestWithFile(t *testing.T) {
+	base := FromFS(fstest.MapFS{
+		".env":         {Data: []byte("PORT=3000\n")},
+		"compose.yaml": {Data: []byte("services: {}\n")},
+	})
+	fsys := WithFile(base, "<stdin>", []byte("services:\n  api:\n    image: api\n"))
+
+	data, err := fsys.ReadFile("<stdin>")
+	if err != nil || string(data) != "services:\n  api:\n    image: api\n" {
+		t.Errorf("expected the stdin content, got %q, %v", data, err)
+	}
+	if info, err := fsys.Stat("<stdin>"); err != nil || info.IsDir() {
+		t.Errorf("expected <stdin> to stat as a file, got %v", err)
+	}
+	if data, err := fsys.ReadFile(".env"); err != nil || string(data) != "PORT=3000\n" {
+		t.Errorf("expected other files to come from base, got %q, %v", data, err)
+	}
+	if _, ok := BrokenLink(fsys, "<stdin>"); ok {
+		t.Error("expected <stdin> not to be a broken link")
+	}
+}
@@ -0,0 +1,195 @@
+// Package vfs provides read-only file access for scanning projects that
+// aren't plain directories on disk
+package vfs
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FS is read-only access to a project tree. Names are relative to the
+// project root and use the OS path separator, like artifact paths.
+type FS interface {
+	fs.FS
+	ReadFile(name string) ([]byte, error)
+	Stat(name string) (fs.FileInfo, error)
+	Glob(pattern string) ([]string, error)
+	// WalkDir walks the tree at root, passing names relative to the
+	// project root to fn
+	WalkDir(root string, fn fs.WalkDirFunc) error
+}
+
+// OS returns an FS for a directory on disk. Unlike fs.FS, names may be
+// absolute or reach outside root with "..", so explicit overrides and
+// include paths keep working.
+func OS(root string) FS {
+	return osFS{root: root}
+}
+
+type osFS struct {
+	root string
+}
+
+func (f osFS) resolve(name string) string {
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(f.root, name)
+}
+
+// rel converts a resolved path back to one relative to root
+func (f osFS) rel(full string) string {
+	rel, err := filepath.Rel(f.root, full)
+	if err != nil {
+		return full
+	}
+	return rel
+}
+
+func (f osFS) Open(name string) (fs.File, error) {
+	return os.Open(f.resolve(name))
+}
+
+func (f osFS) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(f.resolve(name))
+}
+
+func (f osFS) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(f.resolve(name))
+}
+
+func (f osFS) Lstat(name string) (fs.FileInfo, error) {
+	return os.Lstat(f.resolve(name))
+}
+
+func (f osFS) Readlink(name string) (string, error) {
+	return os.Readlink(f.resolve(name))
+}
+
+func (f osFS) Glob(pattern string) ([]string, error) {
+	matches, err := filepath.Glob(f.resolve(pattern))
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range matches {
+		matches[i] = f.rel(m)
+	}
+	return matches, nil
+}
+
+func (f osFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	return filepath.WalkDir(f.resolve(root), func(p string, d fs.DirEntry, err error) error {
+		return fn(f.rel(p), d, err)
+	})
+}
+
+// linkFS is implemented by file systems that can have symlinks
+type linkFS interface {
+	Lstat(name string) (fs.FileInfo, error)
+	Readlink(name string) (string, error)
+}
+
+// RelPath makes an absolute path inside basePath relative to it, so paths
+// from flags and overrides match the relative names detection and checks
+// use. Relative paths are cleaned; paths outside basePath are kept as they
+// are.
+func RelPath(basePath, path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	rel, err := filepath.Rel(basePath, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
+
+// BrokenLink reports whether name is a symlink whose target doesn't exist,
+// returning the link's target as written. File systems without symlinks,
+// like archives, never have broken links.
+func BrokenLink(fsys FS, name string) (string, bool) {
+	links, ok := fsys.(linkFS)
+	if !ok {
+		return "", false
+	}
+	info, err := links.Lstat(name)
+	if err != nil || info.Mode()&fs.ModeSymlink == 0 {
+		return "", false
+	}
+	if _, err := fsys.Stat(name); err == nil {
+		return "", false
+	}
+	target, err := links.Readlink(name)
+	if err != nil {
+		return "", false
+	}
+	return target, true
+}
+
+// FromFS adapts an fs.FS, such as an archive or fstest.MapFS. Names that
+// fs.FS can't express, like absolute paths or "..", are invalid.
+func FromFS(fsys fs.FS) FS {
+	return ioFS{fsys: fsys}
+}
+
+type ioFS struct {
+	fsys fs.FS
+}
+
+// slash converts an OS-style relative name to an fs.FS name
+func slash(op, name string) (string, error) {
+	p := path.Clean(filepath.ToSlash(name))
+	if !fs.ValidPath(p) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return p, nil
+}
+
+func (f ioFS) Open(name string) (fs.File, error) {
+	p, err := slash("open", name)
+	if err != nil {
+		return nil, err
+	}
+	return f.fsys.Open(p)
+}
+
+func (f ioFS) ReadFile(name string) ([]byte, error) {
+	p, err := slash("read", name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(f.fsys, p)
+}
+
+func (f ioFS) Stat(name string) (fs.FileInfo, error) {
+	p, err := slash("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return fs.Stat(f.fsys, p)
+}
+
+func (f ioFS) Glob(pattern string) ([]string, error) {
+	p, err := slash("glob", pattern)
+	if err != nil {
+		return nil, nil
+	}
+	matches, err := fs.Glob(f.fsys, p)
+	for i, m := range matches {
+		matches[i] = filepath.FromSlash(m)
+	}
+	return matches, err
+}
+
+func (f ioFS) WalkDir(root string, fn fs.WalkDirFunc) error {
+	p, err := slash("walk", root)
+	if err != nil {
+		return fn(root, nil, err)
+	}
+	return fs.WalkDir(f.fsys, p, func(name string, d fs.DirEntry, err error) error {
+		return fn(filepath.FromSlash(name), d, err)
+	})
+}
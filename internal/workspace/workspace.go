@@ -0,0 +1,45 @@
+// Package workspace scans the paths of a multi-path scan, such as the
+// services of a monorepo, several at a time
+package workspace
+
+import (
+	"sync"
+
+	"github.com/stackgen-cli/devcheck/internal/models"
+)
+
+// ScanFunc scans one path and returns its report
+type ScanFunc func(path string) *models.Report
+
+// ScanAll runs scan on every path, with at most concurrency scans running
+// at once, and returns the reports in the order of paths however the scans
+// are scheduled. A concurrency below 1 scans one path at a time.
+func ScanAll(paths []string, concurrency int, scan ScanFunc) []*models.Report {
+	reports := make([]*models.Report, len(paths))
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency == 1 || len(paths) < 2 {
+		for i, path := range paths {
+			reports[i] = scan(path)
+		}
+		return reports
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			// Each goroutine writes only its own slot, so reports needs no lock
+			reports[i] = scan(path)
+			<-sem
+		}(i, path)
+	}
+
+	wg.Wait()
+	return reports
+}
@@ -0,0 +1,102 @@
+package workspace
+
+import (
+	"bytes"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stackgen-cli/devcheck/internal/checker"
+	"github.com/stackgen-cli/devcheck/internal/detector"
+	"github.com/stackgen-cli/devcheck/internal/models"
+	"github.com/stackgen-cli/devcheck/internal/reporter"
+)
+
+func TestScanAllKeepsPathOrder(t *testing.T) {
+	paths := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	for _, concurrency := range []int{0, 1, 3, 8, 20} {
+		reports := ScanAll(paths, concurrency, func(path string) *models.Report {
+			// Finish in a random order
+			time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+			return &models.Report{Path: path}
+		})
+		if len(reports) != len(paths) {
+			t.Fatalf("concurrency %d: expected %d reports, got %d", concurrency, len(paths), len(reports))
+		}
+		for i, r := range reports {
+			if r.Path != paths[i] {
+				t.Errorf("concurrency %d: expected report %d for %s, got %s", concurrency, i, paths[i], r.Path)
+			}
+		}
+	}
+}
+
+func TestScanAllBoundsConcurrency(t *testing.T) {
+	paths := make([]string, 12)
+	running := make(chan struct{}, len(paths))
+	var peak int
+	peaks := make(chan int, len(paths))
+
+	ScanAll(paths, 3, func(path string) *models.Report {
+		running <- struct{}{}
+		peaks <- len(running)
+		time.Sleep(2 * time.Millisecond)
+		<-running
+		return &models.Report{}
+	})
+
+	close(peaks)
+	for n := range peaks {
+		peak = max(peak, n)
+	}
+	if peak > 3 {
+		t.Errorf("expected at most 3 scans at once, saw %d", peak)
+	}
+}
+
+func TestScanAllDeterministicReport(t *testing.T) {
+	// Every checker fixture is a workspace
+	entries, err := os.ReadDir(filepath.Join("..", "checker", "testdata"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		path, err := filepath.Abs(filepath.Join("..", "checker", "testdata", e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+
+	scan := func(path string) *models.Report {
+		artifacts := detector.Detect(path, "", nil)
+		report := &models.Report{Path: path, Artifacts: artifacts, Findings: checker.CheckWithOptions(path, artifacts, checker.Options{EnableSourceScanning: true})}
+		report.CalculateSummary()
+		report.CalculateScore()
+		return report
+	}
+	render := func(concurrency int) string {
+		reports := ScanAll(paths, concurrency, scan)
+		report := models.MergeReports(reports, paths)
+
+		var buf bytes.Buffer
+		if err := reporter.NewJSONReporter(&buf, true).Report(report); err != nil {
+			t.Fatal(err)
+		}
+		return buf.String()
+	}
+
+	serial := render(1)
+	for i := 0; i < 5; i++ {
+		if parallel := render(8); parallel != serial {
+			t.Fatalf("run %d: concurrent scan differs from serial", i+1)
+		}
+	}
+}